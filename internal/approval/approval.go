@@ -0,0 +1,210 @@
+package approval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/policy"
+	"github.com/yourorg/flickr/internal/ref"
+	"github.com/yourorg/flickr/internal/signer"
+)
+
+// Subject identifies what an approval covers: a release of an operator set,
+// on a given chain and ReleaseManager, and the digests of its artifacts
+type Subject struct {
+	ChainID        uint64
+	ReleaseManager common.Address
+	AVS            common.Address
+	OperatorSetID  uint32
+	ReleaseID      uint64
+	Digests        []string // sha256:<hex>, in on-chain artifact order
+}
+
+// NewSubject returns the subject for a release read from the ReleaseManager
+// at releaseManager on chain chainID
+func NewSubject(chainID uint64, releaseManager, avs common.Address, operatorSetID uint32, releaseID uint64, rel eth.Release) Subject {
+	digests := make([]string, 0, len(rel.Artifacts))
+	for _, artifact := range rel.Artifacts {
+		digests = append(digests, ref.Digest32ToSha256String(artifact.Digest32))
+	}
+	return Subject{
+		ChainID:        chainID,
+		ReleaseManager: releaseManager,
+		AVS:            avs,
+		OperatorSetID:  operatorSetID,
+		ReleaseID:      releaseID,
+		Digests:        digests,
+	}
+}
+
+// Message is the text signed (EIP-191) by approvers. It is readable so
+// hardware and remote signers can show what is being approved.
+func (s Subject) Message() []byte {
+	var b strings.Builder
+	b.WriteString("Flickr release approval\n")
+	fmt.Fprintf(&b, "Chain ID: %d\n", s.ChainID)
+	fmt.Fprintf(&b, "ReleaseManager: %s\n", s.ReleaseManager.Hex())
+	fmt.Fprintf(&b, "AVS: %s\n", s.AVS.Hex())
+	fmt.Fprintf(&b, "Operator set: %d\n", s.OperatorSetID)
+	fmt.Fprintf(&b, "Release ID: %d\n", s.ReleaseID)
+	for _, digest := range s.Digests {
+		fmt.Fprintf(&b, "Artifact: %s\n", digest)
+	}
+	return []byte(b.String())
+}
+
+func (s Subject) equal(other Subject) bool {
+	if s.ChainID != other.ChainID || s.ReleaseManager != other.ReleaseManager ||
+		s.AVS != other.AVS || s.OperatorSetID != other.OperatorSetID || s.ReleaseID != other.ReleaseID ||
+		len(s.Digests) != len(other.Digests) {
+		return false
+	}
+	for i := range s.Digests {
+		if s.Digests[i] != other.Digests[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Approval is a maintainer's signed approval of a release
+type Approval struct {
+	ChainID        uint64   `json:"chainId"`
+	ReleaseManager string   `json:"releaseManager"`
+	AVS            string   `json:"avs"`
+	OperatorSetID  uint32   `json:"operatorSetId"`
+	ReleaseID      uint64   `json:"releaseId"`
+	Digests        []string `json:"digests"`
+	Approver       string   `json:"approver"`
+	Signature      string   `json:"signature"`
+}
+
+// Sign approves subject with the signer
+func Sign(sig signer.Signer, subject Subject) (*Approval, error) {
+	signature, err := sig.SignMessage(subject.Message())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign approval: %w", err)
+	}
+	return &Approval{
+		ChainID:        subject.ChainID,
+		ReleaseManager: subject.ReleaseManager.Hex(),
+		AVS:            subject.AVS.Hex(),
+		OperatorSetID:  subject.OperatorSetID,
+		ReleaseID:      subject.ReleaseID,
+		Digests:        subject.Digests,
+		Approver:       sig.Address().Hex(),
+		Signature:      hexutil.Encode(signature),
+	}, nil
+}
+
+// Subject returns what the approval covers
+func (a *Approval) Subject() Subject {
+	return Subject{
+		ChainID:        a.ChainID,
+		ReleaseManager: common.HexToAddress(a.ReleaseManager),
+		AVS:            common.HexToAddress(a.AVS),
+		OperatorSetID:  a.OperatorSetID,
+		ReleaseID:      a.ReleaseID,
+		Digests:        a.Digests,
+	}
+}
+
+// Covers reports whether the approval is for subject
+func (a *Approval) Covers(subject Subject) bool {
+	return common.IsHexAddress(a.AVS) && common.IsHexAddress(a.ReleaseManager) && a.Subject().equal(subject)
+}
+
+// Verify checks that the approval covers subject and was signed by its
+// approver, and returns the approver
+func (a *Approval) Verify(subject Subject) (common.Address, error) {
+	if !a.Covers(subject) {
+		return common.Address{}, fmt.Errorf("approval is for a different release, digests, chain or ReleaseManager")
+	}
+	if !common.IsHexAddress(a.Approver) {
+		return common.Address{}, fmt.Errorf("invalid approver address %q", a.Approver)
+	}
+
+	signature, err := hexutil.Decode(a.Signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %w", err)
+	}
+	if len(signature) != 65 {
+		return common.Address{}, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+
+	// Recovery expects V as 0/1
+	recoverable := make([]byte, 65)
+	copy(recoverable, signature)
+	if recoverable[64] >= 27 {
+		recoverable[64] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(subject.Message()), recoverable)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	approver := common.HexToAddress(a.Approver)
+	if signer := crypto.PubkeyToAddress(*pub); signer != approver {
+		return common.Address{}, fmt.Errorf("signed by %s, not approver %s", signer.Hex(), approver.Hex())
+	}
+	return approver, nil
+}
+
+// Evaluate checks approvals against the quorum policy. Approvals for other
+// releases are ignored; invalid ones and unrecognized approvers don't count.
+func Evaluate(p *policy.ApprovalPolicy, subject Subject, approvals []Approval) *policy.Result {
+	recognized := make(map[common.Address]bool)
+	for _, approver := range p.ApproverAddresses() {
+		recognized[approver] = true
+	}
+
+	approved := make(map[common.Address]bool)
+	var invalid, unrecognized int
+	for i := range approvals {
+		if !approvals[i].Covers(subject) {
+			continue
+		}
+		approver, err := approvals[i].Verify(subject)
+		if err != nil {
+			invalid++
+			continue
+		}
+		if !recognized[approver] {
+			unrecognized++
+			continue
+		}
+		approved[approver] = true
+	}
+
+	names := make([]string, 0, len(approved))
+	for approver := range approved {
+		names = append(names, approver.Hex())
+	}
+	sort.Strings(names)
+
+	detail := fmt.Sprintf("%d of %d required approvals", len(approved), p.Threshold)
+	if len(names) > 0 {
+		detail += " (" + strings.Join(names, ", ") + ")"
+	}
+	if invalid > 0 {
+		detail += fmt.Sprintf("; %d invalid", invalid)
+	}
+	if unrecognized > 0 {
+		detail += fmt.Sprintf("; %d from unrecognized approvers", unrecognized)
+	}
+
+	return &policy.Result{
+		Reference: fmt.Sprintf("release %d", subject.ReleaseID),
+		Checks: []policy.Check{{
+			Name:   "approvals",
+			Passed: len(approved) >= p.Threshold,
+			Detail: detail,
+		}},
+	}
+}
@@ -0,0 +1,239 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/policy"
+	"github.com/yourorg/flickr/internal/signer"
+)
+
+// Anvil's default keys
+var testKeys = []string{
+	"0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+	"0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+	"0x5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a",
+}
+
+func testSigners(t *testing.T) []signer.Signer {
+	t.Helper()
+	signers := make([]signer.Signer, 0, len(testKeys))
+	for _, key := range testKeys {
+		sig, err := signer.NewECDSASignerFromHex(key)
+		require.NoError(t, err)
+		signers = append(signers, sig)
+	}
+	return signers
+}
+
+func testSubject() Subject {
+	return NewSubject(
+		11155111, common.HexToAddress("0xabcdef1234567890abcdef1234567890abcdef12"),
+		common.HexToAddress("0x1234567890123456789012345678901234567890"), 1, 5,
+		eth.Release{Artifacts: []eth.Artifact{
+			{Registry: "ghcr.io/org/image", Digest32: [32]byte{0xaa}},
+			{Registry: "ghcr.io/org/sidecar", Digest32: [32]byte{0xbb}},
+		}},
+	)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	sig := testSigners(t)[0]
+	subject := testSubject()
+	require.Len(t, subject.Digests, 2)
+	assert.True(t, strings.HasPrefix(subject.Digests[0], "sha256:aa"))
+
+	a, err := Sign(sig, subject)
+	require.NoError(t, err)
+	assert.Equal(t, sig.Address().Hex(), a.Approver)
+
+	approver, err := a.Verify(subject)
+	require.NoError(t, err)
+	assert.Equal(t, sig.Address(), approver)
+
+	t.Run("Different digests", func(t *testing.T) {
+		other := testSubject()
+		other.Digests = []string{other.Digests[1], other.Digests[0]}
+		_, err := a.Verify(other)
+		assert.ErrorContains(t, err, "different release")
+	})
+
+	t.Run("Other chain or ReleaseManager", func(t *testing.T) {
+		otherChain := testSubject()
+		otherChain.ChainID = 1
+		_, err := a.Verify(otherChain)
+		assert.ErrorContains(t, err, "different release")
+
+		otherRM := testSubject()
+		otherRM.ReleaseManager = common.HexToAddress("0x42583067658071247ec8CE0A516A58f682002d07")
+		_, err = a.Verify(otherRM)
+		assert.ErrorContains(t, err, "different release")
+
+		// Replaying the signature with the approval relabelled fails too
+		replayed := *a
+		replayed.ChainID = 1
+		_, err = replayed.Verify(otherChain)
+		assert.ErrorContains(t, err, "not approver")
+	})
+
+	t.Run("Tampered approval", func(t *testing.T) {
+		tampered := *a
+		tampered.ReleaseID = 6
+		other := testSubject()
+		other.ReleaseID = 6
+		_, err := tampered.Verify(other)
+		assert.ErrorContains(t, err, "not approver")
+	})
+
+	t.Run("Claimed approver", func(t *testing.T) {
+		forged := *a
+		forged.Approver = testSigners(t)[1].Address().Hex()
+		_, err := forged.Verify(subject)
+		assert.ErrorContains(t, err, "not approver")
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	signers := testSigners(t)
+	subject := testSubject()
+
+	sign := func(sig signer.Signer, s Subject) Approval {
+		a, err := Sign(sig, s)
+		require.NoError(t, err)
+		return *a
+	}
+
+	otherRelease := testSubject()
+	otherRelease.ReleaseID = 4
+	otherChain := testSubject()
+	otherChain.ChainID = 17000
+
+	forged := sign(signers[2], subject)
+	forged.Approver = signers[1].Address().Hex()
+
+	pol := &policy.ApprovalPolicy{
+		Threshold: 2,
+		Approvers: []string{signers[0].Address().Hex(), signers[1].Address().Hex()},
+	}
+
+	tests := []struct {
+		name      string
+		approvals []Approval
+		passed    bool
+		detail    string
+	}{
+		{
+			name:      "quorum met",
+			approvals: []Approval{sign(signers[0], subject), sign(signers[1], subject)},
+			passed:    true,
+			detail:    "2 of 2 required approvals",
+		},
+		{
+			name:      "duplicate approvals count once",
+			approvals: []Approval{sign(signers[0], subject), sign(signers[0], subject)},
+			detail:    "1 of 2 required approvals",
+		},
+		{
+			name:      "unrecognized approver",
+			approvals: []Approval{sign(signers[0], subject), sign(signers[2], subject)},
+			detail:    "1 from unrecognized approvers",
+		},
+		{
+			name:      "forged approver",
+			approvals: []Approval{sign(signers[0], subject), forged},
+			detail:    "1 invalid",
+		},
+		{
+			name:      "other releases are ignored",
+			approvals: []Approval{sign(signers[0], otherRelease), sign(signers[1], otherRelease)},
+			detail:    "0 of 2 required approvals",
+		},
+		{
+			name:      "other chains are ignored",
+			approvals: []Approval{sign(signers[0], otherChain), sign(signers[1], otherChain)},
+			detail:    "0 of 2 required approvals",
+		},
+		{
+			name:   "no approvals",
+			detail: "0 of 2 required approvals",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Evaluate(pol, subject, tt.approvals)
+			assert.Equal(t, tt.passed, result.Passed())
+			require.Len(t, result.Checks, 1)
+			assert.Contains(t, result.Checks[0].Detail, tt.detail)
+		})
+	}
+}
+
+func TestSources(t *testing.T) {
+	signers := testSigners(t)
+	subject := testSubject()
+
+	var approvals []Approval
+	dir := t.TempDir()
+	for _, sig := range signers[:2] {
+		a, err := Sign(sig, subject)
+		require.NoError(t, err)
+		path, err := WriteFile(dir, a)
+		require.NoError(t, err)
+		assert.Equal(t, dir, filepath.Dir(path))
+		approvals = append(approvals, *a)
+	}
+
+	t.Run("Directory", func(t *testing.T) {
+		got, err := NewSource(dir).Approvals(context.Background(), subject)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, approvals, got)
+
+		_, err = NewSource(filepath.Join(dir, "missing")).Approvals(context.Background(), subject)
+		assert.Error(t, err)
+
+		bad := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(bad, "bad.json"), []byte("{"), 0644))
+		_, err = NewSource(bad).Approvals(context.Background(), subject)
+		assert.ErrorContains(t, err, "failed to parse approval")
+	})
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/approvals.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(approvals)
+	})
+	mux.HandleFunc("/metadata.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"name": "avs", "approvals": server.URL + "/approvals.json"})
+	})
+	mux.HandleFunc("/no-approvals.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"name": "avs"})
+	})
+
+	t.Run("URL from metadata", func(t *testing.T) {
+		url, err := MetadataApprovalsURL(context.Background(), server.URL+"/metadata.json")
+		require.NoError(t, err)
+		assert.Equal(t, server.URL+"/approvals.json", url)
+
+		got, err := NewSource(url).Approvals(context.Background(), subject)
+		require.NoError(t, err)
+		assert.Equal(t, approvals, got)
+
+		_, err = MetadataApprovalsURL(context.Background(), server.URL+"/no-approvals.json")
+		assert.ErrorContains(t, err, "no approvals URL")
+
+		_, err = NewSource(server.URL + "/missing.json").Approvals(context.Background(), subject)
+		assert.ErrorContains(t, err, "404")
+	})
+}
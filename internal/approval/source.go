@@ -0,0 +1,135 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	fetchTimeout = 30 * time.Second
+	maxFetchSize = 10 << 20
+)
+
+// Source provides the collected approvals of a release
+type Source interface {
+	Approvals(ctx context.Context, subject Subject) ([]Approval, error)
+}
+
+// NewSource returns a source for a directory of approval files or a URL
+// serving a JSON array of approvals
+func NewSource(location string) Source {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return URLSource(location)
+	}
+	return DirSource(location)
+}
+
+// DirSource is a directory of approval files, one approval per *.json file
+type DirSource string
+
+// Approvals reads the approvals in the directory
+func (d DirSource) Approvals(ctx context.Context, subject Subject) ([]Approval, error) {
+	paths, err := filepath.Glob(filepath.Join(string(d), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if paths == nil {
+		if _, err := os.Stat(string(d)); err != nil {
+			return nil, fmt.Errorf("failed to read approvals: %w", err)
+		}
+	}
+
+	approvals := make([]Approval, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read approval: %w", err)
+		}
+		var a Approval
+		if err := json.Unmarshal(data, &a); err != nil {
+			return nil, fmt.Errorf("failed to parse approval %s: %w", path, err)
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, nil
+}
+
+// URLSource is a URL serving a JSON array of approvals
+type URLSource string
+
+// Approvals fetches the approvals from the URL
+func (u URLSource) Approvals(ctx context.Context, subject Subject) ([]Approval, error) {
+	var approvals []Approval
+	if err := fetchJSON(ctx, string(u), &approvals); err != nil {
+		return nil, fmt.Errorf("failed to fetch approvals: %w", err)
+	}
+	return approvals, nil
+}
+
+// MetadataApprovalsURL returns the "approvals" URL of an operator set's
+// metadata document
+func MetadataApprovalsURL(ctx context.Context, metadataURI string) (string, error) {
+	if metadataURI == "" {
+		return "", fmt.Errorf("no metadata URI set for the operator set; set approvals.source in the policy")
+	}
+
+	var metadata struct {
+		Approvals string `json:"approvals"`
+	}
+	if err := fetchJSON(ctx, metadataURI, &metadata); err != nil {
+		return "", fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	if metadata.Approvals == "" {
+		return "", fmt.Errorf("metadata at %s has no approvals URL; set approvals.source in the policy", metadataURI)
+	}
+	return metadata.Approvals, nil
+}
+
+// WriteFile writes an approval to dir and returns its path
+func WriteFile(dir string, a *Approval) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create approvals directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s-%d-%d-%s.json", strings.ToLower(a.AVS), a.OperatorSetID, a.ReleaseID, strings.ToLower(a.Approver))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write approval: %w", err)
+	}
+	return path, nil
+}
+
+func fetchJSON(ctx context.Context, url string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxFetchSize)).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package archive
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/ref"
+)
+
+// ManifestName is the file name of the release manifest inside an archive
+const ManifestName = "flickr-release.json"
+
+// ManifestVersion is the current archive manifest format version
+const ManifestVersion = 1
+
+// ociLayoutName marks the root of an OCI image layout
+const ociLayoutName = "oci-layout"
+
+// Manifest describes the release stored in an archive
+type Manifest struct {
+	Version        int        `json:"version"`
+	ChainID        uint64     `json:"chainId"`
+	ReleaseManager string     `json:"releaseManager"`
+	AVS            string     `json:"avs"`
+	OperatorSetID  uint32     `json:"operatorSetId"`
+	ReleaseID      uint64     `json:"releaseId"`
+	UpgradeByTime  uint32     `json:"upgradeByTime"`
+	Artifacts      []Artifact `json:"artifacts"`
+}
+
+// Artifact is a release artifact stored in an archive
+type Artifact struct {
+	Registry  string `json:"registry"`
+	Digest    string `json:"digest"`    // sha256:<hex>, as recorded on-chain
+	Reference string `json:"reference"` // Reference the image was saved under
+}
+
+// NewManifest builds a manifest for an on-chain release
+func NewManifest(chainID uint64, releaseManager, avs string, operatorSetID uint32, releaseID uint64, release eth.Release) (*Manifest, error) {
+	m := &Manifest{
+		Version:        ManifestVersion,
+		ChainID:        chainID,
+		ReleaseManager: releaseManager,
+		AVS:            avs,
+		OperatorSetID:  operatorSetID,
+		ReleaseID:      releaseID,
+		UpgradeByTime:  release.UpgradeByTime,
+		Artifacts:      make([]Artifact, 0, len(release.Artifacts)),
+	}
+
+	for i, artifact := range release.Artifacts {
+		digest := ref.Digest32ToSha256String(artifact.Digest32)
+		reference, err := ref.BuildReference(artifact.Registry, digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build reference for artifact %d: %w", i, err)
+		}
+		m.Artifacts = append(m.Artifacts, Artifact{
+			Registry:  artifact.Registry,
+			Digest:    digest,
+			Reference: reference,
+		})
+	}
+
+	return m, nil
+}
+
+// References returns the image references of all artifacts
+func (m *Manifest) References() []string {
+	refs := make([]string, len(m.Artifacts))
+	for i, artifact := range m.Artifacts {
+		refs[i] = artifact.Reference
+	}
+	return refs
+}
+
+// Verify checks that the archived artifacts match the on-chain release
+func (m *Manifest) Verify(release eth.Release) error {
+	if len(m.Artifacts) != len(release.Artifacts) {
+		return fmt.Errorf("archive has %d artifacts but release %d has %d on-chain",
+			len(m.Artifacts), m.ReleaseID, len(release.Artifacts))
+	}
+
+	var mismatches []string
+	for i, artifact := range release.Artifacts {
+		expected := ref.Digest32ToSha256String(artifact.Digest32)
+		got := m.Artifacts[i]
+		if got.Digest != expected || got.Registry != artifact.Registry {
+			mismatches = append(mismatches, fmt.Sprintf("  artifact %d: archive %s@%s, on-chain %s@%s",
+				i, got.Registry, got.Digest, artifact.Registry, expected))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("archive does not match release %d on-chain:\n%s", m.ReleaseID, strings.Join(mismatches, "\n"))
+	}
+	return nil
+}
+
+// Write writes an archive to w containing the entries of an OCI image layout
+// tar (as produced by docker save) followed by the release manifest
+func Write(w io.Writer, layout io.Reader, m *Manifest) error {
+	tw := tar.NewWriter(w)
+	tr := tar.NewReader(layout)
+
+	isLayout := false
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read image layout: %w", err)
+		}
+		if hdr.Name == ManifestName {
+			continue
+		}
+		if hdr.Name == ociLayoutName {
+			isLayout = true
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write archive entry %s: %w", hdr.Name, err)
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return fmt.Errorf("failed to write archive entry %s: %w", hdr.Name, err)
+		}
+	}
+
+	if !isLayout {
+		return fmt.Errorf("image export is not an OCI image layout (Docker 25 or later is required)")
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name: ManifestName,
+		Mode: 0644,
+		Size: int64(len(data)),
+	}); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return tw.Close()
+}
+
+// ReadManifest reads the release manifest from an archive
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("archive has no %s (not a flickr release archive)", ManifestName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Name != ManifestName {
+			continue
+		}
+
+		var m Manifest
+		if err := json.NewDecoder(tr).Decode(&m); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ManifestName, err)
+		}
+		if m.Version != ManifestVersion {
+			return nil, fmt.Errorf("unsupported archive version %d", m.Version)
+		}
+		return &m, nil
+	}
+}
@@ -0,0 +1,125 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/eth"
+)
+
+func testRelease() eth.Release {
+	var d [32]byte
+	for i := range d {
+		d[i] = 0xaa
+	}
+	return eth.Release{
+		Artifacts: []eth.Artifact{
+			{Registry: "ghcr.io/org/image", Digest32: d},
+		},
+		UpgradeByTime: 123456,
+	}
+}
+
+func testLayout(t *testing.T, files map[string]string) *bytes.Buffer {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return &buf
+}
+
+func TestNewManifest(t *testing.T) {
+	m, err := NewManifest(11155111, "0xrm", "0xavs", 1, 7, testRelease())
+	require.NoError(t, err)
+
+	assert.Equal(t, ManifestVersion, m.Version)
+	assert.Equal(t, uint64(7), m.ReleaseID)
+	require.Len(t, m.Artifacts, 1)
+	assert.Equal(t, "sha256:"+strings.Repeat("aa", 32), m.Artifacts[0].Digest)
+	assert.Equal(t, []string{"ghcr.io/org/image@sha256:" + strings.Repeat("aa", 32)}, m.References())
+}
+
+func TestWriteAndReadManifest(t *testing.T) {
+	m, err := NewManifest(11155111, "0xrm", "0xavs", 1, 7, testRelease())
+	require.NoError(t, err)
+
+	layout := testLayout(t, map[string]string{
+		"oci-layout": `{"imageLayoutVersion":"1.0.0"}`,
+		"index.json": `{"schemaVersion":2,"manifests":[]}`,
+	})
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, layout, m))
+
+	// Layout entries are kept alongside the manifest
+	names := []string{}
+	tr := tar.NewReader(bytes.NewReader(out.Bytes()))
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, hdr.Name)
+	}
+	assert.ElementsMatch(t, []string{"oci-layout", "index.json", ManifestName}, names)
+
+	got, err := ReadManifest(bytes.NewReader(out.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, m, got)
+}
+
+func TestWrite_NotOCILayout(t *testing.T) {
+	m, err := NewManifest(1, "0xrm", "0xavs", 0, 0, testRelease())
+	require.NoError(t, err)
+
+	layout := testLayout(t, map[string]string{"manifest.json": "[]"})
+
+	var out bytes.Buffer
+	err = Write(&out, layout, m)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OCI image layout")
+}
+
+func TestReadManifest_Missing(t *testing.T) {
+	layout := testLayout(t, map[string]string{"oci-layout": "{}"})
+
+	_, err := ReadManifest(layout)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a flickr release archive")
+}
+
+func TestManifestVerify(t *testing.T) {
+	release := testRelease()
+	m, err := NewManifest(1, "0xrm", "0xavs", 0, 3, release)
+	require.NoError(t, err)
+
+	t.Run("matching release", func(t *testing.T) {
+		assert.NoError(t, m.Verify(release))
+	})
+
+	t.Run("digest mismatch", func(t *testing.T) {
+		tampered := testRelease()
+		tampered.Artifacts[0].Digest32[0] = 0xbb
+
+		err := m.Verify(tampered)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "artifact 0")
+	})
+
+	t.Run("artifact count mismatch", func(t *testing.T) {
+		extra := testRelease()
+		extra.Artifacts = append(extra.Artifacts, extra.Artifacts[0])
+
+		err := m.Verify(extra)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has 2 on-chain")
+	})
+}
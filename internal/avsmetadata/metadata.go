@@ -0,0 +1,191 @@
+// Package avsmetadata reads the metadata document an AVS publishes for an
+// operator set with the ReleaseManager's publishMetadataURI.
+//
+// Besides the usual descriptive fields, the document may describe how
+// operators should run the AVS, so 'flickr context create --from-avs' can
+// prepare a working context:
+//
+//	{
+//	  "name": "My AVS",
+//	  "description": "...",
+//	  "website": "https://my-avs.example.com",
+//	  "approvals": "https://my-avs.example.com/approvals.json",
+//	  "runtime": {
+//	    "containerName": "my-avs",
+//	    "env": {"LOG_LEVEL": "info"},
+//	    "requiredEnv": ["OPERATOR_ID"],
+//	    "ports": ["9000:9000"],
+//	    "volumes": ["my-avs-data:/data"]
+//	  }
+//	}
+//
+// Only named volumes may be mounted; host paths are rejected.
+package avsmetadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yourorg/flickr/internal/config"
+)
+
+const (
+	fetchTimeout = 30 * time.Second
+	maxFetchSize = 1 << 20
+)
+
+// Metadata is an operator set's metadata document. Unknown fields are
+// ignored, as the document is shared with other tools.
+type Metadata struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Website     string   `json:"website,omitempty"`
+	Approvals   string   `json:"approvals,omitempty"` // URL of the release approvals
+	Runtime     *Runtime `json:"runtime,omitempty"`
+}
+
+// Runtime describes how operators run the AVS
+type Runtime struct {
+	ContainerName string            `json:"containerName,omitempty"` // Container name prefix
+	Env           map[string]string `json:"env,omitempty"`           // Recommended environment variables
+	RequiredEnv   []string          `json:"requiredEnv,omitempty"`   // Variables each operator must set
+	Ports         []string          `json:"ports,omitempty"`         // Published ports, in docker run -p syntax
+	Volumes       []string          `json:"volumes,omitempty"`       // Mounts, in docker run -v syntax
+}
+
+var (
+	envKeyPattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	nonNamePattern = regexp.MustCompile(`[^a-z0-9_.-]+`)
+)
+
+// Fetch downloads and validates the metadata document at uri
+func Fetch(ctx context.Context, uri string) (*Metadata, error) {
+	if uri == "" {
+		return nil, fmt.Errorf("no metadata URI set for the operator set")
+	}
+	if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		return nil, fmt.Errorf("unsupported metadata URI %q (expected http or https)", uri)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch metadata: %s returned %s", uri, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+
+	return Parse(data)
+}
+
+// Parse decodes and validates a metadata document
+func Parse(data []byte) (*Metadata, error) {
+	var m Metadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	return &m, nil
+}
+
+func (m *Metadata) validate() error {
+	r := m.Runtime
+	if r == nil {
+		return nil
+	}
+
+	for key := range r.Env {
+		if !envKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid environment variable name %q", key)
+		}
+	}
+	for _, key := range r.RequiredEnv {
+		if !envKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid required environment variable name %q", key)
+		}
+	}
+	for _, port := range r.Ports {
+		if port == "" || strings.ContainsAny(port, " \t\n") {
+			return fmt.Errorf("invalid port %q", port)
+		}
+	}
+	for _, volume := range r.Volumes {
+		if !strings.Contains(volume, ":") || strings.ContainsAny(volume, "\n") {
+			return fmt.Errorf("invalid volume %q (expected SOURCE:TARGET)", volume)
+		}
+		// The document is controlled by the AVS; host bind mounts such as
+		// the Docker socket must be added by the operator
+		if !config.IsNamedVolume(volume) {
+			return fmt.Errorf("invalid volume %q: only named volumes are allowed, not host paths", volume)
+		}
+	}
+	return nil
+}
+
+// ContainerName returns the container name prefix for the AVS: the runtime's
+// name, or one derived from the AVS name
+func (m *Metadata) ContainerName() string {
+	if m.Runtime != nil && m.Runtime.ContainerName != "" {
+		return m.Runtime.ContainerName
+	}
+	return strings.Trim(nonNamePattern.ReplaceAllString(strings.ToLower(m.Name), "-"), "-._")
+}
+
+// Apply fills a context with the runtime settings of the metadata. Settings
+// already in the context are kept. It returns the required environment
+// variables the context does not set.
+func (m *Metadata) Apply(ctx *config.Context) []string {
+	if ctx.Name == "" {
+		ctx.Name = m.ContainerName()
+	}
+
+	r := m.Runtime
+	if r == nil {
+		return nil
+	}
+
+	for key, value := range r.Env {
+		if _, exists := ctx.EnvironmentVars[key]; exists {
+			continue
+		}
+		if ctx.EnvironmentVars == nil {
+			ctx.EnvironmentVars = make(map[string]string)
+		}
+		ctx.EnvironmentVars[key] = value
+	}
+	if len(ctx.Ports) == 0 {
+		ctx.Ports = append([]string(nil), r.Ports...)
+	}
+	if len(ctx.Volumes) == 0 {
+		ctx.Volumes = append([]string(nil), r.Volumes...)
+	}
+
+	var missing []string
+	for _, key := range r.RequiredEnv {
+		if _, exists := ctx.EnvironmentVars[key]; !exists {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,100 @@
+package avsmetadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/config"
+)
+
+const testMetadata = `{
+  "name": "My AVS",
+  "website": "https://my-avs.example.com",
+  "logo": "https://my-avs.example.com/logo.png",
+  "runtime": {
+    "env": {"LOG_LEVEL": "info", "NETWORK": "holesky"},
+    "requiredEnv": ["OPERATOR_ID", "NETWORK"],
+    "ports": ["9000:9000"],
+    "volumes": ["my-avs-data:/data"]
+  }
+}`
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metadata.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testMetadata))
+	}))
+	defer server.Close()
+
+	m, err := Fetch(context.Background(), server.URL+"/metadata.json")
+	require.NoError(t, err)
+	assert.Equal(t, "My AVS", m.Name)
+	assert.Equal(t, []string{"9000:9000"}, m.Runtime.Ports)
+
+	_, err = Fetch(context.Background(), server.URL+"/missing.json")
+	assert.ErrorContains(t, err, "404")
+
+	_, err = Fetch(context.Background(), "")
+	assert.ErrorContains(t, err, "no metadata URI")
+
+	_, err = Fetch(context.Background(), "ipfs://bafy")
+	assert.ErrorContains(t, err, "unsupported metadata URI")
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		wantErr  string
+	}{
+		{"not json", `name: x`, "failed to parse metadata"},
+		{"env name", `{"runtime": {"env": {"BAD-KEY": "1"}}}`, "invalid environment variable name"},
+		{"required env name", `{"runtime": {"requiredEnv": ["1X"]}}`, "invalid required environment variable name"},
+		{"port", `{"runtime": {"ports": ["9000 -v /:/host"]}}`, "invalid port"},
+		{"volume", `{"runtime": {"volumes": ["/data"]}}`, "invalid volume"},
+		{"docker socket", `{"runtime": {"volumes": ["/var/run/docker.sock:/var/run/docker.sock"]}}`, "only named volumes"},
+		{"host root", `{"runtime": {"volumes": ["/:/host"]}}`, "only named volumes"},
+		{"relative path", `{"runtime": {"volumes": ["./data:/data"]}}`, "only named volumes"},
+		{"home path", `{"runtime": {"volumes": ["~/.ssh:/keys:ro"]}}`, "only named volumes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.metadata))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMetadata_Apply(t *testing.T) {
+	m, err := Parse([]byte(testMetadata))
+	require.NoError(t, err)
+
+	ctx := &config.Context{
+		EnvironmentVars: map[string]string{"LOG_LEVEL": "debug"},
+	}
+	missing := m.Apply(ctx)
+
+	assert.Equal(t, "my-avs", ctx.Name)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "NETWORK": "holesky"}, ctx.EnvironmentVars)
+	assert.Equal(t, []string{"9000:9000"}, ctx.Ports)
+	assert.Equal(t, []string{"my-avs-data:/data"}, ctx.Volumes)
+	assert.Equal(t, []string{"OPERATOR_ID"}, missing)
+
+	// Ports and volumes are copied, not shared
+	ctx.Ports[0] = "9001:9000"
+	assert.Equal(t, "9000:9000", m.Runtime.Ports[0])
+}
+
+func TestMetadata_ContainerName(t *testing.T) {
+	assert.Equal(t, "eigen-da", (&Metadata{Name: " Eigen DA! "}).ContainerName())
+	assert.Equal(t, "custom", (&Metadata{Name: "My AVS", Runtime: &Runtime{ContainerName: "custom"}}).ContainerName())
+	assert.Empty(t, (&Metadata{}).ContainerName())
+}
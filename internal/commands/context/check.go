@@ -0,0 +1,77 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func checkCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "check",
+		Usage:     "Check a context's settings against the chain",
+		ArgsUsage: "[name]",
+		Description: `Checks that the RPC URL responds, the ReleaseManager is deployed and answers
+getTotalReleases, the AVS has created operator sets and the operator set exists.
+Checks the current context when no name is given. A context not yet pinned to a
+chain is pinned to the chain the RPC URL serves; a pinned context fails the check
+if the RPC URL serves another chain.`,
+		Action: contextCheckAction,
+	}
+}
+
+func contextCheckAction(c *cli.Context) error {
+	if c.NArg() > 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	name := c.Args().First()
+	if name == "" {
+		if name, _, err = cfg.Current(); err != nil {
+			return err
+		}
+	}
+
+	ctx, exists := cfg.Contexts[name]
+	if !exists {
+		return fmt.Errorf("context '%s' not found", name)
+	}
+
+	if err := checkContext(c, ctx); err != nil {
+		return err
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Fprintf(c.App.Writer, "\nContext '%s' matches the chain\n", name)
+	return nil
+}
+
+// checkContext checks ctx against the chain and prints the results. On
+// success ctx is pinned to the chain, if it isn't yet.
+func checkContext(c *cli.Context, ctx *config.Context) error {
+	log := middleware.GetLogger(c)
+
+	report := eth.RunCheck(c.App.Writer, ctx)
+
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("context check failed: %d of %d checks did not pass", failed, len(report.Checks))
+	}
+
+	if ctx.ChainID == 0 {
+		ctx.ChainID = report.ChainID
+		log.Info("Pinned chain ID", zap.Uint64("chainId", report.ChainID))
+	}
+	return nil
+}
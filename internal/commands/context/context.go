@@ -15,6 +15,14 @@ func Command() *cli.Command {
 			useCommand(),
 			setCommand(),
 			showCommand(),
+			deleteCommand(),
+			renameCommand(),
+			copyCommand(),
+			unsetCommand(),
+			checkCommand(),
+			deploymentCommand(),
+			exportCommand(),
+			importCommand(),
 		},
 	}
 }
\ No newline at end of file
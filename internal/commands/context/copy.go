@@ -0,0 +1,59 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func copyCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "copy",
+		Usage:     "Create a context from the settings of another",
+		ArgsUsage: "<source-name> <new-name>",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "use",
+				Usage: "Set the new context as current",
+			},
+		},
+		Action: contextCopyAction,
+	}
+}
+
+func contextCopyAction(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	source := c.Args().Get(0)
+	name := c.Args().Get(1)
+	log := middleware.GetLogger(c)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.CopyContext(source, name); err != nil {
+		return err
+	}
+	if c.Bool("use") {
+		cfg.CurrentContext = name
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	log.Info("Context copied", zap.String("from", source), zap.String("to", name))
+	fmt.Printf("Context '%s' created from '%s'\n", name, source)
+	if c.Bool("use") {
+		fmt.Printf("Current context set to '%s'\n", name)
+	}
+
+	return nil
+}
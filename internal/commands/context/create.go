@@ -1,10 +1,16 @@
 package context
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/avsmetadata"
 	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
 	"github.com/yourorg/flickr/internal/middleware"
 	"go.uber.org/zap"
 )
@@ -13,17 +19,39 @@ func createCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "create",
 		Usage: "Create a new context",
+		Description: `With --from-avs, the context is filled from the metadata document the AVS
+published for the operator set: container name, recommended environment
+variables, ports and volumes.`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:     "name",
-				Usage:    "Name of the context",
-				Required: true,
+				Name:  "name",
+				Usage: "Name of the context (with --from-avs, defaults to the AVS's container name)",
 			},
 			&cli.BoolFlag{
 				Name:  "use",
 				Usage: "Set as current context",
 				Value: true,
 			},
+			&cli.StringFlag{
+				Name:  "from-avs",
+				Usage: "Fill the context from the metadata of this AVS address",
+			},
+			&cli.Uint64Flag{
+				Name:  "operator-set",
+				Usage: "Operator set ID of the AVS (with --from-avs)",
+			},
+			&cli.StringFlag{
+				Name:  "rpc-url",
+				Usage: "Ethereum RPC URL (with --from-avs)",
+			},
+			&cli.StringFlag{
+				Name:  "release-manager",
+				Usage: "ReleaseManager contract address (with --from-avs; uses chain default if not provided)",
+			},
+			&cli.Uint64Flag{
+				Name:  "chain-id",
+				Usage: "Pin the chain the RPC URL must serve (with --from-avs, defaults to the chain the RPC URL serves)",
+			},
 		},
 		Action: contextCreateAction,
 	}
@@ -35,23 +63,36 @@ func contextCreateAction(c *cli.Context) error {
 	name := c.String("name")
 	setCurrent := c.Bool("use")
 
-	// Load existing config or create new one
+	// Load existing config; a missing file loads as an empty config, and an
+	// invalid one must not be overwritten
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		// If config doesn't exist, create a new one
-		cfg = &config.Config{
-			Contexts: make(map[string]*config.Context),
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Create new context, filled from the AVS metadata if requested
+	ctx := &config.Context{ChainID: c.Uint64("chain-id")}
+	var metadata *avsmetadata.Metadata
+	var missingEnv []string
+	if c.String("from-avs") != "" {
+		metadata, err = contextFromAVS(c, ctx)
+		if err != nil {
+			return err
+		}
+		missingEnv = metadata.Apply(ctx)
+		if name == "" {
+			name = metadata.ContainerName()
 		}
 	}
+	if name == "" {
+		return fmt.Errorf("--name is required")
+	}
 
 	// Check if context already exists
 	if _, exists := cfg.Contexts[name]; exists {
 		return fmt.Errorf("context '%s' already exists", name)
 	}
 
-	// Create new context
-	ctx := &config.Context{}
-
 	// Add to config
 	cfg.Contexts[name] = ctx
 
@@ -70,9 +111,105 @@ func contextCreateAction(c *cli.Context) error {
 		zap.Bool("current", setCurrent))
 
 	fmt.Printf("Context '%s' created successfully\n", name)
+	if metadata != nil {
+		printAVSContext(metadata, ctx, missingEnv)
+	}
 	if setCurrent {
 		fmt.Printf("Current context set to '%s'\n", name)
 	}
 
 	return nil
+}
+
+// contextFromAVS sets the AVS settings of ctx from the flags and fetches the
+// metadata published for the operator set
+func contextFromAVS(c *cli.Context, ctx *config.Context) (*avsmetadata.Metadata, error) {
+	avsAddress := c.String("from-avs")
+	if !common.IsHexAddress(avsAddress) {
+		return nil, fmt.Errorf("invalid AVS address: %s", avsAddress)
+	}
+	rpcURL := c.String("rpc-url")
+	if rpcURL == "" {
+		return nil, fmt.Errorf("--rpc-url is required with --from-avs")
+	}
+
+	// Pin the context to the chain it was created from
+	chainID, err := eth.GetChainID(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.ChainID != 0 && ctx.ChainID != chainID {
+		return nil, &eth.ChainMismatchError{Expected: ctx.ChainID, Actual: chainID}
+	}
+	ctx.ChainID = chainID
+
+	rmAddr, err := eth.GetReleaseManagerAddress(rpcURL, c.String("release-manager"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ReleaseManager address: %w", err)
+	}
+
+	rmClient, err := eth.NewClient(rpcURL, rmAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Ethereum client: %w", err)
+	}
+	defer rmClient.Close()
+
+	avs := common.HexToAddress(avsAddress)
+	operatorSetID := uint32(c.Uint64("operator-set"))
+
+	bgCtx := context.Background()
+	metadataURI, err := rmClient.GetMetadataURI(bgCtx, avs, operatorSetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata URI: %w", err)
+	}
+	metadata, err := avsmetadata.Fetch(bgCtx, metadataURI)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.AVSAddress = avs.Hex()
+	ctx.OperatorSetID = operatorSetID
+	ctx.RPCURL = rpcURL
+	ctx.ReleaseManager = c.String("release-manager")
+
+	middleware.GetLogger(c).Info("Fetched AVS metadata",
+		zap.String("avs", avs.Hex()),
+		zap.Uint32("operatorSet", operatorSetID),
+		zap.String("uri", metadataURI))
+
+	return metadata, nil
+}
+
+// printAVSContext summarizes what was taken from the AVS metadata, so the
+// operator can review it before running
+func printAVSContext(metadata *avsmetadata.Metadata, ctx *config.Context, missingEnv []string) {
+	if metadata.Name != "" {
+		fmt.Printf("AVS: %s (%s)\n", metadata.Name, ctx.AVSAddress)
+	} else {
+		fmt.Printf("AVS: %s\n", ctx.AVSAddress)
+	}
+	fmt.Printf("Operator Set: %d\n", ctx.OperatorSetID)
+	if ctx.Name != "" {
+		fmt.Printf("Container name prefix: %s\n", ctx.Name)
+	}
+	if len(ctx.EnvironmentVars) > 0 {
+		keys := make([]string, 0, len(ctx.EnvironmentVars))
+		for key := range ctx.EnvironmentVars {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Printf("Environment: %s\n", strings.Join(keys, ", "))
+	}
+	for _, port := range ctx.Ports {
+		fmt.Printf("Port: %s\n", port)
+	}
+	for _, volume := range ctx.Volumes {
+		fmt.Printf("Volume: %s\n", volume)
+	}
+	if len(missingEnv) > 0 {
+		fmt.Printf("\nThe AVS requires these environment variables:\n")
+		for _, key := range missingEnv {
+			fmt.Printf("  flickr context set --env %s=<value>\n", key)
+		}
+	}
 }
\ No newline at end of file
@@ -0,0 +1,60 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func deleteCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "delete",
+		Usage:     "Delete a context",
+		ArgsUsage: "<context-name>",
+		Action:    contextDeleteAction,
+	}
+}
+
+func contextDeleteAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	contextName := c.Args().Get(0)
+	log := middleware.GetLogger(c)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	wasCurrent := cfg.CurrentContext == contextName
+	if err := cfg.DeleteContext(contextName); err != nil {
+		return err
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	// Secret files moved out of the config belong to the context
+	removed, err := config.RemoveSecretFiles(cfg, contextName)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Context deleted", zap.String("name", contextName))
+	fmt.Printf("Context '%s' deleted\n", contextName)
+	if removed != "" {
+		fmt.Printf("Removed secret files in %s\n", removed)
+	}
+	if wasCurrent {
+		fmt.Printf("\nNo context is selected. To switch to another context, run:\n")
+		fmt.Printf("  flickr context use <context-name>\n")
+	}
+
+	return nil
+}
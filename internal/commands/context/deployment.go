@@ -0,0 +1,203 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func deploymentCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "deployment",
+		Usage: "Manage the operator sets served from the current context",
+		Description: `A context can serve several operator sets, each run as its own container.
+run and pull act on every deployment, or on those selected with --deployment.
+Settings a deployment leaves empty are taken from the context.`,
+		Subcommands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "Add a deployment to the current context",
+				ArgsUsage: "<name>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "avs-address",
+						Usage: "AVS contract address (defaults to the context's)",
+					},
+					&cli.Uint64Flag{
+						Name:  "operator-set-id",
+						Usage: "Operator set ID",
+					},
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "Container name (defaults to <context name>-<deployment>)",
+					},
+					&cli.StringSliceFlag{
+						Name:  "env",
+						Usage: "Environment variables, merged over the context's (KEY=VALUE)",
+					},
+					&cli.StringSliceFlag{
+						Name:  "port",
+						Usage: "Publish a container port, added to the context's (docker run -p syntax)",
+					},
+					&cli.StringSliceFlag{
+						Name:  "volume",
+						Usage: "Mount a volume, added to the context's (docker run -v syntax)",
+					},
+				},
+				Action: deploymentAddAction,
+			},
+			{
+				Name:      "remove",
+				Usage:     "Remove a deployment from the current context",
+				ArgsUsage: "<name>",
+				Action:    deploymentRemoveAction,
+			},
+			{
+				Name:   "list",
+				Usage:  "List the deployments of the current context",
+				Action: deploymentListAction,
+			},
+		},
+	}
+}
+
+// loadCurrentContext loads the config and the name and settings of the
+// context commands use
+func loadCurrentContext() (*config.Config, string, *config.Context, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	contextName, ctx, err := cfg.Current()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return cfg, contextName, ctx, nil
+}
+
+func deploymentAddAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	name := c.Args().Get(0)
+	log := middleware.GetLogger(c)
+
+	if err := config.ValidateDeploymentName(name); err != nil {
+		return err
+	}
+
+	cfg, contextName, ctx, err := loadCurrentContext()
+	if err != nil {
+		return err
+	}
+	if _, exists := ctx.Deployments[name]; exists {
+		return fmt.Errorf("deployment '%s' already exists in context '%s'", name, contextName)
+	}
+
+	deployment := &config.Deployment{
+		AVSAddress:    c.String("avs-address"),
+		OperatorSetID: uint32(c.Uint64("operator-set-id")),
+		Name:          c.String("name"),
+		Ports:         c.StringSlice("port"),
+		Volumes:       c.StringSlice("volume"),
+	}
+	if deployment.AVSAddress == "" && ctx.AVSAddress == "" {
+		return fmt.Errorf("--avs-address is required (the context has no AVS address)")
+	}
+	for _, env := range c.StringSlice("env") {
+		key, value, ok := strings.Cut(env, "=")
+		if !ok {
+			return fmt.Errorf("invalid env format: %s (expected KEY=VALUE)", env)
+		}
+		if deployment.EnvironmentVars == nil {
+			deployment.EnvironmentVars = make(map[string]string)
+		}
+		deployment.EnvironmentVars[key] = value
+	}
+
+	if ctx.Deployments == nil {
+		ctx.Deployments = make(map[string]*config.Deployment)
+	}
+	ctx.Deployments[name] = deployment
+
+	// SaveConfig validates the deployment
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	log.Info("Deployment added",
+		zap.String("context", contextName),
+		zap.String("deployment", name),
+		zap.Uint32("operatorSet", deployment.OperatorSetID))
+	fmt.Printf("Deployment '%s' added to context '%s'\n", name, contextName)
+	return nil
+}
+
+func deploymentRemoveAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	name := c.Args().Get(0)
+	log := middleware.GetLogger(c)
+
+	cfg, contextName, ctx, err := loadCurrentContext()
+	if err != nil {
+		return err
+	}
+	if _, exists := ctx.Deployments[name]; !exists {
+		return fmt.Errorf("deployment '%s' not found in context '%s'", name, contextName)
+	}
+
+	delete(ctx.Deployments, name)
+	if len(ctx.Deployments) == 0 {
+		ctx.Deployments = nil
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	log.Info("Deployment removed", zap.String("context", contextName), zap.String("deployment", name))
+	fmt.Printf("Deployment '%s' removed from context '%s'\n", name, contextName)
+	return nil
+}
+
+func deploymentListAction(c *cli.Context) error {
+	_, contextName, ctx, err := loadCurrentContext()
+	if err != nil {
+		return err
+	}
+
+	if len(ctx.Deployments) == 0 {
+		fmt.Printf("Context '%s' has no deployments\n", contextName)
+		fmt.Println("\nTo add one, run:")
+		fmt.Println("  flickr context deployment add <name> --avs-address <address> --operator-set-id <id>")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(c.App.Writer)
+	table.Header("NAME", "AVS ADDRESS", "OPERATOR SET", "CONTAINER NAME")
+
+	for _, name := range ctx.DeploymentNames() {
+		deployed, err := ctx.Deployment(name)
+		if err != nil {
+			return err
+		}
+		table.Append([]string{
+			name,
+			deployed.AVSAddress,
+			fmt.Sprintf("%d", deployed.OperatorSetID),
+			deployed.Name,
+		})
+	}
+
+	table.Render()
+	return nil
+}
@@ -0,0 +1,96 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func exportCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "export",
+		Usage:     "Export a context as a shareable profile",
+		ArgsUsage: "[name]",
+		Description: `Writes a context as a YAML or JSON profile that others can load with
+'flickr context import'. Exports the current context when no name is given.
+
+Secret values are never exported. Machine-specific settings (` + strings.Join(config.LocalFields, ", ") + `)
+are left out unless --include-local is set.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Path of the profile to write (default: stdout)",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Profile format (yaml or json)",
+				Value: "yaml",
+			},
+			&cli.StringFlag{
+				Name:  "description",
+				Usage: "Description shown when the profile is imported",
+			},
+			&cli.BoolFlag{
+				Name:  "include-local",
+				Usage: "Include signer settings, registry credential references and the policy path",
+			},
+		},
+		Action: contextExportAction,
+	}
+}
+
+func contextExportAction(c *cli.Context) error {
+	if c.NArg() > 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	log := middleware.GetLogger(c)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	name := c.Args().First()
+	if name == "" {
+		if name, _, err = cfg.Current(); err != nil {
+			return err
+		}
+	}
+
+	ctx, exists := cfg.Contexts[name]
+	if !exists {
+		return fmt.Errorf("context '%s' not found", name)
+	}
+
+	profile, err := config.NewProfile(name, ctx, c.Bool("include-local"))
+	if err != nil {
+		return err
+	}
+	profile.Description = c.String("description")
+
+	data, err := config.MarshalProfile(profile, c.String("format"))
+	if err != nil {
+		return err
+	}
+
+	output := c.String("output")
+	if output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write profile: %w", err)
+	}
+
+	log.Info("Context exported", zap.String("name", name), zap.String("output", output))
+	fmt.Printf("Context '%s' exported to %s\n", name, output)
+	return nil
+}
@@ -0,0 +1,155 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+const (
+	profileFetchTimeout = 30 * time.Second
+	maxProfileSize      = 1 << 20
+)
+
+func importCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Create a context from a profile file or URL",
+		ArgsUsage: "<file|url>",
+		Description: `Creates a context from a profile written by 'flickr context export'. Use "-" to
+read the profile from stdin.
+
+Machine-specific settings (` + strings.Join(config.LocalFields, ", ") + `) in the profile are ignored
+unless --include-local is set, as they may run programs or read files on this host.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Name of the context (default: the name in the profile)",
+			},
+			&cli.BoolFlag{
+				Name:  "use",
+				Usage: "Set as current context",
+				Value: true,
+			},
+			&cli.BoolFlag{
+				Name:  "include-local",
+				Usage: "Keep signer settings, registry credential references and the policy path from the profile",
+			},
+		},
+		Action: contextImportAction,
+	}
+}
+
+func contextImportAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	source := c.Args().Get(0)
+	log := middleware.GetLogger(c)
+
+	data, err := readProfile(context.Background(), source)
+	if err != nil {
+		return err
+	}
+
+	profile, err := config.ParseProfile(data)
+	if err != nil {
+		return err
+	}
+
+	name := c.String("name")
+	if name == "" {
+		name = profile.Name
+	}
+	if name == "" {
+		return fmt.Errorf("profile has no name; set one with --name")
+	}
+
+	stripped := false
+	if !c.Bool("include-local") {
+		stripped = profile.StripLocal()
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if _, exists := cfg.Contexts[name]; exists {
+		return fmt.Errorf("context '%s' already exists", name)
+	}
+
+	cfg.Contexts[name] = profile.Context
+	if c.Bool("use") {
+		cfg.CurrentContext = name
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	log.Info("Context imported", zap.String("name", name), zap.String("source", source))
+	fmt.Printf("Context '%s' imported from %s\n", name, source)
+	if profile.Description != "" {
+		fmt.Printf("%s\n", profile.Description)
+	}
+	if stripped {
+		fmt.Printf("Ignored machine-specific settings (%s); configure them with 'flickr context set'\n", strings.Join(config.LocalFields, ", "))
+	}
+	if c.Bool("use") {
+		fmt.Printf("Current context set to '%s'\n", name)
+	}
+
+	return nil
+}
+
+// readProfile reads a profile from a file, a URL or stdin
+func readProfile(ctx context.Context, source string) ([]byte, error) {
+	if source == "-" {
+		data, err := io.ReadAll(io.LimitReader(os.Stdin, maxProfileSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read profile: %w", err)
+		}
+		return data, nil
+	}
+
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read profile: %w", err)
+		}
+		return data, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, profileFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch profile: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch profile: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch profile: %s returned %s", source, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxProfileSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch profile: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,47 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func renameCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "rename",
+		Usage:     "Rename a context",
+		ArgsUsage: "<old-name> <new-name>",
+		Action:    contextRenameAction,
+	}
+}
+
+func contextRenameAction(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	oldName := c.Args().Get(0)
+	newName := c.Args().Get(1)
+	log := middleware.GetLogger(c)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.RenameContext(oldName, newName); err != nil {
+		return err
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	log.Info("Context renamed", zap.String("from", oldName), zap.String("to", newName))
+	fmt.Printf("Context '%s' renamed to '%s'\n", oldName, newName)
+
+	return nil
+}
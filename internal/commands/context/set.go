@@ -2,11 +2,17 @@ package context
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli/v2"
 	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
 	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/policy"
+	"github.com/yourorg/flickr/internal/secrets"
 	"go.uber.org/zap"
 )
 
@@ -27,6 +33,10 @@ func setCommand() *cli.Command {
 				Name:  "rpc-url",
 				Usage: "Set the Ethereum RPC URL",
 			},
+			&cli.Uint64Flag{
+				Name:  "chain-id",
+				Usage: "Pin the chain the RPC URL must serve (commands refuse other chains)",
+			},
 			&cli.StringFlag{
 				Name:  "release-manager",
 				Usage: "Set the release manager contract address",
@@ -39,9 +49,33 @@ func setCommand() *cli.Command {
 				Name:  "env",
 				Usage: "Set environment variables (KEY=VALUE)",
 			},
+			&cli.StringSliceFlag{
+				Name:  "env-remove",
+				Usage: "Remove environment variables (KEY)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "port",
+				Usage: "Publish a container port in flickr run (docker run -p syntax, e.g. 9000:9000)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "volume",
+				Usage: "Mount a volume in flickr run (docker run -v syntax, e.g. my-avs-data:/data)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "registry-mirror",
+				Usage: "Fetch images for a registry from a mirror (SOURCE=MIRROR, e.g. ghcr.io=mirror.internal)",
+			},
+			&cli.StringFlag{
+				Name:  "policy",
+				Usage: "Set the attestation policy file checked by pull and run",
+			},
 			&cli.StringFlag{
 				Name:  "ecdsa-private-key",
-				Usage: "Set ECDSA private key (hex encoded)",
+				Usage: "Set ECDSA private key (hex encoded; saved to a file readable only by you, prefer --ecdsa-private-key-ref)",
+			},
+			&cli.StringFlag{
+				Name:  "ecdsa-private-key-ref",
+				Usage: "Set where the ECDSA private key is read from (" + secrets.RefHelp + ")",
 			},
 			&cli.StringFlag{
 				Name:  "keystore-path",
@@ -49,7 +83,63 @@ func setCommand() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  "keystore-password",
-				Usage: "Set keystore password",
+				Usage: "Set keystore password (saved to a file readable only by you, prefer --keystore-password-ref)",
+			},
+			&cli.StringFlag{
+				Name:  "keystore-password-ref",
+				Usage: "Set where the keystore password is read from (" + secrets.RefHelp + ")",
+			},
+			&cli.StringFlag{
+				Name:  "remote-signer-url",
+				Usage: "Set the URL of a Web3Signer-compatible remote signer",
+			},
+			&cli.StringFlag{
+				Name:  "remote-signer-address",
+				Usage: "Set the address of the key held by the remote signer",
+			},
+			&cli.StringFlag{
+				Name:  "remote-signer-ca-cert",
+				Usage: "Set the CA certificate used to verify the remote signer",
+			},
+			&cli.StringFlag{
+				Name:  "remote-signer-client-cert",
+				Usage: "Set the client certificate for mutual TLS with the remote signer",
+			},
+			&cli.StringFlag{
+				Name:  "remote-signer-client-key",
+				Usage: "Set the client key for mutual TLS with the remote signer",
+			},
+			&cli.StringFlag{
+				Name:  "kms-provider",
+				Usage: "Set the KMS holding the signing key (aws or gcp)",
+			},
+			&cli.StringFlag{
+				Name:  "kms-key-id",
+				Usage: "Set the KMS key (AWS key ID, ARN or alias; GCP key version name)",
+			},
+			&cli.StringFlag{
+				Name:  "kms-region",
+				Usage: "Set the AWS region of the KMS key",
+			},
+			&cli.StringFlag{
+				Name:  "kms-endpoint",
+				Usage: "Set a custom KMS endpoint (e.g. a local emulator)",
+			},
+			&cli.StringFlag{
+				Name:  "exec-signer-command",
+				Usage: "Set an external program that signs hashes (JSON on stdin/stdout)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exec-signer-arg",
+				Usage: "Set an argument passed to the exec signer program (repeatable)",
+			},
+			&cli.StringFlag{
+				Name:  "exec-signer-address",
+				Usage: "Set the address of the exec signer's key (asked from the program if not set)",
+			},
+			&cli.BoolFlag{
+				Name:  "validate",
+				Usage: "Check the settings against the chain before saving them (see 'flickr context check')",
 			},
 		},
 		Action: contextSetAction,
@@ -64,13 +154,9 @@ func contextSetAction(c *cli.Context) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if cfg.CurrentContext == "" {
-		return fmt.Errorf("no current context set")
-	}
-
-	ctx, exists := cfg.Contexts[cfg.CurrentContext]
-	if !exists {
-		return fmt.Errorf("current context '%s' not found", cfg.CurrentContext)
+	contextName, ctx, err := cfg.Current()
+	if err != nil {
+		return err
 	}
 
 	updated := false
@@ -88,11 +174,33 @@ func contextSetAction(c *cli.Context) error {
 	}
 
 	if url := c.String("rpc-url"); url != "" {
+		// A new URL is checked against the pin rather than clearing it, and
+		// pins a context that isn't pinned yet. --chain-id re-pins without
+		// asking the URL.
+		if url != ctx.RPCURL && !c.IsSet("chain-id") {
+			chainID, err := eth.GetChainID(url)
+			if err != nil {
+				return fmt.Errorf("failed to get the chain of %s (set --chain-id to pin it without asking): %w", url, err)
+			}
+			if ctx.ChainID != 0 && ctx.ChainID != chainID {
+				return &eth.ChainMismatchError{Expected: ctx.ChainID, Actual: chainID}
+			}
+			if ctx.ChainID == 0 {
+				ctx.ChainID = chainID
+				log.Info("Pinned chain ID", zap.Uint64("chainId", chainID))
+			}
+		}
 		ctx.RPCURL = url
 		updated = true
 		log.Info("Updated RPC URL", zap.String("url", url))
 	}
 
+	if c.IsSet("chain-id") {
+		ctx.ChainID = c.Uint64("chain-id")
+		updated = true
+		log.Info("Pinned chain ID", zap.Uint64("chainId", ctx.ChainID))
+	}
+
 	if addr := c.String("release-manager"); addr != "" {
 		ctx.ReleaseManager = addr
 		updated = true
@@ -105,6 +213,18 @@ func contextSetAction(c *cli.Context) error {
 		log.Info("Updated container name prefix", zap.String("name", name))
 	}
 
+	if policyPath := c.String("policy"); policyPath != "" {
+		if _, err := policy.Load(policyPath); err != nil {
+			return err
+		}
+		if abs, err := filepath.Abs(policyPath); err == nil {
+			policyPath = abs
+		}
+		ctx.PolicyPath = policyPath
+		updated = true
+		log.Info("Updated attestation policy", zap.String("path", policyPath))
+	}
+
 	// Handle environment variables
 	envFlags := c.StringSlice("env")
 	if len(envFlags) > 0 {
@@ -126,20 +246,103 @@ func contextSetAction(c *cli.Context) error {
 		}
 	}
 
+	for _, key := range c.StringSlice("env-remove") {
+		if _, exists := ctx.EnvironmentVars[key]; !exists {
+			return fmt.Errorf("environment variable %s is not set in context '%s'", key, contextName)
+		}
+		delete(ctx.EnvironmentVars, key)
+		log.Info("Removed environment variable", zap.String("key", key))
+		updated = true
+	}
+	if len(ctx.EnvironmentVars) == 0 {
+		ctx.EnvironmentVars = nil
+	}
+
+	// Handle ports and volumes; unset them to start over
+	for _, port := range c.StringSlice("port") {
+		if !slices.Contains(ctx.Ports, port) {
+			ctx.Ports = append(ctx.Ports, port)
+		}
+		log.Info("Added port", zap.String("port", port))
+		updated = true
+	}
+	for _, volume := range c.StringSlice("volume") {
+		if !strings.Contains(volume, ":") {
+			return fmt.Errorf("invalid volume format: %s (expected SOURCE:TARGET)", volume)
+		}
+		if !slices.Contains(ctx.Volumes, volume) {
+			ctx.Volumes = append(ctx.Volumes, volume)
+		}
+		log.Info("Added volume", zap.String("volume", volume))
+		updated = true
+	}
+
+	// Handle registry mirrors
+	mirrorFlags := c.StringSlice("registry-mirror")
+	if len(mirrorFlags) > 0 {
+		if ctx.RegistryMirrors == nil {
+			ctx.RegistryMirrors = make(map[string]string)
+		}
+
+		for _, mirror := range mirrorFlags {
+			parts := strings.SplitN(mirror, "=", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("invalid registry-mirror format: %s (expected SOURCE=MIRROR)", mirror)
+			}
+
+			ctx.RegistryMirrors[parts[0]] = parts[1]
+			log.Info("Set registry mirror", zap.String("source", parts[0]), zap.String("mirror", parts[1]))
+			updated = true
+		}
+	}
+
 	// Handle signer configuration (mutually exclusive)
+	if c.String("ecdsa-private-key") != "" && c.String("ecdsa-private-key-ref") != "" {
+		return fmt.Errorf("--ecdsa-private-key and --ecdsa-private-key-ref are mutually exclusive")
+	}
+	if c.String("keystore-password") != "" && c.String("keystore-password-ref") != "" {
+		return fmt.Errorf("--keystore-password and --keystore-password-ref are mutually exclusive")
+	}
+
 	if privateKey := c.String("ecdsa-private-key"); privateKey != "" {
-		// Setting private key clears keystore settings
+		// Setting private key clears keystore settings. SaveConfig moves the
+		// key to a file and stores a reference to it.
 		ctx.ECDSAPrivateKey = privateKey
+		ctx.ECDSAPrivateKeyRef = ""
 		ctx.KeystorePath = ""
 		ctx.KeystorePassword = ""
+		ctx.KeystorePasswordRef = ""
+		ctx.RemoteSigner = nil
+		ctx.KMSSigner = nil
+		ctx.ExecSigner = nil
 		updated = true
 		log.Info("Updated ECDSA private key")
 	}
 
+	if keyRef := c.String("ecdsa-private-key-ref"); keyRef != "" {
+		if _, _, err := secrets.ParseRef(keyRef); err != nil {
+			return err
+		}
+		ctx.ECDSAPrivateKeyRef = keyRef
+		ctx.ECDSAPrivateKey = ""
+		ctx.KeystorePath = ""
+		ctx.KeystorePassword = ""
+		ctx.KeystorePasswordRef = ""
+		ctx.RemoteSigner = nil
+		ctx.KMSSigner = nil
+		ctx.ExecSigner = nil
+		updated = true
+		log.Info("Updated ECDSA private key reference", zap.String("ref", keyRef))
+	}
+
 	if keystorePath := c.String("keystore-path"); keystorePath != "" {
 		// Setting keystore clears private key
 		ctx.KeystorePath = keystorePath
 		ctx.ECDSAPrivateKey = ""
+		ctx.ECDSAPrivateKeyRef = ""
+		ctx.RemoteSigner = nil
+		ctx.KMSSigner = nil
+		ctx.ExecSigner = nil
 		updated = true
 		log.Info("Updated keystore path", zap.String("path", keystorePath))
 	}
@@ -149,18 +352,161 @@ func contextSetAction(c *cli.Context) error {
 			return fmt.Errorf("keystore-password requires keystore-path to be set")
 		}
 		ctx.KeystorePassword = keystorePassword
+		ctx.KeystorePasswordRef = ""
 		updated = true
 		log.Info("Updated keystore password")
 	}
 
+	if passwordRef := c.String("keystore-password-ref"); passwordRef != "" {
+		if ctx.KeystorePath == "" {
+			return fmt.Errorf("keystore-password-ref requires keystore-path to be set")
+		}
+		if _, _, err := secrets.ParseRef(passwordRef); err != nil {
+			return err
+		}
+		ctx.KeystorePasswordRef = passwordRef
+		ctx.KeystorePassword = ""
+		updated = true
+		log.Info("Updated keystore password reference", zap.String("ref", passwordRef))
+	}
+
+	if c.IsSet("remote-signer-url") || c.IsSet("remote-signer-address") || c.IsSet("remote-signer-ca-cert") ||
+		c.IsSet("remote-signer-client-cert") || c.IsSet("remote-signer-client-key") {
+		// Setting a remote signer clears local keys
+		remote := ctx.RemoteSigner
+		if remote == nil {
+			remote = &config.RemoteSigner{}
+		}
+		if c.IsSet("remote-signer-url") {
+			remote.URL = c.String("remote-signer-url")
+		}
+		if c.IsSet("remote-signer-address") {
+			remote.Address = c.String("remote-signer-address")
+		}
+		for flag, field := range map[string]*string{
+			"remote-signer-ca-cert":     &remote.CACert,
+			"remote-signer-client-cert": &remote.ClientCert,
+			"remote-signer-client-key":  &remote.ClientKey,
+		} {
+			if !c.IsSet(flag) {
+				continue
+			}
+			path := c.String(flag)
+			if path != "" {
+				if abs, err := filepath.Abs(path); err == nil {
+					path = abs
+				}
+			}
+			*field = path
+		}
+
+		if remote.URL == "" || remote.Address == "" {
+			return fmt.Errorf("remote signer requires both --remote-signer-url and --remote-signer-address")
+		}
+		if !common.IsHexAddress(remote.Address) {
+			return fmt.Errorf("invalid remote signer address: %s", remote.Address)
+		}
+		if (remote.ClientCert == "") != (remote.ClientKey == "") {
+			return fmt.Errorf("--remote-signer-client-cert and --remote-signer-client-key must be set together")
+		}
+
+		ctx.RemoteSigner = remote
+		ctx.KMSSigner = nil
+		ctx.ExecSigner = nil
+		ctx.ECDSAPrivateKey = ""
+		ctx.ECDSAPrivateKeyRef = ""
+		ctx.KeystorePath = ""
+		ctx.KeystorePassword = ""
+		ctx.KeystorePasswordRef = ""
+		updated = true
+		log.Info("Updated remote signer", zap.String("url", remote.URL), zap.String("address", remote.Address))
+	}
+
+	if c.IsSet("kms-provider") || c.IsSet("kms-key-id") || c.IsSet("kms-region") || c.IsSet("kms-endpoint") {
+		// Setting a KMS signer clears local keys and the remote signer
+		kms := ctx.KMSSigner
+		if kms == nil {
+			kms = &config.KMSSigner{}
+		}
+		for flag, field := range map[string]*string{
+			"kms-provider": &kms.Provider,
+			"kms-key-id":   &kms.KeyID,
+			"kms-region":   &kms.Region,
+			"kms-endpoint": &kms.Endpoint,
+		} {
+			if c.IsSet(flag) {
+				*field = c.String(flag)
+			}
+		}
+
+		if kms.Provider != config.KMSProviderAWS && kms.Provider != config.KMSProviderGCP {
+			return fmt.Errorf("--kms-provider must be %s or %s", config.KMSProviderAWS, config.KMSProviderGCP)
+		}
+		if kms.KeyID == "" {
+			return fmt.Errorf("KMS signer requires --kms-key-id")
+		}
+
+		ctx.KMSSigner = kms
+		ctx.RemoteSigner = nil
+		ctx.ExecSigner = nil
+		ctx.ECDSAPrivateKey = ""
+		ctx.ECDSAPrivateKeyRef = ""
+		ctx.KeystorePath = ""
+		ctx.KeystorePassword = ""
+		ctx.KeystorePasswordRef = ""
+		updated = true
+		log.Info("Updated KMS signer", zap.String("provider", kms.Provider), zap.String("key", kms.KeyID))
+	}
+
+	if c.IsSet("exec-signer-command") || c.IsSet("exec-signer-arg") || c.IsSet("exec-signer-address") {
+		// Setting an exec signer clears every other signer
+		execSigner := ctx.ExecSigner
+		if execSigner == nil {
+			execSigner = &config.ExecSigner{}
+		}
+		if c.IsSet("exec-signer-command") {
+			execSigner.Command = c.String("exec-signer-command")
+		}
+		if c.IsSet("exec-signer-arg") {
+			execSigner.Args = c.StringSlice("exec-signer-arg")
+		}
+		if c.IsSet("exec-signer-address") {
+			execSigner.Address = c.String("exec-signer-address")
+		}
+
+		if execSigner.Command == "" {
+			return fmt.Errorf("exec signer requires --exec-signer-command")
+		}
+		if execSigner.Address != "" && !common.IsHexAddress(execSigner.Address) {
+			return fmt.Errorf("invalid exec signer address: %s", execSigner.Address)
+		}
+
+		ctx.ExecSigner = execSigner
+		ctx.KMSSigner = nil
+		ctx.RemoteSigner = nil
+		ctx.ECDSAPrivateKey = ""
+		ctx.ECDSAPrivateKeyRef = ""
+		ctx.KeystorePath = ""
+		ctx.KeystorePassword = ""
+		ctx.KeystorePasswordRef = ""
+		updated = true
+		log.Info("Updated exec signer", zap.String("command", execSigner.Command))
+	}
+
 	if !updated {
 		return fmt.Errorf("no values provided to update")
 	}
 
+	if c.Bool("validate") {
+		if err := checkContext(c, ctx); err != nil {
+			return fmt.Errorf("context '%s' not updated: %w", contextName, err)
+		}
+	}
+
 	if err := config.SaveConfig(cfg); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("Context '%s' updated\n", cfg.CurrentContext)
+	fmt.Printf("Context '%s' updated\n", contextName)
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,98 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/yourorg/flickr/internal/config"
+)
+
+// newRPCServer serves eth_chainId for the given chain
+func newRPCServer(t *testing.T, chainID string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": chainID})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// setupConfig points the config at a temporary file holding ctx as the
+// current context "test"
+func setupConfig(t *testing.T, ctx *config.Context) {
+	config.SetConfigPath(filepath.Join(t.TempDir(), "config.json"))
+	t.Cleanup(func() { config.SetConfigPath("") })
+	t.Setenv(config.EnvContext, "")
+	require.NoError(t, config.SaveConfig(&config.Config{
+		CurrentContext: "test",
+		Contexts:       map[string]*config.Context{"test": ctx},
+	}))
+}
+
+func runSet(t *testing.T, args ...string) error {
+	app := &cli.App{
+		Writer:    io.Discard,
+		ErrWriter: io.Discard,
+		Commands:  []*cli.Command{setCommand()},
+	}
+	return app.RunContext(context.Background(), append([]string{"flickr", "set"}, args...))
+}
+
+func loadContext(t *testing.T) *config.Context {
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	return cfg.Contexts["test"]
+}
+
+func TestContextSet_RPCURLPinsChain(t *testing.T) {
+	sepolia := newRPCServer(t, "0xaa36a7")
+	mainnet := newRPCServer(t, "0x1")
+
+	t.Run("Pins an unpinned context", func(t *testing.T) {
+		setupConfig(t, &config.Context{})
+		require.NoError(t, runSet(t, "--rpc-url", sepolia.URL))
+		ctx := loadContext(t)
+		assert.Equal(t, sepolia.URL, ctx.RPCURL)
+		assert.Equal(t, uint64(11155111), ctx.ChainID)
+	})
+
+	t.Run("Keeps the pin for the same chain", func(t *testing.T) {
+		setupConfig(t, &config.Context{RPCURL: "https://old.example.com", ChainID: 11155111})
+		require.NoError(t, runSet(t, "--rpc-url", sepolia.URL))
+		assert.Equal(t, uint64(11155111), loadContext(t).ChainID)
+	})
+
+	t.Run("Refuses another chain", func(t *testing.T) {
+		setupConfig(t, &config.Context{RPCURL: "https://old.example.com", ChainID: 11155111})
+		err := runSet(t, "--rpc-url", mainnet.URL)
+		assert.ErrorContains(t, err, "but the context is pinned to")
+		assert.Equal(t, "https://old.example.com", loadContext(t).RPCURL)
+	})
+
+	t.Run("Re-pins with --chain-id", func(t *testing.T) {
+		setupConfig(t, &config.Context{RPCURL: "https://old.example.com", ChainID: 11155111})
+		require.NoError(t, runSet(t, "--rpc-url", mainnet.URL, "--chain-id", "1"))
+		ctx := loadContext(t)
+		assert.Equal(t, mainnet.URL, ctx.RPCURL)
+		assert.Equal(t, uint64(1), ctx.ChainID)
+	})
+
+	t.Run("Unreachable RPC URL", func(t *testing.T) {
+		setupConfig(t, &config.Context{})
+		err := runSet(t, "--rpc-url", "http://127.0.0.1:1")
+		assert.ErrorContains(t, err, "set --chain-id to pin it without asking")
+	})
+}
@@ -11,35 +11,49 @@ import (
 
 func showCommand() *cli.Command {
 	return &cli.Command{
-		Name:   "show",
-		Usage:  "Show current context details",
+		Name:  "show",
+		Usage: "Show current context details",
+		Description: `Shows the effective context: the current context of the config file, with the
+project config (` + config.ProjectFileName + `) of the working directory layered over it.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "origin",
+				Usage: "Show the file each value comes from",
+			},
+		},
 		Action: contextShowAction,
 	}
 }
 
 func contextShowAction(c *cli.Context) error {
-	cfg, err := config.LoadConfig()
+	_, effective, err := config.LoadEffective()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if cfg.CurrentContext == "" {
+	if effective.Context == nil {
 		return fmt.Errorf("no current context set")
 	}
 
-	ctx, exists := cfg.Contexts[cfg.CurrentContext]
-	if !exists {
-		return fmt.Errorf("current context '%s' not found", cfg.CurrentContext)
-	}
-
 	// Use the ToMap method to get the context data
 	data := map[string]interface{}{
-		"current-context": cfg.CurrentContext,
-		"context":         ctx.ToMap(),
+		"current-context": effective.Name,
+		"context":         effective.Context.ToMap(),
+	}
+	if c.Bool("origin") {
+		data["origins"] = effective.Origins
 	}
 
 	encoder := yaml.NewEncoder(os.Stdout)
 	defer encoder.Close()
 
-	return encoder.Encode(data)
+	if err := encoder.Encode(data); err != nil {
+		return err
+	}
+
+	// Without a pin, nothing checks the chain the RPC URL serves
+	if effective.Context.RPCURL != "" && effective.Context.ChainID == 0 {
+		fmt.Fprintf(os.Stderr, "Note: the context is not pinned to a chain; pin it with 'flickr context check'\n")
+	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,56 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func unsetCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "unset",
+		Usage:       "Clear fields of the current context",
+		ArgsUsage:   "<field>...",
+		Description: "Fields: " + strings.Join(config.UnsetFields, ", ") + `
+
+"signer" clears every signer setting.`,
+		Action: contextUnsetAction,
+	}
+}
+
+func contextUnsetAction(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	log := middleware.GetLogger(c)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	contextName, ctx, err := cfg.Current()
+	if err != nil {
+		return err
+	}
+
+	// Nothing is saved unless every field is valid
+	for _, field := range c.Args().Slice() {
+		if err := ctx.Unset(field); err != nil {
+			return err
+		}
+		log.Info("Unset field", zap.String("field", field))
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("Context '%s' updated\n", contextName)
+	return nil
+}
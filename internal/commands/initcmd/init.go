@@ -0,0 +1,398 @@
+package initcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/keys"
+	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/secrets"
+	"go.uber.org/zap"
+)
+
+// Signer types offered by the wizard
+const (
+	signerNewKey    = "new-key"
+	signerImportKey = "import-key"
+	signerKeystore  = "keystore"
+	signerNone      = "none"
+)
+
+// networkOther is the network choice for chains without defaults
+const networkOther = "other"
+
+// Command returns the init command
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "init",
+		Usage: "Set up a context interactively",
+		Description: `Asks for the network, RPC URL, AVS address, operator set and signer, tests
+them against the chain and writes a validated context. A new key is generated or
+a private key imported into ~/.flickr/keystore, with the password prompted for
+without echoing.
+
+Flags answer the matching questions. With --yes nothing is asked: unanswered
+questions take their defaults, and the command fails if a required one has none.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Don't prompt; take answers from flags and defaults",
+			},
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "Name of the context (default: the network name)",
+			},
+			&cli.StringFlag{
+				Name:  "network",
+				Usage: "Network: mainnet, sepolia, local or other",
+				Value: "sepolia",
+			},
+			&cli.StringFlag{
+				Name:  "rpc-url",
+				Usage: "Ethereum RPC URL",
+			},
+			&cli.StringFlag{
+				Name:  "release-manager",
+				Usage: "ReleaseManager contract address (uses chain default if not provided)",
+			},
+			&cli.StringFlag{
+				Name:  "avs-address",
+				Usage: "AVS contract address",
+			},
+			&cli.Uint64Flag{
+				Name:  "operator-set-id",
+				Usage: "Operator set ID",
+			},
+			&cli.StringFlag{
+				Name:  "signer",
+				Usage: "Signer: new-key, import-key, keystore or none",
+				Value: signerNewKey,
+			},
+			&cli.StringFlag{
+				Name:  "keystore-path",
+				Usage: "Existing keystore file (with --signer keystore)",
+			},
+			&cli.StringFlag{
+				Name:  "private-key-ref",
+				Usage: "Read the private key to import from a reference (" + secrets.RefHelp + ")",
+			},
+			&cli.StringFlag{
+				Name:  "password-ref",
+				Usage: "Read the keystore password from a reference instead of prompting; saved in the context",
+			},
+			&cli.BoolFlag{
+				Name:  "skip-check",
+				Usage: "Don't check the AVS and operator set against the chain",
+			},
+			&cli.BoolFlag{
+				Name:  "use",
+				Usage: "Set as current context",
+				Value: true,
+			},
+		},
+		Action: initAction,
+	}
+}
+
+func initAction(c *cli.Context) error {
+	log := middleware.GetLogger(c)
+	p := newPrompter(c.Bool("yes"))
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Network and RPC URL, tested live
+	network, err := askNetwork(p, c.String("network"))
+	if err != nil {
+		return err
+	}
+	var chainID uint64
+	rpcURL, err := p.ask("RPC URL", "rpc-url", c.String("rpc-url"), func(url string) error {
+		id, err := eth.GetChainID(url)
+		if err != nil {
+			return fmt.Errorf("RPC URL did not respond: %w", err)
+		}
+		if network.ChainID != 0 && id != network.ChainID {
+			return fmt.Errorf("RPC URL serves %s, expected %s", eth.Network{ChainID: id}, network)
+		}
+		chainID = id
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "  Connected to %s\n", eth.Network{ChainID: chainID})
+
+	ctx := &config.Context{RPCURL: rpcURL, ChainID: chainID}
+
+	// ReleaseManager, asked only for chains without a default
+	ctx.ReleaseManager = c.String("release-manager")
+	if ctx.ReleaseManager == "" && !hasDefaultReleaseManager(chainID) {
+		ctx.ReleaseManager, err = p.ask("ReleaseManager address (no default for this chain)", "release-manager", "", checkAddress)
+		if err != nil {
+			return err
+		}
+	} else if ctx.ReleaseManager != "" {
+		if err := checkAddress(ctx.ReleaseManager); err != nil {
+			return fmt.Errorf("--release-manager: %w", err)
+		}
+	}
+
+	// Operator set
+	ctx.AVSAddress, err = p.ask("AVS address", "avs-address", c.String("avs-address"), checkAddress)
+	if err != nil {
+		return err
+	}
+	operatorSet, err := p.ask("Operator set ID", "operator-set-id", strconv.FormatUint(c.Uint64("operator-set-id"), 10), func(answer string) error {
+		_, err := strconv.ParseUint(answer, 10, 32)
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", answer)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	id, _ := strconv.ParseUint(operatorSet, 10, 32)
+	ctx.OperatorSetID = uint32(id)
+
+	// Check the settings against the chain before creating keys
+	if !c.Bool("skip-check") {
+		if err := checkContext(p, ctx); err != nil {
+			return err
+		}
+	}
+
+	// Context name
+	defaultName := network.Name
+	if defaultName == networkOther {
+		defaultName = "default"
+	}
+	if c.String("name") != "" {
+		defaultName = c.String("name")
+	}
+	name, err := p.ask("Context name", "name", defaultName, func(answer string) error {
+		if _, exists := cfg.Contexts[answer]; exists {
+			return fmt.Errorf("context '%s' already exists", answer)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Signer
+	key, err := setupSigner(c, p, ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg.Contexts[name] = ctx
+	if c.Bool("use") {
+		cfg.CurrentContext = name
+	}
+
+	// SaveConfig validates the context. A key generated for a context that
+	// could not be saved is removed again.
+	if err := config.SaveConfig(cfg); err != nil {
+		if key != nil {
+			os.Remove(key.Path)
+		}
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	log.Info("Context created",
+		zap.String("name", name),
+		zap.Uint64("chainId", chainID),
+		zap.Bool("current", c.Bool("use")))
+
+	fmt.Printf("\nContext '%s' created\n", name)
+	fmt.Printf("Network: %s\n", eth.Network{ChainID: chainID})
+	fmt.Printf("AVS: %s\n", ctx.AVSAddress)
+	fmt.Printf("Operator Set: %d\n", ctx.OperatorSetID)
+	if key != nil {
+		fmt.Printf("Signer: %s (%s)\n", key.Address.Hex(), key.Path)
+	}
+	if c.Bool("use") {
+		fmt.Printf("Current context set to '%s'\n", name)
+	}
+
+	fmt.Println("\nNext steps:")
+	fmt.Println("  flickr pull          # Pull the latest release")
+	fmt.Println("  flickr run --detach  # Run it")
+	if ctx.KeystorePath != "" && ctx.KeystorePasswordRef == "" {
+		fmt.Printf("\nThe keystore password is prompted for when signing. To read it from %s, run:\n", secrets.RefHelp)
+		fmt.Printf("  flickr context set --keystore-password-ref <ref>\n")
+	}
+
+	return nil
+}
+
+// askNetwork asks for one of the networks with default contracts, or other
+func askNetwork(p *prompter, def string) (eth.Network, error) {
+	options := make([]string, 0, len(eth.Networks)+1)
+	descriptions := make([]string, 0, len(eth.Networks)+1)
+	for _, n := range eth.Networks {
+		options = append(options, n.Name)
+		descriptions = append(descriptions, n.String())
+	}
+	options = append(options, networkOther)
+	descriptions = append(descriptions, "Another chain (enter the ReleaseManager address)")
+
+	choice, err := p.choose("Network", "network", def, options, descriptions)
+	if err != nil {
+		return eth.Network{}, err
+	}
+	if choice == networkOther {
+		return eth.Network{Name: networkOther}, nil
+	}
+	return eth.LookupNetwork(choice)
+}
+
+// hasDefaultReleaseManager reports whether the chain has a ReleaseManager
+// deployed at a default address
+func hasDefaultReleaseManager(chainID uint64) bool {
+	defaults, err := eth.GetDefaultContractAddresses(chainID)
+	return err == nil && common.HexToAddress(defaults.ReleaseManager) != (common.Address{})
+}
+
+func checkAddress(address string) error {
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("invalid address: %s", address)
+	}
+	return nil
+}
+
+// checkContext checks the settings against the chain. Failed checks can be
+// accepted interactively, e.g. for an AVS that hasn't created its operator
+// set yet.
+func checkContext(p *prompter, ctx *config.Context) error {
+	fmt.Fprintln(p.out)
+	report := eth.RunCheck(p.out, ctx)
+
+	if report.Failed() == 0 {
+		return nil
+	}
+	if p.yes {
+		return fmt.Errorf("context check failed: %d of %d checks did not pass (use --skip-check to create the context anyway)", report.Failed(), len(report.Checks))
+	}
+	ok, err := p.confirm("Some checks failed. Create the context anyway?", false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("setup cancelled")
+	}
+	return nil
+}
+
+// setupSigner configures the signer of ctx and returns the key it stored in
+// the keystore, if any
+func setupSigner(c *cli.Context, p *prompter, ctx *config.Context) (*keys.Key, error) {
+	signerType, err := p.choose("Signer", "signer", c.String("signer"),
+		[]string{signerNewKey, signerImportKey, signerKeystore, signerNone},
+		[]string{
+			"Generate a new key in the keystore",
+			"Import a hex private key into the keystore",
+			"Use an existing keystore file",
+			"No signer (pull and run only; set one later with 'flickr context set')",
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	switch signerType {
+	case signerNone:
+		return nil, nil
+	case signerKeystore:
+		path, err := p.ask("Keystore file", "keystore-path", c.String("keystore-path"), func(path string) error {
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("keystore file not found: %s", path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if ref := c.String("password-ref"); ref != "" {
+			if _, _, err := secrets.ParseRef(ref); err != nil {
+				return nil, err
+			}
+		}
+		ctx.KeystorePath = path
+		ctx.KeystorePasswordRef = c.String("password-ref")
+		return nil, nil
+	}
+
+	dir, err := keys.Dir()
+	if err != nil {
+		return nil, err
+	}
+	ks, err := keys.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var privateKey string
+	if signerType == signerImportKey {
+		if ref := c.String("private-key-ref"); ref != "" {
+			privateKey, err = secrets.Resolve(ref)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve private key: %w", err)
+			}
+		} else {
+			privateKey, err = p.secret("Private key (hex): ", "private-key-ref", false)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	password, err := keystorePassword(c, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var key keys.Key
+	if signerType == signerImportKey {
+		key, err = ks.Import(privateKey, password)
+	} else {
+		key, err = ks.New(password)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	middleware.GetLogger(c).Info("Key stored", zap.String("address", key.Address.Hex()), zap.String("path", key.Path))
+	ctx.KeystorePath = key.Path
+	ctx.KeystorePasswordRef = c.String("password-ref")
+	return &key, nil
+}
+
+// keystorePassword returns the password from --password-ref, or prompts for
+// it without echoing
+func keystorePassword(c *cli.Context, p *prompter) (string, error) {
+	if ref := c.String("password-ref"); ref != "" {
+		if _, _, err := secrets.ParseRef(ref); err != nil {
+			return "", err
+		}
+		password, err := secrets.Resolve(ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve password: %w", err)
+		}
+		return password, nil
+	}
+	return p.secret("Keystore password: ", "password-ref", true)
+}
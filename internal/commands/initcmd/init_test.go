@@ -0,0 +1,113 @@
+package initcmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/yourorg/flickr/internal/config"
+)
+
+// newRPCServer serves eth_chainId for the given chain
+func newRPCServer(t *testing.T, chainID string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method != "eth_chainId" {
+			json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": map[string]any{"code": -32601, "message": "method not found"}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": chainID})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func runInit(t *testing.T, args ...string) error {
+	app := &cli.App{
+		Writer:    io.Discard,
+		ErrWriter: io.Discard,
+		Commands:  []*cli.Command{Command()},
+	}
+	return app.RunContext(context.Background(), append([]string{"flickr", "init"}, args...))
+}
+
+func TestInit_Yes(t *testing.T) {
+	config.SetConfigPath(filepath.Join(t.TempDir(), "config.json"))
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	sepolia := newRPCServer(t, "0xaa36a7")
+	avs := "0x1234567890123456789012345678901234567890"
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "Missing RPC URL",
+			args:    []string{"--yes"},
+			wantErr: "--rpc-url is required with --yes",
+		},
+		{
+			name:    "Unknown network",
+			args:    []string{"--yes", "--network", "holesky"},
+			wantErr: "--network: expected one of",
+		},
+		{
+			name:    "Wrong chain",
+			args:    []string{"--yes", "--network", "mainnet", "--rpc-url", sepolia.URL},
+			wantErr: "--rpc-url: RPC URL serves Sepolia",
+		},
+		{
+			name:    "Missing AVS address",
+			args:    []string{"--yes", "--rpc-url", sepolia.URL},
+			wantErr: "--avs-address is required with --yes",
+		},
+		{
+			name:    "Missing keystore password",
+			args:    []string{"--yes", "--rpc-url", sepolia.URL, "--avs-address", avs, "--skip-check"},
+			wantErr: "--password-ref is required with --yes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runInit(t, tt.args...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			// Nothing is saved when setup fails
+			cfg, err := config.LoadConfig()
+			require.NoError(t, err)
+			assert.Empty(t, cfg.Contexts)
+		})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		err := runInit(t, "--yes", "--rpc-url", sepolia.URL, "--avs-address", avs, "--operator-set-id", "2", "--skip-check", "--signer", "none")
+		require.NoError(t, err)
+
+		cfg, err := config.LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "sepolia", cfg.CurrentContext)
+		require.Contains(t, cfg.Contexts, "sepolia")
+		ctx := cfg.Contexts["sepolia"]
+		assert.Equal(t, sepolia.URL, ctx.RPCURL)
+		assert.Equal(t, uint64(11155111), ctx.ChainID)
+		assert.Equal(t, avs, ctx.AVSAddress)
+		assert.Equal(t, uint32(2), ctx.OperatorSetID)
+	})
+}
@@ -0,0 +1,170 @@
+package initcmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// prompter asks the questions of the setup wizard. With yes set, every
+// question is answered with its default, and a question without one fails
+// naming the flag that answers it.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	yes bool
+}
+
+func newPrompter(yes bool) *prompter {
+	return &prompter{in: bufio.NewReader(os.Stdin), out: os.Stderr, yes: yes}
+}
+
+// ask asks a question until the answer passes check. An empty answer takes
+// the default; flag is the flag that answers the question with --yes.
+func (p *prompter) ask(question, flag, def string, check func(string) error) (string, error) {
+	if p.yes {
+		if def == "" {
+			return "", fmt.Errorf("--%s is required with --yes", flag)
+		}
+		if err := check(def); err != nil {
+			return "", fmt.Errorf("--%s: %w", flag, err)
+		}
+		return def, nil
+	}
+
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", question)
+		}
+
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+		if answer == "" {
+			fmt.Fprintf(p.out, "  A value is required\n")
+			continue
+		}
+		if err := check(answer); err != nil {
+			fmt.Fprintf(p.out, "  %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// choose asks for one of options, by number or name
+func (p *prompter) choose(question, flag, def string, options []string, descriptions []string) (string, error) {
+	if !p.yes {
+		fmt.Fprintf(p.out, "%s\n", question)
+		for i, option := range options {
+			fmt.Fprintf(p.out, "  %d) %-12s %s\n", i+1, option, descriptions[i])
+		}
+	}
+
+	var choice string
+	_, err := p.ask("Choose", flag, def, func(answer string) error {
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			choice = options[n-1]
+			return nil
+		}
+		for _, option := range options {
+			if answer == option {
+				choice = option
+				return nil
+			}
+		}
+		return fmt.Errorf("expected one of: %s", strings.Join(options, ", "))
+	})
+	return choice, err
+}
+
+// confirm asks a yes/no question
+func (p *prompter) confirm(question string, def bool) (bool, error) {
+	if p.yes {
+		return def, nil
+	}
+
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, hint)
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// secret reads a value without echoing it on a terminal. With confirm set,
+// a value typed on a terminal must be entered twice.
+func (p *prompter) secret(prompt, flag string, confirm bool) (string, error) {
+	if p.yes {
+		return "", fmt.Errorf("--%s is required with --yes", flag)
+	}
+
+	value, err := p.readSecret(prompt)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", fmt.Errorf("value must not be empty")
+	}
+
+	if confirm && term.IsTerminal(int(os.Stdin.Fd())) {
+		again, err := p.readSecret("Confirm: ")
+		if err != nil {
+			return "", err
+		}
+		if again != value {
+			return "", fmt.Errorf("values do not match")
+		}
+	}
+	return value, nil
+}
+
+func (p *prompter) readSecret(prompt string) (string, error) {
+	fmt.Fprint(p.out, prompt)
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(p.out)
+		if err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+		return string(value), nil
+	}
+	return p.readLine()
+}
+
+func (p *prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		if err == io.EOF {
+			return "", fmt.Errorf("setup aborted: no more input (use --yes with flags to run without prompts)")
+		}
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,150 @@
+package initcmd
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPrompter returns a prompter reading input and the output it writes
+func testPrompter(input string, yes bool) (*prompter, *strings.Builder) {
+	out := &strings.Builder{}
+	return &prompter{in: bufio.NewReader(strings.NewReader(input)), out: out, yes: yes}, out
+}
+
+func checkNumber(answer string) error {
+	if strings.Trim(answer, "0123456789") != "" {
+		return fmt.Errorf("expected a number, got %q", answer)
+	}
+	return nil
+}
+
+func TestPrompter_Ask(t *testing.T) {
+	t.Run("Answer", func(t *testing.T) {
+		p, out := testPrompter("42\n", false)
+		answer, err := p.ask("Operator set ID", "operator-set-id", "0", checkNumber)
+		require.NoError(t, err)
+		assert.Equal(t, "42", answer)
+		assert.Equal(t, "Operator set ID [0]: ", out.String())
+	})
+
+	t.Run("Empty answer takes the default", func(t *testing.T) {
+		p, _ := testPrompter("\n", false)
+		answer, err := p.ask("Operator set ID", "operator-set-id", "7", checkNumber)
+		require.NoError(t, err)
+		assert.Equal(t, "7", answer)
+	})
+
+	t.Run("Asks again until the answer is valid", func(t *testing.T) {
+		p, out := testPrompter("\nabc\n  3  \n", false)
+		answer, err := p.ask("Operator set ID", "operator-set-id", "", checkNumber)
+		require.NoError(t, err)
+		assert.Equal(t, "3", answer)
+		assert.Contains(t, out.String(), "A value is required")
+		assert.Contains(t, out.String(), `expected a number, got "abc"`)
+	})
+
+	t.Run("Input ends", func(t *testing.T) {
+		p, _ := testPrompter("abc\n", false)
+		_, err := p.ask("Operator set ID", "operator-set-id", "", checkNumber)
+		assert.ErrorContains(t, err, "setup aborted: no more input")
+	})
+}
+
+func TestPrompter_Yes(t *testing.T) {
+	p, out := testPrompter("", true)
+
+	answer, err := p.ask("Operator set ID", "operator-set-id", "5", checkNumber)
+	require.NoError(t, err)
+	assert.Equal(t, "5", answer)
+
+	_, err = p.ask("AVS address", "avs-address", "", checkAddress)
+	assert.EqualError(t, err, "--avs-address is required with --yes")
+
+	_, err = p.ask("AVS address", "avs-address", "0x123", checkAddress)
+	assert.EqualError(t, err, "--avs-address: invalid address: 0x123")
+
+	ok, err := p.confirm("Create the context anyway?", false)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = p.secret("Keystore password: ", "password-ref", true)
+	assert.EqualError(t, err, "--password-ref is required with --yes")
+
+	// Nothing is asked
+	assert.Empty(t, out.String())
+}
+
+func TestPrompter_Choose(t *testing.T) {
+	options := []string{"new-key", "keystore", "none"}
+	descriptions := []string{"Generate a key", "Use a keystore file", "No signer"}
+
+	tests := []struct {
+		name     string
+		input    string
+		def      string
+		expected string
+	}{
+		{name: "By number", input: "2\n", def: "new-key", expected: "keystore"},
+		{name: "By name", input: "none\n", def: "new-key", expected: "none"},
+		{name: "Default", input: "\n", def: "new-key", expected: "new-key"},
+		{name: "Asks again", input: "4\nother\n3\n", def: "new-key", expected: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, out := testPrompter(tt.input, false)
+			choice, err := p.choose("Signer", "signer", tt.def, options, descriptions)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, choice)
+			assert.Contains(t, out.String(), "  2) keystore     Use a keystore file")
+		})
+	}
+
+	t.Run("Invalid default with --yes", func(t *testing.T) {
+		p, _ := testPrompter("", true)
+		_, err := p.choose("Signer", "signer", "hsm", options, descriptions)
+		assert.EqualError(t, err, "--signer: expected one of: new-key, keystore, none")
+	})
+}
+
+func TestPrompter_Confirm(t *testing.T) {
+	tests := []struct {
+		input    string
+		def      bool
+		expected bool
+	}{
+		{input: "y\n", expected: true},
+		{input: "YES\n", expected: true},
+		{input: "n\n", def: true, expected: false},
+		{input: "\n", def: true, expected: true},
+		{input: "\n", expected: false},
+		{input: "maybe\ny\n", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.TrimSpace(tt.input), func(t *testing.T) {
+			p, _ := testPrompter(tt.input, false)
+			ok, err := p.confirm("Continue?", tt.def)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
+func TestPrompter_Secret(t *testing.T) {
+	// Without a terminal the value is read as a line, and not asked twice
+	p, out := testPrompter("hunter2\n", false)
+	value, err := p.secret("Keystore password: ", "password-ref", true)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+	assert.Equal(t, "Keystore password: ", out.String())
+
+	p, _ = testPrompter("\n", false)
+	_, err = p.secret("Keystore password: ", "password-ref", true)
+	assert.EqualError(t, err, "value must not be empty")
+}
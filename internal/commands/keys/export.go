@@ -0,0 +1,75 @@
+package keys
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func exportCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "export",
+		Usage:     "Export a key file re-encrypted with a new password",
+		ArgsUsage: "<address|key-file>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Write the key file here instead of stdout",
+			},
+			passwordRefFlag(),
+			&cli.StringFlag{
+				Name:  "new-password-ref",
+				Usage: "Read the password for the exported key from a reference instead of prompting",
+			},
+		},
+		Action: exportAction,
+	}
+}
+
+func exportAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+	log := middleware.GetLogger(c)
+
+	ks, err := openKeystore()
+	if err != nil {
+		return err
+	}
+
+	key, err := ks.Find(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	password, err := readPassword(c, "password-ref", fmt.Sprintf("Password for %s: ", key.Address.Hex()), false)
+	if err != nil {
+		return err
+	}
+	newPassword, err := readPassword(c, "new-password-ref", "Password for the exported key: ", true)
+	if err != nil {
+		return err
+	}
+
+	data, err := ks.Export(key, password, newPassword)
+	if err != nil {
+		return err
+	}
+
+	output := c.String("output")
+	if output == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	log.Info("Key exported", zap.String("address", key.Address.Hex()), zap.String("output", output))
+	fmt.Printf("Exported key %s to %s\n", key.Address.Hex(), output)
+	return nil
+}
@@ -0,0 +1,74 @@
+package keys
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/secrets"
+	"go.uber.org/zap"
+)
+
+func importCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "import",
+		Usage: "Import a hex-encoded private key",
+		Description: `Encrypts a private key into the keystore. Without --private-key or
+--private-key-ref the key is prompted for without echoing.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "private-key",
+				Usage: "Hex-encoded private key (visible in shell history, prefer the prompt or --private-key-ref)",
+			},
+			&cli.StringFlag{
+				Name:  "private-key-ref",
+				Usage: "Read the private key from a reference (" + secrets.RefHelp + ")",
+			},
+			passwordRefFlag(),
+		},
+		Action: importAction,
+	}
+}
+
+func importAction(c *cli.Context) error {
+	log := middleware.GetLogger(c)
+
+	if c.String("private-key") != "" && c.String("private-key-ref") != "" {
+		return fmt.Errorf("--private-key and --private-key-ref are mutually exclusive")
+	}
+
+	ks, err := openKeystore()
+	if err != nil {
+		return err
+	}
+
+	privateKey := c.String("private-key")
+	if ref := c.String("private-key-ref"); ref != "" {
+		privateKey, err = secrets.Resolve(ref)
+		if err != nil {
+			return fmt.Errorf("failed to resolve private key: %w", err)
+		}
+	}
+	if privateKey == "" {
+		key, err := secrets.ReadPassword("Private key (hex): ")
+		if err != nil {
+			return err
+		}
+		privateKey = string(key)
+	}
+
+	password, err := readPassword(c, "password-ref", "Password for the imported key: ", true)
+	if err != nil {
+		return err
+	}
+
+	key, err := ks.Import(privateKey, password)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Key imported", zap.String("address", key.Address.Hex()), zap.String("path", key.Path))
+	fmt.Printf("Imported key %s\n", key.Address.Hex())
+	fmt.Printf("  Path: %s\n", key.Path)
+	return nil
+}
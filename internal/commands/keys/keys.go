@@ -0,0 +1,78 @@
+package keys
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/keys"
+	"github.com/yourorg/flickr/internal/secrets"
+	"golang.org/x/term"
+)
+
+// Command returns the keys command
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "keys",
+		Usage: "Manage encrypted keys in ~/.flickr/keystore",
+		Description: `Creates, imports and exports keys in go-ethereum's keystore format. Passwords
+are prompted for without echoing, or read from a secret reference with
+--password-ref, so they never appear in shell history. Use a key with
+
+  flickr context set --keystore-path <path>`,
+		Subcommands: []*cli.Command{
+			newCommand(),
+			importCommand(),
+			listCommand(),
+			exportCommand(),
+		},
+	}
+}
+
+func passwordRefFlag() *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:  "password-ref",
+		Usage: "Read the key password from a reference (" + secrets.RefHelp + ") instead of prompting",
+	}
+}
+
+// openKeystore opens the default keystore directory
+func openKeystore() (*keys.Keystore, error) {
+	dir, err := keys.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return keys.Open(dir)
+}
+
+// readPassword returns the password from the reference in flag, or prompts
+// for it. With confirm set, a password typed on a terminal must be entered
+// twice.
+func readPassword(c *cli.Context, flag, prompt string, confirm bool) (string, error) {
+	if ref := c.String(flag); ref != "" {
+		password, err := secrets.Resolve(ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve password: %w", err)
+		}
+		return password, nil
+	}
+
+	password, err := secrets.ReadPassword(prompt)
+	if err != nil {
+		return "", err
+	}
+	if len(password) == 0 {
+		return "", fmt.Errorf("password must not be empty")
+	}
+
+	if confirm && term.IsTerminal(int(os.Stdin.Fd())) {
+		again, err := secrets.ReadPassword("Confirm password: ")
+		if err != nil {
+			return "", err
+		}
+		if string(again) != string(password) {
+			return "", fmt.Errorf("passwords do not match")
+		}
+	}
+	return string(password), nil
+}
@@ -0,0 +1,39 @@
+package keys
+
+import (
+	"fmt"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli/v2"
+)
+
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "list",
+		Usage:  "List keys in the keystore",
+		Action: listAction,
+	}
+}
+
+func listAction(c *cli.Context) error {
+	ks, err := openKeystore()
+	if err != nil {
+		return err
+	}
+
+	keys := ks.List()
+	if len(keys) == 0 {
+		fmt.Println("No keys found")
+		fmt.Println("\nTo create a key, run:")
+		fmt.Println("  flickr keys new")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(c.App.Writer)
+	table.Header("ADDRESS", "PATH")
+	for _, key := range keys {
+		table.Append([]string{key.Address.Hex(), key.Path})
+	}
+	table.Render()
+	return nil
+}
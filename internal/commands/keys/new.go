@@ -0,0 +1,44 @@
+package keys
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func newCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "new",
+		Usage:  "Generate a new key",
+		Flags:  []cli.Flag{passwordRefFlag()},
+		Action: newAction,
+	}
+}
+
+func newAction(c *cli.Context) error {
+	log := middleware.GetLogger(c)
+
+	ks, err := openKeystore()
+	if err != nil {
+		return err
+	}
+
+	password, err := readPassword(c, "password-ref", "Password for the new key: ", true)
+	if err != nil {
+		return err
+	}
+
+	key, err := ks.New(password)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Key created", zap.String("address", key.Address.Hex()), zap.String("path", key.Path))
+	fmt.Printf("Created key %s\n", key.Address.Hex())
+	fmt.Printf("  Path: %s\n", key.Path)
+	fmt.Println("\nTo sign with it, run:")
+	fmt.Printf("  flickr context set --keystore-path %s\n", key.Path)
+	return nil
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/yourorg/flickr/internal/config"
 	"github.com/yourorg/flickr/internal/eth"
 	"github.com/yourorg/flickr/internal/middleware"
-	"github.com/yourorg/flickr/internal/signer"
 	"go.uber.org/zap"
 )
 
@@ -30,6 +29,7 @@ func setCommand() *cli.Command {
 		Name:  "set",
 		Usage: "Set metadata URI for an operator set",
 		Flags: []cli.Flag{
+			middleware.KeystorePasswordFileFlag(),
 			&cli.StringFlag{
 				Name:     "uri",
 				Usage:    "Metadata URI (e.g., https://example.com/metadata.json)",
@@ -103,7 +103,7 @@ func setAction(c *cli.Context) error {
 	}
 
 	// Get signer from context
-	sig, err := signer.FromContext(currentCtx)
+	sig, err := middleware.GetSigner(c, currentCtx)
 	if err != nil {
 		return fmt.Errorf("no signer configured: %w", err)
 	}
@@ -130,8 +130,14 @@ func setAction(c *cli.Context) error {
 	}
 	defer rmClient.Close()
 
-	// Publish metadata URI
 	ctx := context.Background()
+
+	// Check the signer may publish metadata for the AVS
+	if err := rmClient.CheckPermission(ctx, avs, eth.PublishMetadataURIFunction); err != nil {
+		return err
+	}
+
+	// Publish metadata URI
 	tx, err := rmClient.PublishMetadataURI(ctx, avs, operatorSetID, uri, c.Uint64("gas-limit"))
 	if err != nil {
 		return fmt.Errorf("failed to publish metadata URI: %w", err)
@@ -201,45 +207,11 @@ func getAction(c *cli.Context) error {
 	return nil
 }
 
-// getConfig extracts configuration from flags or context
+// getConfig extracts configuration from flags, FLICKR_* variables or context
 func getConfig(c *cli.Context, currentCtx *config.Context) (string, uint32, string, common.Address, error) {
-	// Get AVS address (from flag or context)
-	avsAddress := c.String("avs")
-	if avsAddress == "" {
-		avsAddress = currentCtx.AVSAddress
-	}
-	if avsAddress == "" {
-		return "", 0, "", common.Address{}, fmt.Errorf("--avs is required (or set in context with 'flickr context set --avs-address')")
-	}
-
-	// Get operator set ID (from flag or context)
-	operatorSetID := uint32(c.Uint64("operator-set"))
-	if operatorSetID == 0 && c.IsSet("operator-set") {
-		// Explicitly set to 0
-	} else if operatorSetID == 0 {
-		operatorSetID = currentCtx.OperatorSetID
-	}
-
-	// Get RPC URL (from flag or context)
-	rpcURL := c.String("rpc-url")
-	if rpcURL == "" {
-		rpcURL = currentCtx.RPCURL
-	}
-	if rpcURL == "" {
-		return "", 0, "", common.Address{}, fmt.Errorf("--rpc-url is required (or set in context with 'flickr context set --rpc-url')")
-	}
-
-	// Get release manager address (from flag, context, or chain default)
-	releaseManager := c.String("release-manager")
-	if releaseManager == "" {
-		releaseManager = currentCtx.ReleaseManager
-	}
-
-	// Get the actual address (may use chain defaults)
-	rmAddr, err := eth.GetReleaseManagerAddress(rpcURL, releaseManager)
+	target, rmAddr, err := eth.ResolveTarget(c, currentCtx)
 	if err != nil {
-		return "", 0, "", common.Address{}, fmt.Errorf("failed to get ReleaseManager address: %w", err)
+		return "", 0, "", common.Address{}, err
 	}
-
-	return avsAddress, operatorSetID, rpcURL, rmAddr, nil
+	return target.AVS.Hex(), target.OperatorSetID, target.RPCURL, rmAddr, nil
 }
\ No newline at end of file
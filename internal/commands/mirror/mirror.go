@@ -0,0 +1,200 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/ref"
+	"github.com/yourorg/flickr/internal/registry"
+	"go.uber.org/zap"
+)
+
+// signatureSuffixes are the tag suffixes cosign uses to attach signatures,
+// attestations and SBOMs to an image digest (sha256-<hex><suffix>). The empty
+// suffix is the OCI referrers tag fallback.
+var signatureSuffixes = []string{".sig", ".att", ".sbom", ""}
+
+// Command returns the mirror command
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "mirror",
+		Usage: "Copy the artifacts of a release to another registry",
+		Description: `Copies every artifact of a release from the registry recorded on-chain to a
+target registry by digest, including multi-arch indexes and any attached signatures.
+Configure the context with --registry-mirror so pull and run fetch from the mirror.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "to",
+				Usage:    "Target registry (e.g., mirror.internal)",
+				Required: true,
+			},
+			&cli.Uint64Flag{
+				Name:  "release-id",
+				Usage: "Specific release ID to mirror (defaults to latest)",
+			},
+			&cli.StringFlag{
+				Name:  "avs",
+				Usage: "AVS contract address (uses context if not provided)",
+			},
+			&cli.Uint64Flag{
+				Name:  "operator-set",
+				Usage: "Operator set ID (uses context if not provided)",
+			},
+			&cli.StringFlag{
+				Name:  "release-manager",
+				Usage: "ReleaseManager contract address (uses chain default if not provided)",
+			},
+			&cli.StringFlag{
+				Name:  "rpc-url",
+				Usage: "Ethereum RPC URL (uses context if not provided)",
+			},
+		},
+		Action: mirrorAction,
+	}
+}
+
+func mirrorAction(c *cli.Context) error {
+	log := middleware.GetLogger(c)
+
+	// Get context
+	currentCtx, err := middleware.GetCurrentContext(c)
+	if err != nil {
+		currentCtx = &config.Context{}
+	}
+
+	// Resolve the target: flag, FLICKR_* variable, context, chain default
+	resolved, rmAddr, err := eth.ResolveTarget(c, currentCtx)
+	if err != nil {
+		return err
+	}
+	avsAddress, operatorSetID, rpcURL := resolved.AVS.Hex(), resolved.OperatorSetID, resolved.RPCURL
+
+	target := strings.TrimSuffix(c.String("to"), "/")
+	avs := common.HexToAddress(avsAddress)
+
+	log.Info("Using configuration",
+		zap.String("avs", avs.Hex()),
+		zap.Uint32("operatorSet", operatorSetID),
+		zap.String("releaseManager", rmAddr.Hex()),
+		zap.String("target", target))
+
+	rmClient, err := eth.NewClient(rpcURL, rmAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create Ethereum client: %w", err)
+	}
+	defer rmClient.Close()
+
+	ctx := context.Background()
+
+	var (
+		release   eth.Release
+		releaseID uint64
+	)
+	if c.IsSet("release-id") {
+		releaseID = c.Uint64("release-id")
+		release, err = rmClient.GetRelease(ctx, avs, operatorSetID, releaseID)
+		if err != nil {
+			return fmt.Errorf("failed to get release %d: %w", releaseID, err)
+		}
+	} else {
+		release, releaseID, err = rmClient.GetLatestRelease(ctx, avs, operatorSetID)
+		if err != nil {
+			return fmt.Errorf("failed to get latest release: %w", err)
+		}
+	}
+
+	if len(release.Artifacts) == 0 {
+		return fmt.Errorf("no artifacts in release %d", releaseID)
+	}
+
+	// Create Docker runner with the context's registry credentials
+	dockerRunner, cleanup, err := registry.NewRunner(currentCtx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	sources := make(map[string]string)
+	mirrored := make([]string, 0, len(release.Artifacts))
+
+	for i, artifact := range release.Artifacts {
+		digest := ref.Digest32ToSha256String(artifact.Digest32)
+
+		// Always mirrored by the on-chain digest; a registry embedding any
+		// other digest is refused
+		source, err := ref.BuildReference(artifact.Registry, digest)
+		if err != nil {
+			return fmt.Errorf("failed to build reference for artifact %d: %w", i, err)
+		}
+
+		srcRepo := strings.SplitN(source, "@", 2)[0]
+		dstRepo, err := ref.MirrorRepository(artifact.Registry, target)
+		if err != nil {
+			return fmt.Errorf("failed to build mirror repository for artifact %d: %w", i, err)
+		}
+
+		// Tag the copy so registries with tag retention policies keep it
+		hexDigest := strings.TrimPrefix(digest, "sha256:")
+		tag := "flickr-" + hexDigest[:12]
+
+		log.Info("Mirroring artifact",
+			zap.Int("artifact", i+1),
+			zap.Int("total", len(release.Artifacts)),
+			zap.String("source", source),
+			zap.String("target", dstRepo))
+
+		if err := dockerRunner.Copy(ctx, source, dstRepo+":"+tag); err != nil {
+			return fmt.Errorf("failed to mirror %s: %w", source, err)
+		}
+
+		// Confirm the mirror serves the on-chain digest
+		mirroredRef := dstRepo + "@" + digest
+		if !dockerRunner.RemoteExists(ctx, mirroredRef) {
+			return fmt.Errorf("mirrored image %s not found after copy (digest not preserved)", mirroredRef)
+		}
+
+		// Copy signatures and attestations attached to the digest, if any
+		for _, suffix := range signatureSuffixes {
+			sigTag := "sha256-" + hexDigest + suffix
+			if !dockerRunner.RemoteExists(ctx, srcRepo+":"+sigTag) {
+				continue
+			}
+			if err := dockerRunner.Copy(ctx, srcRepo+":"+sigTag, dstRepo+":"+sigTag); err != nil {
+				return fmt.Errorf("failed to mirror %s:%s: %w", srcRepo, sigTag, err)
+			}
+			log.Info("Mirrored attachment", zap.String("tag", sigTag))
+		}
+
+		host, _ := ref.SplitRepository(artifact.Registry)
+		sources[host] = target
+		mirrored = append(mirrored, mirroredRef)
+	}
+
+	fmt.Printf("\nSuccessfully mirrored release %d to %s\n", releaseID, target)
+	fmt.Printf("\nMirrored Images:\n")
+	for _, img := range mirrored {
+		fmt.Printf("  - %s\n", img)
+	}
+
+	hosts := make([]string, 0, len(sources))
+	for host := range sources {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	fmt.Printf("\nTo pull from the mirror, run:\n")
+	fmt.Printf("  flickr context set")
+	for _, host := range hosts {
+		fmt.Printf(" --registry-mirror %s=%s", host, sources[host])
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func grantCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "grant",
+		Usage:  "Allow an address to publish for the AVS (requires an admin signer)",
+		Flags:  appointeeFlags(),
+		Action: grantAction,
+	}
+}
+
+func revokeCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "revoke",
+		Usage:  "Revoke an address's permission to publish for the AVS (requires an admin signer)",
+		Flags:  appointeeFlags(),
+		Action: revokeAction,
+	}
+}
+
+func grantAction(c *cli.Context) error {
+	return updateAppointee(c, true)
+}
+
+func revokeAction(c *cli.Context) error {
+	return updateAppointee(c, false)
+}
+
+// updateAppointee grants or revokes the selected functions for --appointee
+func updateAppointee(c *cli.Context, grant bool) error {
+	log := middleware.GetLogger(c)
+
+	currentCtx, err := middleware.GetCurrentContext(c)
+	if err != nil {
+		currentCtx = &config.Context{}
+	}
+
+	avs, rpcURL, rmAddr, err := getConfig(c, currentCtx)
+	if err != nil {
+		return err
+	}
+
+	if !common.IsHexAddress(c.String("appointee")) {
+		return fmt.Errorf("invalid appointee address: %s", c.String("appointee"))
+	}
+	appointee := common.HexToAddress(c.String("appointee"))
+
+	functions, err := getFunctions(c)
+	if err != nil {
+		return err
+	}
+
+	// Get signer from context
+	sig, err := middleware.GetSigner(c, currentCtx)
+	if err != nil {
+		return fmt.Errorf("no signer configured: %w", err)
+	}
+
+	rmClient, err := eth.NewClientWithSigner(rpcURL, rmAddr, sig)
+	if err != nil {
+		return fmt.Errorf("failed to create Ethereum client: %w", err)
+	}
+	defer rmClient.Close()
+
+	ctx := context.Background()
+	for _, fn := range functions {
+		update, summary := rmClient.SetAppointee, "Granted %s to %s\n"
+		if !grant {
+			update, summary = rmClient.RemoveAppointee, "Revoked %s from %s\n"
+		}
+
+		tx, err := update(ctx, avs, appointee, fn, c.Uint64("gas-limit"))
+		if err != nil {
+			return err
+		}
+
+		log.Info("Transaction submitted",
+			zap.String("txHash", tx.Hash().Hex()),
+			zap.String("from", sig.Address().Hex()),
+			zap.String("appointee", appointee.Hex()),
+			zap.String("function", fn.Method()))
+
+		fmt.Printf(summary, fn.Method(), appointee.Hex())
+		fmt.Printf("Transaction: %s\n", tx.Hash().Hex())
+	}
+	fmt.Printf("AVS: %s\n", avs.Hex())
+
+	return nil
+}
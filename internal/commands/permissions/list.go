@@ -0,0 +1,67 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/middleware"
+)
+
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "list",
+		Usage:  "List the admins and appointees of an AVS",
+		Flags:  chainFlags(),
+		Action: listAction,
+	}
+}
+
+func listAction(c *cli.Context) error {
+	currentCtx, err := middleware.GetCurrentContext(c)
+	if err != nil {
+		currentCtx = &config.Context{}
+	}
+
+	avs, rpcURL, rmAddr, err := getConfig(c, currentCtx)
+	if err != nil {
+		return err
+	}
+
+	rmClient, err := eth.NewClient(rpcURL, rmAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create Ethereum client: %w", err)
+	}
+	defer rmClient.Close()
+
+	ctx := context.Background()
+	admins, err := rmClient.GetAdmins(ctx, avs)
+	if err != nil {
+		return err
+	}
+	// Without admins, the AVS itself is the admin
+	if len(admins) == 0 {
+		admins = append(admins, avs)
+	}
+
+	table := tablewriter.NewWriter(c.App.Writer)
+	table.Header("ADDRESS", "ROLE", "FUNCTION")
+	for _, admin := range admins {
+		table.Append([]string{admin.Hex(), "admin", "all"})
+	}
+	for _, fn := range eth.ReleaseFunctions {
+		appointees, err := rmClient.GetAppointees(ctx, avs, fn)
+		if err != nil {
+			return err
+		}
+		for _, appointee := range appointees {
+			table.Append([]string{appointee.Hex(), "appointee", fn.Method()})
+		}
+	}
+	fmt.Printf("AVS: %s\n", avs.Hex())
+	table.Render()
+	return nil
+}
@@ -0,0 +1,91 @@
+package permissions
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/middleware"
+)
+
+// Command returns the permissions command
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "permissions",
+		Usage: "Manage who may publish releases for an AVS",
+		Description: `Lists and manages the appointees EigenLayer's PermissionController allows to
+call publishRelease and publishMetadataURI on the ReleaseManager on behalf of
+an AVS. Granting and revoking require the context's signer to be an AVS admin.`,
+		Subcommands: []*cli.Command{
+			listCommand(),
+			grantCommand(),
+			revokeCommand(),
+		},
+	}
+}
+
+// chainFlags are the flags used to locate the AVS permissions on-chain
+func chainFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "avs",
+			Usage: "AVS contract address (uses context if not provided)",
+		},
+		&cli.StringFlag{
+			Name:  "release-manager",
+			Usage: "ReleaseManager contract address (uses chain default if not provided)",
+		},
+		&cli.StringFlag{
+			Name:  "rpc-url",
+			Usage: "Ethereum RPC URL (uses context if not provided)",
+		},
+	}
+}
+
+// appointeeFlags are the flags of grant and revoke
+func appointeeFlags() []cli.Flag {
+	return append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "appointee",
+			Usage:    "Address to grant or revoke the permission for",
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "function",
+			Usage: "Function to grant or revoke: publish-release, publish-metadata-uri (default: both)",
+		},
+		&cli.Uint64Flag{
+			Name:  "gas-limit",
+			Usage: "Gas limit for each transaction",
+			Value: 200000,
+		},
+		middleware.KeystorePasswordFileFlag(),
+	}, chainFlags()...)
+}
+
+// getConfig extracts configuration from flags, FLICKR_* variables or context
+func getConfig(c *cli.Context, currentCtx *config.Context) (common.Address, string, common.Address, error) {
+	target, rmAddr, err := eth.ResolveTarget(c, currentCtx)
+	if err != nil {
+		return common.Address{}, "", common.Address{}, err
+	}
+	return target.AVS, target.RPCURL, rmAddr, nil
+}
+
+// getFunctions returns the functions selected with --function, or all
+func getFunctions(c *cli.Context) ([]eth.ReleaseFunction, error) {
+	names := c.StringSlice("function")
+	if len(names) == 0 {
+		return eth.ReleaseFunctions, nil
+	}
+
+	functions := make([]eth.ReleaseFunction, 0, len(names))
+	for _, name := range names {
+		fn, err := eth.LookupReleaseFunction(name)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, fn)
+	}
+	return functions, nil
+}
@@ -3,15 +3,18 @@ package pull
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli/v2"
 	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/controller"
+	"github.com/yourorg/flickr/internal/docker"
 	"github.com/yourorg/flickr/internal/eth"
 	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/policy"
 	"github.com/yourorg/flickr/internal/ref"
+	"github.com/yourorg/flickr/internal/registry"
 	"go.uber.org/zap"
 )
 
@@ -22,7 +25,10 @@ func Command() *cli.Command {
 		Usage: "Pull Docker images for a release from the chain",
 		Description: `Fetches release information from the on-chain ReleaseManager and pulls 
 all Docker images associated with the release. Can pull the latest release or a specific 
-release ID.`,
+release ID.
+
+For a context with deployments, pulls the release of every deployment, or of those
+selected with --deployment.`,
 		Flags: []cli.Flag{
 			&cli.Uint64Flag{
 				Name:  "release-id",
@@ -48,56 +54,54 @@ release ID.`,
 				Name:  "all",
 				Usage: "Pull all artifacts (default pulls only the first)",
 			},
+			&cli.StringFlag{
+				Name:  "policy",
+				Usage: "Attestation policy file (uses context if not provided)",
+			},
+			&cli.BoolFlag{
+				Name:  "ignore-policy",
+				Usage: "Continue even if the attestation policy fails",
+			},
+			middleware.DeploymentFlag(),
 		},
 		Action: pullAction,
 	}
 }
 
 func pullAction(c *cli.Context) error {
-	log := middleware.GetLogger(c)
-
 	// Get context
 	currentCtx, err := middleware.GetCurrentContext(c)
 	if err != nil {
 		currentCtx = &config.Context{}
 	}
 
-	// Get AVS address (from flag or context)
-	avsAddress := c.String("avs")
-	if avsAddress == "" {
-		avsAddress = currentCtx.AVSAddress
-	}
-	if avsAddress == "" {
-		return fmt.Errorf("--avs is required (or set in context with 'flickr context set --avs-address')")
-	}
+	return middleware.ForEachDeployment(c, currentCtx, func(_ string, ctx *config.Context) error {
+		return pullRelease(c, ctx)
+	})
+}
 
-	// Get operator set ID (from flag or context)
-	operatorSetID := uint32(c.Uint64("operator-set"))
-	if operatorSetID == 0 && c.IsSet("operator-set") {
-		// Explicitly set to 0
-	} else if operatorSetID == 0 {
-		operatorSetID = currentCtx.OperatorSetID
-	}
+// pullRelease pulls the release of the operator set of currentCtx
+func pullRelease(c *cli.Context, currentCtx *config.Context) error {
+	log := middleware.GetLogger(c)
 
-	// Get RPC URL (from flag or context)
-	rpcURL := c.String("rpc-url")
-	if rpcURL == "" {
-		rpcURL = currentCtx.RPCURL
-	}
-	if rpcURL == "" {
-		return fmt.Errorf("--rpc-url is required (or set in context with 'flickr context set --rpc-url')")
+	// Resolve the target: flag, FLICKR_* variable, context, chain default
+	target, rmAddr, err := eth.ResolveTarget(c, currentCtx)
+	if err != nil {
+		return err
 	}
+	avsAddress, operatorSetID, rpcURL := target.AVS.Hex(), target.OperatorSetID, target.RPCURL
 
-	// Get release manager address (from flag, context, or chain default)
-	releaseManager := c.String("release-manager")
-	if releaseManager == "" {
-		releaseManager = currentCtx.ReleaseManager
+	// Load attestation policy (from flag or context)
+	var pol *policy.Policy
+	policyPath := c.String("policy")
+	if policyPath == "" {
+		policyPath = currentCtx.PolicyPath
 	}
-
-	// Get the actual address (may use chain defaults)
-	rmAddr, err := eth.GetReleaseManagerAddress(rpcURL, releaseManager)
-	if err != nil {
-		return fmt.Errorf("failed to get ReleaseManager address: %w", err)
+	if policyPath != "" {
+		pol, err = policy.Load(policyPath)
+		if err != nil {
+			return err
+		}
 	}
 
 	log.Info("Using configuration",
@@ -182,32 +186,55 @@ To push a release, run:
 			zap.Int("totalArtifacts", len(release.Artifacts)))
 	}
 
+	// Create Docker runner with the context's registry credentials
+	dockerRunner, cleanup, err := registry.NewRunner(currentCtx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
 	// Pull each artifact
 	pulledImages := make([]string, 0, len(artifactsToPull))
 	for i, artifact := range artifactsToPull {
 		// Convert digest to string format
 		digest := ref.Digest32ToSha256String(artifact.Digest32)
 
-		// Build pullable reference
-		reference, err := ref.BuildReference(artifact.Registry, digest)
+		// Build pullable reference, fetching from a mirror if configured
+		registry := ref.RewriteRegistry(artifact.Registry, currentCtx.RegistryMirrors)
+		reference, err := ref.BuildReference(registry, digest)
 		if err != nil {
 			return fmt.Errorf("failed to build reference for artifact %d: %w", i, err)
 		}
 
+		// Check supply-chain policy before pulling; attestations are only
+		// fetched from the registry if a rule needs them
+		if pol != nil && pol.HasAttestationRules() {
+			if err := checkPolicy(ctx, c, dockerRunner, pol, reference); err != nil {
+				return err
+			}
+		}
+
 		log.Info("Pulling Docker image",
 			zap.Int("artifact", i+1),
 			zap.Int("total", len(artifactsToPull)),
 			zap.String("reference", reference))
 
 		// Docker pull
-		cmd := exec.Command("docker", "pull", reference)
-		output, err := cmd.CombinedOutput()
+		if err := dockerRunner.Pull(ctx, reference); err != nil {
+			return fmt.Errorf("failed to pull image %s: %w", reference, err)
+		}
+
+		// Confirm the image that landed locally carries the on-chain digest
+		repoDigests, err := dockerRunner.RepoDigests(ctx, reference)
 		if err != nil {
-			return fmt.Errorf("failed to pull image %s: %v\n%s", reference, err, string(output))
+			return fmt.Errorf("failed to inspect pulled image %s: %w", reference, err)
+		}
+		if err := ref.VerifyRepoDigests(reference, digest, repoDigests); err != nil {
+			return fmt.Errorf("pulled image failed verification: %w", err)
 		}
 
 		pulledImages = append(pulledImages, reference)
-		log.Info("Successfully pulled image", zap.String("reference", reference))
+		log.Info("Successfully pulled and verified image", zap.String("reference", reference))
 	}
 
 	// Print summary
@@ -224,5 +251,25 @@ To push a release, run:
 		fmt.Printf("\nNote: Only pulled first artifact. Use --all to pull all %d artifacts.\n", len(release.Artifacts))
 	}
 
+	return nil
+}
+
+// checkPolicy evaluates the attestation policy for a reference and prints the result
+func checkPolicy(ctx context.Context, c *cli.Context, dockerRunner *docker.Runner, pol *policy.Policy, reference string) error {
+	att, err := controller.RunnerAttestations{Runner: dockerRunner}.Attestations(ctx, reference)
+	if err != nil && !c.Bool("ignore-policy") {
+		return fmt.Errorf("failed to fetch attestations for %s: %w", reference, err)
+	}
+
+	result := pol.Evaluate(reference, att)
+	result.Print(c.App.Writer)
+
+	if !result.Passed() {
+		if c.Bool("ignore-policy") {
+			fmt.Fprintf(c.App.Writer, "Policy failed, continuing because --ignore-policy is set\n")
+			return nil
+		}
+		return fmt.Errorf("release artifact %s failed policy (use --ignore-policy to override)", reference)
+	}
 	return nil
 }
\ No newline at end of file
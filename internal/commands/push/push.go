@@ -3,17 +3,17 @@ package push
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli/v2"
 	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/docker"
 	"github.com/yourorg/flickr/internal/eth"
 	"github.com/yourorg/flickr/internal/middleware"
 	"github.com/yourorg/flickr/internal/ref"
-	"github.com/yourorg/flickr/internal/signer"
+	"github.com/yourorg/flickr/internal/registry"
 	"go.uber.org/zap"
 )
 
@@ -25,6 +25,7 @@ func Command() *cli.Command {
 		Description: `Pushes a Docker image to a registry and creates an on-chain release 
 in the ReleaseManager contract for the configured AVS and operator set.`,
 		Flags: []cli.Flag{
+			middleware.KeystorePasswordFileFlag(),
 			&cli.StringSliceFlag{
 				Name:     "image",
 				Usage:    "Docker image(s) to push (e.g., myregistry.io/myimage:tag)",
@@ -77,46 +78,15 @@ func pushAction(c *cli.Context) error {
 		currentCtx = &config.Context{}
 	}
 
-	// Get AVS address (from flag or context)
-	avsAddress := c.String("avs")
-	if avsAddress == "" {
-		avsAddress = currentCtx.AVSAddress
-	}
-	if avsAddress == "" {
-		return fmt.Errorf("--avs is required (or set in context with 'flickr context set --avs-address')")
-	}
-
-	// Get operator set ID (from flag or context)
-	operatorSetID := uint32(c.Uint64("operator-set"))
-	if operatorSetID == 0 && c.IsSet("operator-set") {
-		// Explicitly set to 0
-	} else if operatorSetID == 0 {
-		operatorSetID = currentCtx.OperatorSetID
-	}
-
-	// Get RPC URL (from flag or context)
-	rpcURL := c.String("rpc-url")
-	if rpcURL == "" {
-		rpcURL = currentCtx.RPCURL
-	}
-	if rpcURL == "" {
-		return fmt.Errorf("--rpc-url is required (or set in context with 'flickr context set --rpc-url')")
-	}
-
-	// Get release manager address (from flag, context, or chain default)
-	releaseManager := c.String("release-manager")
-	if releaseManager == "" {
-		releaseManager = currentCtx.ReleaseManager
-	}
-
-	// Get the actual address (may use chain defaults)
-	rmAddr, err := eth.GetReleaseManagerAddress(rpcURL, releaseManager)
+	// Resolve the target: flag, FLICKR_* variable, context, chain default
+	target, rmAddr, err := eth.ResolveTarget(c, currentCtx)
 	if err != nil {
-		return fmt.Errorf("failed to get ReleaseManager address: %w", err)
+		return err
 	}
+	avsAddress, operatorSetID, rpcURL := target.AVS.Hex(), target.OperatorSetID, target.RPCURL
 
 	// Get signer from context
-	sig, err := signer.FromContext(currentCtx)
+	sig, err := middleware.GetSigner(c, currentCtx)
 	if err != nil {
 		return fmt.Errorf("no signer configured: %w", err)
 	}
@@ -137,6 +107,46 @@ func pushAction(c *cli.Context) error {
 		return fmt.Errorf("at least one --image is required")
 	}
 
+	// Create Docker runner with the context's registry credentials
+	dockerRunner, cleanup, err := registry.NewRunner(currentCtx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Create Ethereum client with signer
+	rmClient, err := eth.NewClientWithSigner(rpcURL, rmAddr, sig)
+	if err != nil {
+		return fmt.Errorf("failed to create Ethereum client: %w", err)
+	}
+	defer rmClient.Close()
+
+	// Check if metadata URI is set
+	metadataURI, err := rmClient.GetMetadataURI(ctx, avs, operatorSetID)
+	if err != nil {
+		return fmt.Errorf("failed to check metadata URI: %w", err)
+	}
+
+	if metadataURI == "" {
+		return fmt.Errorf(`no metadata URI set for this operator set
+
+Please set a metadata URI first with:
+  flickr metadata set --uri "https://your-metadata-uri.json"
+
+Current configuration:
+  AVS: %s
+  Operator Set: %d`, avs.Hex(), operatorSetID)
+	}
+
+	log.Info("Metadata URI verified", zap.String("uri", metadataURI))
+
+	// Check the signer may publish releases for the AVS
+	if err := rmClient.CheckPermission(ctx, avs, eth.PublishReleaseFunction); err != nil {
+		return err
+	}
+
 	// Process artifacts
 	artifacts := make([]eth.Artifact, 0, len(images))
 	
@@ -146,16 +156,14 @@ func pushAction(c *cli.Context) error {
 		// Push Docker image unless skipped
 		if !c.Bool("skip-docker-push") {
 			log.Info("Pushing Docker image", zap.String("image", image))
-			cmd := exec.Command("docker", "push", image)
-			output, err := cmd.CombinedOutput()
-			if err != nil {
-				return fmt.Errorf("failed to push image %s: %v\n%s", image, err, string(output))
+			if err := dockerRunner.Push(ctx, image); err != nil {
+				return fmt.Errorf("failed to push image %s: %w", image, err)
 			}
 			log.Info("Docker push successful", zap.String("image", image))
 		}
 
 		// Get digest from the image
-		digest, registry, err := getImageDigest(image)
+		digest, registry, err := getImageDigest(ctx, dockerRunner, image)
 		if err != nil {
 			return fmt.Errorf("failed to get digest for %s: %w", image, err)
 		}
@@ -185,33 +193,6 @@ func pushAction(c *cli.Context) error {
 		upgradeByTime = uint32(time.Now().Add(30 * 24 * time.Hour).Unix())
 	}
 
-	// Create Ethereum client with signer
-	rmClient, err := eth.NewClientWithSigner(rpcURL, rmAddr, sig)
-	if err != nil {
-		return fmt.Errorf("failed to create Ethereum client: %w", err)
-	}
-	defer rmClient.Close()
-
-	// Check if metadata URI is set
-	ctx := context.Background()
-	metadataURI, err := rmClient.GetMetadataURI(ctx, avs, operatorSetID)
-	if err != nil {
-		return fmt.Errorf("failed to check metadata URI: %w", err)
-	}
-
-	if metadataURI == "" {
-		return fmt.Errorf(`no metadata URI set for this operator set
-
-Please set a metadata URI first with:
-  flickr metadata set --uri "https://your-metadata-uri.json"
-
-Current configuration:
-  AVS: %s
-  Operator Set: %d`, avs.Hex(), operatorSetID)
-	}
-
-	log.Info("Metadata URI verified", zap.String("uri", metadataURI))
-
 	// Push release on-chain
 	log.Info("Pushing release on-chain",
 		zap.Int("artifactCount", len(artifacts)),
@@ -236,27 +217,16 @@ Current configuration:
 }
 
 // getImageDigest gets the digest and registry from a Docker image
-func getImageDigest(image string) ([]byte, string, error) {
-	// Get the digest using docker inspect
-	cmd := exec.Command("docker", "inspect", "--format", "{{.RepoDigests}}", image)
-	output, err := cmd.Output()
+func getImageDigest(ctx context.Context, dockerRunner *docker.Runner, image string) ([]byte, string, error) {
+	// Get the digests using docker inspect
+	// Format is like: [registry.io/image@sha256:abcd1234...]
+	digests, err := dockerRunner.RepoDigests(ctx, image)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to inspect image: %w", err)
 	}
 
-	// Parse the output to get the digest
-	// Format is like: [registry.io/image@sha256:abcd1234...]
-	digestStr := strings.TrimSpace(string(output))
-	digestStr = strings.Trim(digestStr, "[]")
-	
-	if digestStr == "" {
-		return nil, "", fmt.Errorf("no digest found for image (may need to pull first)")
-	}
-
-	// Handle multiple digests (space-separated)
-	digests := strings.Fields(digestStr)
 	if len(digests) == 0 {
-		return nil, "", fmt.Errorf("no digest found for image")
+		return nil, "", fmt.Errorf("no digest found for image (may need to pull first)")
 	}
 
 	// Use the first digest
@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+)
+
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "list",
+		Usage:  "List registry credentials for the current context",
+		Action: listAction,
+	}
+}
+
+func listAction(c *cli.Context) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	_, ctx, err := cfg.Current()
+	if err != nil {
+		return err
+	}
+
+	if len(ctx.RegistryAuth) == 0 {
+		fmt.Println("No registry credentials configured")
+		fmt.Println("\nTo add credentials, run:")
+		fmt.Println("  flickr registry login <registry-host> --helper <name>")
+		return nil
+	}
+
+	hosts := make([]string, 0, len(ctx.RegistryAuth))
+	for host := range ctx.RegistryAuth {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	table := tablewriter.NewWriter(c.App.Writer)
+	table.Header("REGISTRY", "TYPE", "SOURCE", "USERNAME")
+
+	for _, host := range hosts {
+		cred := ctx.RegistryAuth[host]
+		kind, source := cred.Source()
+
+		username := cred.Username
+		if username == "" {
+			username = "-"
+		}
+
+		table.Append([]string{host, kind, source, username})
+	}
+
+	table.Render()
+	return nil
+}
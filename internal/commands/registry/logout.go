@@ -0,0 +1,51 @@
+package registry
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func logoutCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "logout",
+		Usage:     "Remove credentials for a registry",
+		ArgsUsage: "<registry-host>",
+		Action:    logoutAction,
+	}
+}
+
+func logoutAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	host := c.Args().Get(0)
+	log := middleware.GetLogger(c)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	contextName, ctx, err := cfg.Current()
+	if err != nil {
+		return err
+	}
+
+	if _, exists := ctx.RegistryAuth[host]; !exists {
+		return fmt.Errorf("no credentials configured for '%s'", host)
+	}
+	delete(ctx.RegistryAuth, host)
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	log.Info("Registry credentials removed", zap.String("registry", host))
+	fmt.Printf("Removed credentials for '%s' from context '%s'\n", host, contextName)
+	return nil
+}
@@ -0,0 +1,18 @@
+package registry
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// Command returns the registry command
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "registry",
+		Usage: "Manage registry credentials for the current context",
+		Subcommands: []*cli.Command{
+			loginCommand(),
+			logoutCommand(),
+			listCommand(),
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/approval"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func approveCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "approve",
+		Usage:     "Sign an off-chain approval of a release",
+		ArgsUsage: "<release-id>",
+		Description: `Signs (EIP-191) the AVS, operator set, release ID and artifact digests of an
+on-chain release with the context's signer, and writes the approval to a directory.
+Collect approvals in a shared directory or publish them as a JSON array at a URL;
+'flickr run' checks them against the approvals quorum of the policy.`,
+		Flags: append([]cli.Flag{
+			middleware.KeystorePasswordFileFlag(),
+			&cli.StringFlag{
+				Name:  "dir",
+				Usage: "Directory to write the approval to",
+				Value: ".",
+			},
+		}, chainFlags()...),
+		Action: approveAction,
+	}
+}
+
+func approveAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	releaseID, err := strconv.ParseUint(c.Args().Get(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid release ID %q: %w", c.Args().Get(0), err)
+	}
+
+	log := middleware.GetLogger(c)
+
+	currentCtx, err := middleware.GetCurrentContext(c)
+	if err != nil {
+		currentCtx = &config.Context{}
+	}
+
+	avs, operatorSetID, rpcURL, rmAddr, err := getConfig(c, currentCtx)
+	if err != nil {
+		return err
+	}
+
+	sig, err := middleware.GetSigner(c, currentCtx)
+	if err != nil {
+		return fmt.Errorf("no signer configured: %w", err)
+	}
+
+	rmClient, err := eth.NewClient(rpcURL, rmAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create Ethereum client: %w", err)
+	}
+	defer rmClient.Close()
+
+	release, err := rmClient.GetRelease(context.Background(), avs, operatorSetID, releaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get release %d: %w", releaseID, err)
+	}
+	if len(release.Artifacts) == 0 {
+		return fmt.Errorf("no artifacts in release %d", releaseID)
+	}
+
+	chainID, err := eth.GetChainID(rpcURL)
+	if err != nil {
+		return err
+	}
+
+	subject := approval.NewSubject(chainID, rmAddr, avs, operatorSetID, releaseID, release)
+	a, err := approval.Sign(sig, subject)
+	if err != nil {
+		return err
+	}
+
+	path, err := approval.WriteFile(c.String("dir"), a)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Release approved",
+		zap.Uint64("releaseID", releaseID),
+		zap.String("approver", a.Approver),
+		zap.String("path", path))
+
+	fmt.Printf("Approved release %d as %s\n", releaseID, a.Approver)
+	fmt.Printf("Chain ID: %d\n", chainID)
+	fmt.Printf("ReleaseManager: %s\n", rmAddr.Hex())
+	fmt.Printf("AVS: %s\n", avs.Hex())
+	fmt.Printf("Operator Set: %d\n", operatorSetID)
+	fmt.Printf("\nDigests:\n")
+	for _, digest := range subject.Digests {
+		fmt.Printf("  - %s\n", digest)
+	}
+	fmt.Printf("\nApproval written to %s\n", path)
+	return nil
+}
@@ -0,0 +1,144 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/archive"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/registry"
+	"go.uber.org/zap"
+)
+
+func exportCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "export",
+		Usage:     "Export the artifacts of a release to an archive for air-gapped hosts",
+		ArgsUsage: "<release-id>",
+		Description: `Pulls every artifact of a release and saves them as an OCI image layout tar,
+together with a manifest describing the AVS, operator set, release ID and on-chain digests.
+Load the archive on another host with 'flickr release import'.`,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:     "output",
+				Aliases:  []string{"o"},
+				Usage:    "Path of the archive to write",
+				Required: true,
+			},
+		}, chainFlags()...),
+		Action: exportAction,
+	}
+}
+
+func exportAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	releaseID, err := strconv.ParseUint(c.Args().Get(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid release ID %q: %w", c.Args().Get(0), err)
+	}
+
+	log := middleware.GetLogger(c)
+
+	currentCtx, err := middleware.GetCurrentContext(c)
+	if err != nil {
+		currentCtx = &config.Context{}
+	}
+
+	avs, operatorSetID, rpcURL, rmAddr, err := getConfig(c, currentCtx)
+	if err != nil {
+		return err
+	}
+
+	chainID, err := eth.GetChainID(rpcURL)
+	if err != nil {
+		return err
+	}
+
+	rmClient, err := eth.NewClient(rpcURL, rmAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create Ethereum client: %w", err)
+	}
+	defer rmClient.Close()
+
+	ctx := context.Background()
+	release, err := rmClient.GetRelease(ctx, avs, operatorSetID, releaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get release %d: %w", releaseID, err)
+	}
+	if len(release.Artifacts) == 0 {
+		return fmt.Errorf("no artifacts in release %d", releaseID)
+	}
+
+	manifest, err := archive.NewManifest(chainID, rmAddr.Hex(), avs.Hex(), operatorSetID, releaseID, release)
+	if err != nil {
+		return err
+	}
+
+	// Create Docker runner with the context's registry credentials
+	dockerRunner, cleanup, err := registry.NewRunner(currentCtx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// Pull all artifacts so they can be saved
+	for _, reference := range manifest.References() {
+		log.Info("Pulling Docker image", zap.String("reference", reference))
+		if err := dockerRunner.Pull(ctx, reference); err != nil {
+			return fmt.Errorf("failed to pull image %s: %w", reference, err)
+		}
+	}
+
+	output := c.String("output")
+
+	// Save to a temporary layout next to the output, then wrap it with the manifest
+	layout, err := os.CreateTemp(filepath.Dir(output), ".flickr-layout-*.tar")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(layout.Name())
+	defer layout.Close()
+
+	log.Info("Saving images", zap.Int("count", len(manifest.Artifacts)))
+	if err := dockerRunner.Save(ctx, layout, manifest.References()...); err != nil {
+		return err
+	}
+	if _, err := layout.Seek(0, 0); err != nil {
+		return fmt.Errorf("failed to read saved images: %w", err)
+	}
+
+	out, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	// A failed close can leave a truncated archive, so it fails the export
+	if err := archive.Write(out, layout, manifest); err != nil {
+		out.Close()
+		os.Remove(output)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(output)
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	fmt.Printf("Exported release %d to %s\n", releaseID, output)
+	fmt.Printf("AVS: %s\n", avs.Hex())
+	fmt.Printf("Operator Set: %d\n", operatorSetID)
+	fmt.Printf("\nArtifacts:\n")
+	for _, artifact := range manifest.Artifacts {
+		fmt.Printf("  - %s\n", artifact.Reference)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/archive"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/ref"
+	"github.com/yourorg/flickr/internal/registry"
+	"go.uber.org/zap"
+)
+
+func importCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import a release archive into the local Docker image store",
+		ArgsUsage: "<archive>",
+		Description: `Reads a release archive written by 'flickr release export', checks that its
+digests match the release on-chain, and loads the images into Docker. Only the RPC
+endpoint is needed; no registry access is required.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "rpc-url",
+				Usage: "Ethereum RPC URL (uses context if not provided)",
+			},
+			&cli.StringFlag{
+				Name:  "release-manager",
+				Usage: "ReleaseManager contract address (uses context or chain default if not provided)",
+			},
+		},
+		Action: importAction,
+	}
+}
+
+func importAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	path := c.Args().Get(0)
+	log := middleware.GetLogger(c)
+
+	currentCtx, err := middleware.GetCurrentContext(c)
+	if err != nil {
+		currentCtx = &config.Context{}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	manifest, err := archive.ReadManifest(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	log.Info("Read release archive",
+		zap.String("avs", manifest.AVS),
+		zap.Uint32("operatorSet", manifest.OperatorSetID),
+		zap.Uint64("releaseID", manifest.ReleaseID))
+
+	rpcURL, err := getRPCURL(c, currentCtx)
+	if err != nil {
+		return err
+	}
+
+	chainID, err := eth.GetChainID(rpcURL)
+	if err != nil {
+		return err
+	}
+	if chainID != manifest.ChainID {
+		return fmt.Errorf("archive was exported from chain %d but RPC serves chain %d", manifest.ChainID, chainID)
+	}
+
+	// The archive is untrusted: the ReleaseManager is resolved like every
+	// other command's, and the archive's only has to agree with it
+	releaseManager := c.String(config.FlagReleaseManager)
+	if releaseManager == "" {
+		releaseManager = currentCtx.ReleaseManager
+	}
+	if releaseManager != "" && !common.IsHexAddress(releaseManager) {
+		return fmt.Errorf("invalid ReleaseManager address: %s", releaseManager)
+	}
+	rmAddr, err := eth.GetReleaseManagerAddress(rpcURL, releaseManager)
+	if err != nil {
+		return fmt.Errorf("failed to get ReleaseManager address: %w", err)
+	}
+	if manifest.ReleaseManager != "" && common.HexToAddress(manifest.ReleaseManager) != rmAddr {
+		return fmt.Errorf("archive was exported from ReleaseManager %s but %s is configured; refusing to import", manifest.ReleaseManager, rmAddr.Hex())
+	}
+
+	rmClient, err := eth.NewClient(rpcURL, rmAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create Ethereum client: %w", err)
+	}
+	defer rmClient.Close()
+
+	// Check the archive against the chain before loading anything
+	ctx := context.Background()
+	avs := common.HexToAddress(manifest.AVS)
+	release, err := rmClient.GetRelease(ctx, avs, manifest.OperatorSetID, manifest.ReleaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get release %d: %w", manifest.ReleaseID, err)
+	}
+	if err := manifest.Verify(release); err != nil {
+		return err
+	}
+
+	log.Info("Archive digests match the chain", zap.Int("artifacts", len(manifest.Artifacts)))
+
+	// Create Docker runner with the context's registry credentials
+	dockerRunner, cleanup, err := registry.NewRunner(currentCtx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := dockerRunner.Load(ctx, path); err != nil {
+		return err
+	}
+
+	// The archive can name any image after the release's digests; only the
+	// digests Docker computed for the loaded images are trusted
+	for _, artifact := range manifest.Artifacts {
+		repoDigests, err := dockerRunner.RepoDigests(ctx, artifact.Reference)
+		if err != nil {
+			return fmt.Errorf("image %s not found after load (the Docker containerd image store is required to keep digests): %w", artifact.Reference, err)
+		}
+		if err := ref.VerifyRepoDigests(artifact.Reference, artifact.Digest, repoDigests); err != nil {
+			return fmt.Errorf("loaded image does not match the release: %w", err)
+		}
+	}
+
+	fmt.Printf("Imported release %d\n", manifest.ReleaseID)
+	fmt.Printf("AVS: %s\n", avs.Hex())
+	fmt.Printf("Operator Set: %d\n", manifest.OperatorSetID)
+	fmt.Printf("\nLoaded Images:\n")
+	for _, reference := range manifest.References() {
+		fmt.Printf("  - %s\n", reference)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package release
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+)
+
+// Command returns the release command
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "release",
+		Usage: "Manage on-chain releases",
+		Subcommands: []*cli.Command{
+			exportCommand(),
+			importCommand(),
+			approveCommand(),
+		},
+	}
+}
+
+// chainFlags are the flags used to locate releases on-chain
+func chainFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "avs",
+			Usage: "AVS contract address (uses context if not provided)",
+		},
+		&cli.Uint64Flag{
+			Name:  "operator-set",
+			Usage: "Operator set ID (uses context if not provided)",
+		},
+		&cli.StringFlag{
+			Name:  "release-manager",
+			Usage: "ReleaseManager contract address (uses chain default if not provided)",
+		},
+		&cli.StringFlag{
+			Name:  "rpc-url",
+			Usage: "Ethereum RPC URL (uses context if not provided)",
+		},
+	}
+}
+
+// getConfig extracts configuration from flags, FLICKR_* variables or context
+func getConfig(c *cli.Context, currentCtx *config.Context) (common.Address, uint32, string, common.Address, error) {
+	target, rmAddr, err := eth.ResolveTarget(c, currentCtx)
+	if err != nil {
+		return common.Address{}, 0, "", common.Address{}, err
+	}
+	return target.AVS, target.OperatorSetID, target.RPCURL, rmAddr, nil
+}
+
+// getRPCURL gets the RPC URL from flag, FLICKR_RPC_URL or context
+func getRPCURL(c *cli.Context, currentCtx *config.Context) (string, error) {
+	return eth.ResolveRPCURL(c, currentCtx)
+}
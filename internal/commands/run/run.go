@@ -4,15 +4,16 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/approval"
 	"github.com/yourorg/flickr/internal/config"
 	"github.com/yourorg/flickr/internal/controller"
-	"github.com/yourorg/flickr/internal/docker"
 	"github.com/yourorg/flickr/internal/eth"
 	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/policy"
+	"github.com/yourorg/flickr/internal/registry"
 	"go.uber.org/zap"
 )
 
@@ -23,7 +24,10 @@ func Command() *cli.Command {
 		Usage: "Run an AVS release in Docker",
 		Description: `Fetches release information from the on-chain ReleaseManager and runs the 
 specified release as a Docker container with AVS context. Can run the latest release or a 
-specific release ID.`,
+specific release ID.
+
+For a context with deployments, runs a container for every deployment, or for those
+selected with --deployment. Running more than one deployment requires --detach.`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "avs",
@@ -63,14 +67,29 @@ specific release ID.`,
 				Name:  "cmd",
 				Usage: "Command to run in the container",
 			},
+			&cli.StringSliceFlag{
+				Name:  "port",
+				Usage: "Publish a container port (docker run -p syntax, added to the context's ports)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "volume",
+				Usage: "Mount a volume (docker run -v syntax, added to the context's volumes)",
+			},
+			&cli.StringFlag{
+				Name:  "policy",
+				Usage: "Release policy file: attestation rules and approval quorum (uses context if not provided)",
+			},
+			&cli.BoolFlag{
+				Name:  "ignore-policy",
+				Usage: "Continue even if the policy fails",
+			},
+			middleware.DeploymentFlag(),
 		},
 		Action: runAction,
 	}
 }
 
 func runAction(c *cli.Context) error {
-	log := middleware.GetLogger(c)
-	
 	// Get context
 	currentCtx, err := middleware.GetCurrentContext(c)
 	if err != nil {
@@ -78,43 +97,35 @@ func runAction(c *cli.Context) error {
 		currentCtx = &config.Context{}
 	}
 
-	// Get AVS address (from flag or context)
-	avsAddress := c.String("avs")
-	if avsAddress == "" {
-		avsAddress = currentCtx.AVSAddress
-	}
-	if avsAddress == "" {
-		return fmt.Errorf("--avs is required (or set in context with 'flickr context set --avs-address')")
+	// Containers of several deployments can only run side by side
+	if len(currentCtx.Deployments) > 0 && !c.IsSet("avs") && !c.IsSet("operator-set") {
+		names, err := currentCtx.SelectDeployments(c.StringSlice("deployment"))
+		if err != nil {
+			return err
+		}
+		if len(names) > 1 && !c.Bool("detach") {
+			return fmt.Errorf("running %d deployments requires --detach (or select one with --deployment)", len(names))
+		}
+		if len(names) > 1 && c.String("name") != "" {
+			return fmt.Errorf("--name cannot be used with %d deployments; set a name per deployment instead", len(names))
+		}
 	}
 
-	// Get operator set ID (from flag or context)
-	operatorSetID := uint32(c.Uint64("operator-set"))
-	if operatorSetID == 0 && c.IsSet("operator-set") {
-		// Explicitly set to 0
-	} else if operatorSetID == 0 {
-		operatorSetID = currentCtx.OperatorSetID
-	}
+	return middleware.ForEachDeployment(c, currentCtx, func(_ string, ctx *config.Context) error {
+		return runRelease(c, ctx)
+	})
+}
 
-	// Get RPC URL (from flag or context)
-	rpcURL := c.String("rpc-url")
-	if rpcURL == "" {
-		rpcURL = currentCtx.RPCURL
-	}
-	if rpcURL == "" {
-		return fmt.Errorf("--rpc-url is required (or set in context with 'flickr context set --rpc-url')")
-	}
+// runRelease runs the release of the operator set of currentCtx
+func runRelease(c *cli.Context, currentCtx *config.Context) error {
+	log := middleware.GetLogger(c)
 
-	// Get release manager address (from flag, context, or chain default)
-	releaseManager := c.String("release-manager")
-	if releaseManager == "" {
-		releaseManager = currentCtx.ReleaseManager
-	}
-	
-	// Get the actual address (may use chain defaults)
-	rmAddr, err := eth.GetReleaseManagerAddress(rpcURL, releaseManager)
+	// Resolve the target: flag, FLICKR_* variable, context, chain default
+	target, rmAddr, err := eth.ResolveTarget(c, currentCtx)
 	if err != nil {
-		return fmt.Errorf("failed to get ReleaseManager address: %w", err)
+		return err
 	}
+	avsAddress, operatorSetID, rpcURL := target.AVS.Hex(), target.OperatorSetID, target.RPCURL
 
 	log.Info("Using configuration",
 		zap.String("avs", avsAddress),
@@ -149,10 +160,24 @@ func runAction(c *cli.Context) error {
 		envMap[parts[0]] = parts[1]
 	}
 
-	// Get container name (from flag or context)
+	// Get container name (from flag or context); a stable name lets a restart
+	// or cleanup find the container again
 	containerName := c.String("name")
-	if containerName == "" && currentCtx.Name != "" {
-		containerName = fmt.Sprintf("%s-%d", currentCtx.Name, time.Now().Unix())
+	if containerName == "" {
+		containerName = currentCtx.Name
+	}
+
+	// Load attestation policy (from flag or context)
+	var pol *policy.Policy
+	policyPath := c.String("policy")
+	if policyPath == "" {
+		policyPath = currentCtx.PolicyPath
+	}
+	if policyPath != "" {
+		pol, err = policy.Load(policyPath)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Create Ethereum client
@@ -162,27 +187,57 @@ func runAction(c *cli.Context) error {
 	}
 	defer rmClient.Close()
 
-	// Create Docker runner
-	dockerRunner := docker.New()
+	// Create Docker runner with the context's registry credentials
+	dockerRunner, cleanup, err := registry.NewRunner(currentCtx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 
 	// Create controller
 	ctrl := controller.New(rmClient, dockerRunner)
+	ctrl.Attestations = controller.RunnerAttestations{Runner: dockerRunner}
+	ctrl.Out = c.App.Writer
+
+	ctx := context.Background()
+
+	// Collect release approvals when the policy requires a quorum. They are
+	// bound to the chain, so it is looked up if the context isn't pinned.
+	chainID := target.ChainID
+	if pol != nil && pol.Approvals != nil {
+		source, err := approvalSource(ctx, rmClient, avs, operatorSetID, pol.Approvals)
+		if err != nil {
+			return err
+		}
+		ctrl.Approvals = source
+
+		if chainID == 0 {
+			if chainID, err = eth.GetChainID(rpcURL); err != nil {
+				return err
+			}
+		}
+	}
 
 	// Prepare config
 	cfg := controller.RunConfig{
-		AVS:            avs,
-		OperatorSetID:  operatorSetID,
-		ReleaseID:      relID,
-		ReleaseManager: rmAddr,
-		RPCURL:         rpcURL,
-		Name:           containerName,
-		Detached:       c.Bool("detach"),
-		Env:            envMap,
-		Cmd:            c.StringSlice("cmd"),
+		AVS:             avs,
+		OperatorSetID:   operatorSetID,
+		ReleaseID:       relID,
+		ReleaseManager:  rmAddr,
+		RPCURL:          rpcURL,
+		ChainID:         chainID,
+		Name:            containerName,
+		Detached:        c.Bool("detach"),
+		Env:             envMap,
+		Cmd:             c.StringSlice("cmd"),
+		Ports:           append(append([]string(nil), currentCtx.Ports...), c.StringSlice("port")...),
+		Volumes:         append(append([]string(nil), currentCtx.Volumes...), c.StringSlice("volume")...),
+		Policy:          pol,
+		IgnorePolicy:    c.Bool("ignore-policy"),
+		RegistryMirrors: currentCtx.RegistryMirrors,
 	}
 
 	// Execute
-	ctx := context.Background()
 	if err := ctrl.Execute(ctx, cfg); err != nil {
 		return err
 	}
@@ -197,4 +252,22 @@ func runAction(c *cli.Context) error {
 	}
 
 	return nil
+}
+
+// approvalSource returns where release approvals are collected: the policy's
+// source, or the approvals URL in the operator set's metadata
+func approvalSource(ctx context.Context, rmClient *eth.Client, avs common.Address, operatorSetID uint32, pol *policy.ApprovalPolicy) (approval.Source, error) {
+	if pol.Source != "" {
+		return approval.NewSource(pol.Source), nil
+	}
+
+	metadataURI, err := rmClient.GetMetadataURI(ctx, avs, operatorSetID)
+	if err != nil {
+		return nil, err
+	}
+	url, err := approval.MetadataApprovalsURL(ctx, metadataURI)
+	if err != nil {
+		return nil, err
+	}
+	return approval.NewSource(url), nil
 }
\ No newline at end of file
@@ -0,0 +1,53 @@
+package secrets
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/secrets"
+	"go.uber.org/zap"
+)
+
+func deleteCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "delete",
+		Usage:     "Remove a secret",
+		ArgsUsage: "<name>",
+		Action:    deleteAction,
+	}
+}
+
+func deleteAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	name := c.Args().Get(0)
+	log := middleware.GetLogger(c)
+
+	if !secrets.StoreExists() {
+		return fmt.Errorf("secret '%s' not found", name)
+	}
+
+	passphrase, err := secrets.Passphrase(false)
+	if err != nil {
+		return err
+	}
+
+	store, err := secrets.OpenStore(passphrase)
+	if err != nil {
+		return err
+	}
+
+	if !store.Delete(name) {
+		return fmt.Errorf("secret '%s' not found", name)
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	log.Info("Secret removed", zap.String("name", name))
+	fmt.Printf("Removed secret '%s'\n", name)
+	return nil
+}
@@ -0,0 +1,46 @@
+package secrets
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/secrets"
+)
+
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "list",
+		Usage:  "List secret names (values are never shown)",
+		Action: listAction,
+	}
+}
+
+func listAction(c *cli.Context) error {
+	if !secrets.StoreExists() {
+		fmt.Println("No secrets stored")
+		fmt.Println("\nTo add a secret, run:")
+		fmt.Println("  flickr secrets set <name>")
+		return nil
+	}
+
+	passphrase, err := secrets.Passphrase(false)
+	if err != nil {
+		return err
+	}
+
+	store, err := secrets.OpenStore(passphrase)
+	if err != nil {
+		return err
+	}
+
+	names := store.Names()
+	if len(names) == 0 {
+		fmt.Println("No secrets stored")
+		return nil
+	}
+
+	for _, name := range names {
+		fmt.Printf("secret:%s\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package secrets
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// Command returns the secrets command
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "secrets",
+		Usage: "Manage the encrypted secrets file",
+		Description: `Stores named secrets in ~/.flickr/secrets.enc, encrypted with a passphrase.
+Reference them from a context with secret:<name>, e.g.
+
+  flickr secrets set mainnet-key
+  flickr context set --ecdsa-private-key-ref secret:mainnet-key
+
+The passphrase is prompted for, or read from FLICKR_SECRETS_PASSPHRASE.`,
+		Subcommands: []*cli.Command{
+			setCommand(),
+			listCommand(),
+			deleteCommand(),
+		},
+	}
+}
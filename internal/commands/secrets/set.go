@@ -0,0 +1,62 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/secrets"
+	"go.uber.org/zap"
+)
+
+func setCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "set",
+		Usage:     "Add or replace a secret",
+		ArgsUsage: "<name>",
+		Description: `Reads the secret value from the terminal without echoing it, or from stdin
+when piped.`,
+		Action: setAction,
+	}
+}
+
+func setAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.ShowSubcommandHelp(c)
+	}
+
+	name := c.Args().Get(0)
+	if strings.TrimSpace(name) == "" || strings.Contains(name, ":") {
+		return fmt.Errorf("invalid secret name %q", name)
+	}
+	log := middleware.GetLogger(c)
+
+	// A new secrets file needs the passphrase confirmed
+	passphrase, err := secrets.Passphrase(!secrets.StoreExists())
+	if err != nil {
+		return err
+	}
+
+	store, err := secrets.OpenStore(passphrase)
+	if err != nil {
+		return err
+	}
+
+	value, err := secrets.ReadPassword(fmt.Sprintf("Value for '%s': ", name))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(value)) == "" {
+		return fmt.Errorf("secret value must not be empty")
+	}
+
+	store.Set(name, string(value))
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	log.Info("Secret stored", zap.String("name", name))
+	fmt.Printf("Secret '%s' stored; reference it with secret:%s\n", name, name)
+	return nil
+}
@@ -0,0 +1,16 @@
+package sign
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// Command returns the sign command
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "sign",
+		Usage: "Sign data with the context's signer",
+		Subcommands: []*cli.Command{
+			typedDataCommand(),
+		},
+	}
+}
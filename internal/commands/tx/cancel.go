@@ -0,0 +1,48 @@
+package tx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func cancelCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "cancel",
+		Usage: "Cancel a stuck transaction",
+		Description: `Replaces the unconfirmed transaction with the given nonce with an empty
+transfer to the signer itself, with higher fees. Once it is mined, the
+original transaction can no longer be.`,
+		Flags:  replaceFlags(),
+		Action: cancelAction,
+	}
+}
+
+func cancelAction(c *cli.Context) error {
+	log := middleware.GetLogger(c)
+
+	client, sig, err := newClient(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	nonce := c.Uint64("nonce")
+	tx, err := client.CancelTransaction(context.Background(), nonce, c.Int("bump"))
+	if err != nil {
+		return fmt.Errorf("failed to cancel transaction: %w", err)
+	}
+
+	log.Info("Cancellation submitted",
+		zap.String("txHash", tx.Hash().Hex()),
+		zap.String("from", sig.Address().Hex()),
+		zap.Uint64("nonce", nonce))
+
+	fmt.Printf("Cancellation sent successfully!\n")
+	fmt.Printf("Transaction: %s\n", tx.Hash().Hex())
+	fmt.Printf("Nonce: %d\n", nonce)
+	return nil
+}
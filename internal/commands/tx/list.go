@@ -0,0 +1,52 @@
+package tx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/middleware"
+)
+
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "list",
+		Usage:  "List unconfirmed transactions",
+		Flags:  []cli.Flag{rpcURLFlag(), middleware.KeystorePasswordFileFlag()},
+		Action: listAction,
+	}
+}
+
+func listAction(c *cli.Context) error {
+	client, sig, err := newClient(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	pending, err := client.PendingTransactions(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		fmt.Printf("No unconfirmed transactions from %s\n", sig.Address().Hex())
+		return nil
+	}
+
+	table := tablewriter.NewWriter(c.App.Writer)
+	table.Header("NONCE", "STATUS", "HASH", "DESCRIPTION", "SENT")
+	for _, p := range pending {
+		table.Append([]string{
+			strconv.FormatUint(p.Nonce, 10),
+			p.Status,
+			p.Hash.Hex(),
+			p.Description,
+			p.SentAt.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+	table.Render()
+	return nil
+}
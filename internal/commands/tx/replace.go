@@ -0,0 +1,48 @@
+package tx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/middleware"
+	"go.uber.org/zap"
+)
+
+func replaceCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "replace",
+		Usage: "Resend a stuck transaction with higher fees",
+		Description: `Resends the unconfirmed transaction with the given nonce with its fees
+bumped, so it is mined instead of the original. Also resends a transaction
+the node dropped.`,
+		Flags:  replaceFlags(),
+		Action: replaceAction,
+	}
+}
+
+func replaceAction(c *cli.Context) error {
+	log := middleware.GetLogger(c)
+
+	client, sig, err := newClient(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	nonce := c.Uint64("nonce")
+	tx, err := client.ReplaceTransaction(context.Background(), nonce, c.Int("bump"))
+	if err != nil {
+		return fmt.Errorf("failed to replace transaction: %w", err)
+	}
+
+	log.Info("Replacement submitted",
+		zap.String("txHash", tx.Hash().Hex()),
+		zap.String("from", sig.Address().Hex()),
+		zap.Uint64("nonce", nonce))
+
+	fmt.Printf("Transaction replaced successfully!\n")
+	fmt.Printf("Transaction: %s\n", tx.Hash().Hex())
+	fmt.Printf("Nonce: %d\n", nonce)
+	return nil
+}
@@ -0,0 +1,77 @@
+package tx
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/signer"
+)
+
+// Command returns the tx command
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "tx",
+		Usage: "Manage transactions sent by the context's signer",
+		Description: `Lists transactions flickr sent that are not confirmed yet, and speeds up or
+cancels a stuck one by resending its nonce with higher fees. Nonces are
+tracked per signer and chain in ~/.flickr/nonces.`,
+		Subcommands: []*cli.Command{
+			listCommand(),
+			replaceCommand(),
+			cancelCommand(),
+		},
+	}
+}
+
+func rpcURLFlag() *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:  "rpc-url",
+		Usage: "Ethereum RPC URL (uses context if not provided)",
+	}
+}
+
+func replaceFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.Uint64Flag{
+			Name:     "nonce",
+			Usage:    "Nonce of the transaction",
+			Required: true,
+		},
+		&cli.IntFlag{
+			Name:  "bump",
+			Usage: "Fee increase in percent (at least 10)",
+			Value: eth.DefaultFeeBump,
+		},
+		rpcURLFlag(),
+		middleware.KeystorePasswordFileFlag(),
+	}
+}
+
+// newClient creates an Ethereum client with the context's signer
+func newClient(c *cli.Context) (*eth.Client, signer.Signer, error) {
+	currentCtx, err := middleware.GetCurrentContext(c)
+	if err != nil {
+		currentCtx = &config.Context{}
+	}
+
+	rpcURL, err := eth.ResolveRPCURL(c, currentCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sig, err := middleware.GetSigner(c, currentCtx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("no signer configured: %w", err)
+	}
+
+	// Plain transactions don't touch the ReleaseManager
+	client, err := eth.NewClientWithSigner(rpcURL, common.Address{}, sig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Ethereum client: %w", err)
+	}
+	return client, sig, nil
+}
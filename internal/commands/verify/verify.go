@@ -0,0 +1,252 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/olekukonko/tablewriter"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/controller"
+	"github.com/yourorg/flickr/internal/docker"
+	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/middleware"
+	"github.com/yourorg/flickr/internal/ref"
+	"github.com/yourorg/flickr/internal/registry"
+	"go.uber.org/zap"
+)
+
+// Command returns the verify command
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:  "verify",
+		Usage: "Check local images and containers against the on-chain releases",
+		Description: `Re-checks every locally cached image of the operator set's releases, and every
+container started by 'flickr run', against the digests recorded in the ReleaseManager.
+Exits with an error if any image or container does not match the chain.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "avs",
+				Usage: "AVS contract address (uses context if not provided)",
+			},
+			&cli.Uint64Flag{
+				Name:  "operator-set",
+				Usage: "Operator set ID (uses context if not provided)",
+			},
+			&cli.StringFlag{
+				Name:  "release-manager",
+				Usage: "ReleaseManager contract address (uses chain default if not provided)",
+			},
+			&cli.StringFlag{
+				Name:  "rpc-url",
+				Usage: "Ethereum RPC URL (uses context if not provided)",
+			},
+		},
+		Action: verifyAction,
+	}
+}
+
+// result is the outcome of verifying one image or container
+type result struct {
+	kind      string
+	name      string
+	reference string
+	err       error
+}
+
+func verifyAction(c *cli.Context) error {
+	log := middleware.GetLogger(c)
+
+	// Get context
+	currentCtx, err := middleware.GetCurrentContext(c)
+	if err != nil {
+		currentCtx = &config.Context{}
+	}
+
+	// Resolve the target: flag, FLICKR_* variable, context, chain default
+	target, rmAddr, err := eth.ResolveTarget(c, currentCtx)
+	if err != nil {
+		return err
+	}
+	avsAddress, operatorSetID, rpcURL := target.AVS.Hex(), target.OperatorSetID, target.RPCURL
+
+	avs := common.HexToAddress(avsAddress)
+
+	log.Info("Using configuration",
+		zap.String("avs", avs.Hex()),
+		zap.Uint32("operatorSet", operatorSetID),
+		zap.String("releaseManager", rmAddr.Hex()))
+
+	rmClient, err := eth.NewClient(rpcURL, rmAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create Ethereum client: %w", err)
+	}
+	defer rmClient.Close()
+
+	// Create Docker runner with the context's registry credentials
+	dockerRunner, cleanup, err := registry.NewRunner(currentCtx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+
+	images, err := verifyImages(ctx, rmClient, dockerRunner, currentCtx, avs, operatorSetID)
+	if err != nil {
+		return err
+	}
+
+	containers, err := verifyContainers(ctx, rmClient, dockerRunner, currentCtx)
+	if err != nil {
+		return err
+	}
+
+	results := append(images, containers...)
+	if len(results) == 0 {
+		fmt.Println("No local images or flickr containers found for this operator set")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(c.App.Writer)
+	table.Header("TYPE", "NAME", "REFERENCE", "STATUS")
+
+	failed := 0
+	for _, r := range results {
+		status := "ok"
+		if r.err != nil {
+			status = "FAILED: " + r.err.Error()
+			failed++
+		}
+		table.Append([]string{r.kind, r.name, r.reference, status})
+	}
+	table.Render()
+
+	if failed > 0 {
+		return fmt.Errorf("verification failed: %d of %d checks did not match the chain", failed, len(results))
+	}
+
+	fmt.Printf("\nAll %d images and containers match the chain\n", len(results))
+	return nil
+}
+
+// verifyImages checks every artifact of every release of the operator set
+// that is present in the local image store
+func verifyImages(ctx context.Context, rmClient *eth.Client, dockerRunner *docker.Runner, currentCtx *config.Context, avs common.Address, operatorSetID uint32) ([]result, error) {
+	total, err := rmClient.GetTotalReleases(ctx, avs, operatorSetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check releases: %w", err)
+	}
+
+	var results []result
+	for releaseID := uint64(0); releaseID < total.Uint64(); releaseID++ {
+		release, err := rmClient.GetRelease(ctx, avs, operatorSetID, releaseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get release %d: %w", releaseID, err)
+		}
+
+		for i, artifact := range release.Artifacts {
+			reference, err := artifactReference(artifact, currentCtx.RegistryMirrors)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build reference for release %d artifact %d: %w", releaseID, i, err)
+			}
+
+			if !dockerRunner.ImageExists(ctx, reference) {
+				continue
+			}
+
+			r := result{
+				kind:      "image",
+				name:      fmt.Sprintf("release %d", releaseID),
+				reference: reference,
+			}
+			repoDigests, err := dockerRunner.RepoDigests(ctx, reference)
+			if err != nil {
+				r.err = err
+			} else {
+				r.err = ref.VerifyRepoDigests(reference, ref.Digest32ToSha256String(artifact.Digest32), repoDigests)
+			}
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+// verifyContainers checks every container started by 'flickr run' against
+// the release recorded in its labels
+func verifyContainers(ctx context.Context, rmClient *eth.Client, dockerRunner *docker.Runner, currentCtx *config.Context) ([]result, error) {
+	containers, err := dockerRunner.Containers(ctx, controller.LabelReference)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]result, 0, len(containers))
+	for _, ctr := range containers {
+		r := result{
+			kind:      "container",
+			name:      ctr.Name,
+			reference: ctr.Labels[controller.LabelReference],
+		}
+		r.err = verifyContainer(ctx, rmClient, dockerRunner, currentCtx, ctr)
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func verifyContainer(ctx context.Context, rmClient *eth.Client, dockerRunner *docker.Runner, currentCtx *config.Context, ctr docker.Container) error {
+	avsLabel := ctr.Labels[controller.LabelAVS]
+	if !common.IsHexAddress(avsLabel) {
+		return fmt.Errorf("invalid %s label %q", controller.LabelAVS, avsLabel)
+	}
+	operatorSetID, err := strconv.ParseUint(ctr.Labels[controller.LabelOperatorSet], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid %s label: %w", controller.LabelOperatorSet, err)
+	}
+	releaseID, err := strconv.ParseUint(ctr.Labels[controller.LabelReleaseID], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s label: %w", controller.LabelReleaseID, err)
+	}
+
+	release, err := rmClient.GetRelease(ctx, common.HexToAddress(avsLabel), uint32(operatorSetID), releaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get release %d: %w", releaseID, err)
+	}
+	if len(release.Artifacts) == 0 {
+		return fmt.Errorf("release %d has no artifacts", releaseID)
+	}
+
+	// 'flickr run' starts the first artifact of a release
+	expected, err := artifactReference(release.Artifacts[0], currentCtx.RegistryMirrors)
+	if err != nil {
+		return err
+	}
+	if label := ctr.Labels[controller.LabelReference]; label != expected {
+		return fmt.Errorf("container was started from %s, release %d on-chain is %s", label, releaseID, expected)
+	}
+
+	repoDigests, err := dockerRunner.RepoDigests(ctx, expected)
+	if err != nil {
+		return fmt.Errorf("on-chain image %s is not present locally", expected)
+	}
+	if err := ref.VerifyRepoDigests(expected, ref.Digest32ToSha256String(release.Artifacts[0].Digest32), repoDigests); err != nil {
+		return err
+	}
+
+	imageID, err := dockerRunner.ImageID(ctx, expected)
+	if err != nil {
+		return err
+	}
+	if ctr.Image != imageID {
+		return fmt.Errorf("container image %s differs from on-chain image %s", ctr.Image, imageID)
+	}
+	return nil
+}
+
+// artifactReference builds the local reference of an artifact, fetched from
+// a mirror if one is configured
+func artifactReference(artifact eth.Artifact, mirrors map[string]string) (string, error) {
+	digest := ref.Digest32ToSha256String(artifact.Digest32)
+	return ref.BuildReference(ref.RewriteRegistry(artifact.Registry, mirrors), digest)
+}
@@ -2,9 +2,13 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
 )
 
 // ContextKey is the key used to store context in cli.Context
@@ -19,6 +23,7 @@ const (
 
 // Config represents the CLI configuration
 type Config struct {
+	Version        int                 `json:"version"` // Format version, see CurrentVersion
 	CurrentContext string              `json:"currentContext,omitempty"`
 	Contexts       map[string]*Context `json:"contexts,omitempty"`
 }
@@ -30,35 +35,165 @@ type Context struct {
 	OperatorSetID    uint32 `json:"operatorSetId,omitempty"`
 	ReleaseManager   string `json:"releaseManager,omitempty"`
 	RPCURL           string `json:"rpcUrl,omitempty"`
+	ChainID          uint64 `json:"chainId,omitempty"` // Chain the RPC URL must serve; commands refuse other chains
 	
 	// Optional settings
 	Name             string            `json:"name,omitempty"`
 	EnvironmentVars  map[string]string `json:"environmentVars,omitempty"`
+	RegistryMirrors  map[string]string `json:"registryMirrors,omitempty"` // Source registry prefix -> mirror registry
+	PolicyPath       string            `json:"policyPath,omitempty"`      // Attestation policy file checked by pull/run
+	Ports            []string          `json:"ports,omitempty"`           // Published container ports (docker run -p)
+	Volumes          []string          `json:"volumes,omitempty"`         // Container mounts (docker run -v)
+
+	// Operator sets served from this context by name. When set, run and pull
+	// act on every deployment, or those selected with --deployment.
+	Deployments map[string]*Deployment `json:"deployments,omitempty"`
+
+	// Registry credentials by registry host (references only, never secrets)
+	RegistryAuth map[string]*RegistryCredential `json:"registryAuth,omitempty"`
 	
-	// ECDSA Signer configuration (mutually exclusive)
-	ECDSAPrivateKey    string `json:"ecdsaPrivateKey,omitempty"`    // Hex-encoded private key
-	KeystorePath       string `json:"keystorePath,omitempty"`       // Path to keystore file
-	KeystorePassword   string `json:"keystorePassword,omitempty"`   // Keystore password
+	// ECDSA Signer configuration (mutually exclusive). Secrets are stored as
+	// references (env:, file:, cmd: or secret:), never as values.
+	ECDSAPrivateKeyRef  string `json:"ecdsaPrivateKeyRef,omitempty"`  // Reference to the hex-encoded private key
+	KeystorePath        string `json:"keystorePath,omitempty"`        // Path to keystore file
+	KeystorePasswordRef string `json:"keystorePasswordRef,omitempty"` // Reference to the keystore password
+
+	// Remote signer; keys stay on the signer
+	RemoteSigner *RemoteSigner `json:"remoteSigner,omitempty"`
+
+	// Cloud KMS signer; keys stay in the KMS
+	KMSSigner *KMSSigner `json:"kmsSigner,omitempty"`
+
+	// External signer program, e.g. a wrapper around in-house HSM tooling
+	ExecSigner *ExecSigner `json:"execSigner,omitempty"`
+
+	// Plaintext secrets from older configs. They are moved to files readable
+	// only by the user when the config is loaded or saved.
+	ECDSAPrivateKey  string `json:"ecdsaPrivateKey,omitempty"`  // Deprecated: use ECDSAPrivateKeyRef
+	KeystorePassword string `json:"keystorePassword,omitempty"` // Deprecated: use KeystorePasswordRef
+}
+
+// RemoteSigner configures a Web3Signer-compatible remote signer
+type RemoteSigner struct {
+	URL        string `json:"url"`
+	Address    string `json:"address"`              // Address of the key held by the signer
+	CACert     string `json:"caCert,omitempty"`     // CA certificate to verify the signer with
+	ClientCert string `json:"clientCert,omitempty"` // Client certificate for mutual TLS
+	ClientKey  string `json:"clientKey,omitempty"`  // Client private key for mutual TLS
 }
 
-// GetConfigPath returns the path to the config file
+// KMS providers
+const (
+	KMSProviderAWS = "aws"
+	KMSProviderGCP = "gcp"
+)
+
+// KMSSigner configures a secp256k1 key held in a cloud KMS
+type KMSSigner struct {
+	Provider string `json:"provider"`           // aws or gcp
+	KeyID    string `json:"keyId"`              // AWS key ID/ARN/alias, or GCP key version name
+	Region   string `json:"region,omitempty"`   // AWS region
+	Endpoint string `json:"endpoint,omitempty"` // Custom endpoint, e.g. a local KMS emulator
+}
+
+// ExecSigner configures an external program that signs hashes
+type ExecSigner struct {
+	Command string   `json:"command"`           // Program to run for every signature
+	Args    []string `json:"args,omitempty"`    // Arguments passed to the program
+	Address string   `json:"address,omitempty"` // Address of the program's key; asked from the program if empty
+}
+
+// RegistryCredential references where the credentials for a registry come
+// from. Exactly one of Helper, TokenFile or TokenEnv is set.
+type RegistryCredential struct {
+	Helper    string `json:"helper,omitempty"`    // Docker credential helper (docker-credential-<helper>)
+	Username  string `json:"username,omitempty"`  // Username for token credentials
+	TokenFile string `json:"tokenFile,omitempty"` // File containing the password or token
+	TokenEnv  string `json:"tokenEnv,omitempty"`  // Environment variable containing the password or token
+}
+
+// Source describes where the credential is read from, for display
+func (r *RegistryCredential) Source() (string, string) {
+	switch {
+	case r.Helper != "":
+		return "helper", r.Helper
+	case r.TokenFile != "":
+		return "token-file", r.TokenFile
+	case r.TokenEnv != "":
+		return "token-env", r.TokenEnv
+	default:
+		return "none", ""
+	}
+}
+
+// EnvConfig overrides the config file location
+const EnvConfig = "FLICKR_CONFIG"
+
+// configPathOverride is set by SetConfigPath
+var configPathOverride string
+
+// SetConfigPath overrides the config file location for this process, e.g.
+// from the --config flag. It takes precedence over FLICKR_CONFIG.
+func SetConfigPath(path string) {
+	configPathOverride = path
+}
+
+// GetConfigPath returns the path to the config file: the --config path,
+// FLICKR_CONFIG, $XDG_CONFIG_HOME/flickr/config.json when XDG_CONFIG_HOME is
+// set and no ~/.flickr/config.json exists, or ~/.flickr/config.json. Secrets,
+// keystores and nonces are kept next to the config file.
 func GetConfigPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	configPath, err := resolveConfigPath()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
 
-	configDir := filepath.Join(homeDir, ".flickr")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
 		return "", fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	return filepath.Join(configDir, "config.json"), nil
+	return configPath, nil
+}
+
+// ErrNoHome is returned when the config path defaults to the home directory
+// and there is none, e.g. in containers
+var ErrNoHome = errors.New("failed to get home directory")
+
+func resolveConfigPath() (string, error) {
+	override := configPathOverride
+	if override == "" {
+		override = os.Getenv(EnvConfig)
+	}
+	if override != "" {
+		// Secret references written next to the config must stay valid
+		// from other working directories
+		abs, err := filepath.Abs(override)
+		if err != nil {
+			return "", fmt.Errorf("invalid config path %s: %w", override, err)
+		}
+		return abs, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("%w (set --config or %s): %w", ErrNoHome, EnvConfig, err)
+	}
+
+	legacyPath := filepath.Join(homeDir, ".flickr", "config.json")
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		if _, err := os.Stat(legacyPath); os.IsNotExist(err) {
+			return filepath.Join(xdg, "flickr", "config.json"), nil
+		}
+	}
+
+	return legacyPath, nil
 }
 
 // LoadConfig loads the configuration from disk
 func LoadConfig() (*Config, error) {
-	configPath, err := GetConfigPath()
+	// Reading doesn't create the config directory, so a missing config
+	// loads in read-only homes
+	configPath, err := resolveConfigPath()
 	if err != nil {
 		return nil, err
 	}
@@ -68,22 +203,41 @@ func LoadConfig() (*Config, error) {
 		if os.IsNotExist(err) {
 			// Return empty config if file doesn't exist
 			return &Config{
+				Version:  CurrentVersion,
 				Contexts: make(map[string]*Context),
 			}, nil
 		}
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	// Upgrade files written by older versions, keeping a backup
+	data, upgraded, err := migrate(configPath, data)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := decodeConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", configPath, err)
 	}
 
-	if cfg.Contexts == nil {
-		cfg.Contexts = make(map[string]*Context)
+	if upgraded != nil {
+		if err := writeConfig(configPath, cfg); err != nil {
+			return nil, fmt.Errorf("failed to save the config file upgraded to version %d (%s must be writable once to upgrade it): %w", upgraded.to, configPath, err)
+		}
+		for _, note := range upgraded.notes {
+			fmt.Fprintln(os.Stderr, note)
+		}
+		fmt.Fprintf(os.Stderr, "Upgraded config file from version %d to %d (backup: %s)\n", upgraded.from, upgraded.to, upgraded.backup)
+	} else if info, err := os.Stat(configPath); err == nil && info.Mode().Perm()&0077 != 0 {
+		// Tighten permissions of configs written by older versions. Configs
+		// mounted read-only, e.g. in containers, are left as they are.
+		if err := os.Chmod(configPath, 0600); err != nil && !errors.Is(err, syscall.EROFS) {
+			return nil, fmt.Errorf("failed to set config file permissions: %w", err)
+		}
 	}
 
-	return &cfg, nil
+	return cfg, nil
 }
 
 // SaveConfig saves the configuration to disk
@@ -93,18 +247,126 @@ func SaveConfig(cfg *Config) error {
 		return err
 	}
 
+	if _, err := migrateSecrets(cfg); err != nil {
+		return err
+	}
+	cfg.Version = CurrentVersion
+
+	// Never write a config the next load would reject
+	names := make([]string, 0, len(cfg.Contexts))
+	for name := range cfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ctx := cfg.Contexts[name]; ctx != nil {
+			if err := ctx.Validate(); err != nil {
+				return fmt.Errorf("context '%s': %w", name, err)
+			}
+		}
+	}
+
+	return writeConfig(configPath, cfg)
+}
+
+// writeConfig writes the configuration readable only by the user
+func writeConfig(configPath string, cfg *Config) error {
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	// Write to a temporary file and rename it over the config, so a failed
+	// write never leaves a truncated config behind
+	tmpPath := configPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to set config file permissions: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		os.Remove(tmpPath)
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
 	return nil
 }
 
+// migrateSecrets moves plaintext secrets into files under ~/.flickr/secrets
+// readable only by the user, replacing them with file: references. It returns
+// the paths of the files written.
+func migrateSecrets(cfg *Config) ([]string, error) {
+	var written []string
+	for name, ctx := range cfg.Contexts {
+		if ctx == nil {
+			continue
+		}
+
+		if ctx.ECDSAPrivateKey != "" {
+			path, err := writeSecretFile(name, "ecdsa-private-key", ctx.ECDSAPrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			ctx.ECDSAPrivateKeyRef = "file:" + path
+			ctx.ECDSAPrivateKey = ""
+			written = append(written, path)
+		}
+
+		if ctx.KeystorePassword != "" {
+			path, err := writeSecretFile(name, "keystore-password", ctx.KeystorePassword)
+			if err != nil {
+				return nil, err
+			}
+			ctx.KeystorePasswordRef = "file:" + path
+			ctx.KeystorePassword = ""
+			written = append(written, path)
+		}
+	}
+	sort.Strings(written)
+	return written, nil
+}
+
+// writeSecretFile writes a secret of a context to ~/.flickr/secrets/<context>/<name>
+func writeSecretFile(contextName, name, value string) (string, error) {
+	dir, err := secretDir(contextName)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(value+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write secret file: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		return "", fmt.Errorf("failed to set secret file permissions: %w", err)
+	}
+	return path, nil
+}
+
+// secretDir returns the directory holding the secret files of a context
+func secretDir(contextName string) (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+
+	// Context names are user provided; keep them inside the secrets directory
+	dirName := strings.NewReplacer("/", "_", "\\", "_").Replace(contextName)
+	if dirName == "" || dirName == "." || dirName == ".." {
+		dirName = "_" + dirName
+	}
+
+	return filepath.Join(filepath.Dir(configPath), "secrets", dirName), nil
+}
+
 // GetCurrentContext returns the current context
 func GetCurrentContext() (*Context, error) {
 	cfg, err := LoadConfig()
@@ -140,22 +402,91 @@ func (c *Context) ToMap() map[string]interface{} {
 	if c.RPCURL != "" {
 		m["rpc-url"] = c.RPCURL
 	}
+	if c.ChainID != 0 {
+		m["chain-id"] = c.ChainID
+	}
 	if c.Name != "" {
 		m["name"] = c.Name
 	}
 	if len(c.EnvironmentVars) > 0 {
 		m["environment-vars"] = c.EnvironmentVars
 	}
+	if len(c.RegistryMirrors) > 0 {
+		m["registry-mirrors"] = c.RegistryMirrors
+	}
+	if c.PolicyPath != "" {
+		m["policy"] = c.PolicyPath
+	}
+	if len(c.Ports) > 0 {
+		m["ports"] = c.Ports
+	}
+	if len(c.Volumes) > 0 {
+		m["volumes"] = c.Volumes
+	}
+	if len(c.Deployments) > 0 {
+		deployments := make(map[string]interface{})
+		for name, d := range c.Deployments {
+			deployments[name] = d.toMap()
+		}
+		m["deployments"] = deployments
+	}
+	if len(c.RegistryAuth) > 0 {
+		auth := make(map[string]string)
+		for host, cred := range c.RegistryAuth {
+			kind, source := cred.Source()
+			auth[host] = kind + ":" + source
+		}
+		m["registry-auth"] = auth
+	}
 	
-	// Add signer info
-	if c.ECDSAPrivateKey != "" {
-		m["ecdsa-private-key"] = c.ECDSAPrivateKey
+	// Add signer info (references only)
+	if c.ECDSAPrivateKeyRef != "" {
+		m["ecdsa-private-key"] = c.ECDSAPrivateKeyRef
 	}
 	if c.KeystorePath != "" {
 		m["keystore-path"] = c.KeystorePath
-		if c.KeystorePassword != "" {
-			m["keystore-password"] = c.KeystorePassword
+		if c.KeystorePasswordRef != "" {
+			m["keystore-password"] = c.KeystorePasswordRef
+		}
+	}
+	if c.RemoteSigner != nil {
+		remote := map[string]string{
+			"url":     c.RemoteSigner.URL,
+			"address": c.RemoteSigner.Address,
+		}
+		if c.RemoteSigner.CACert != "" {
+			remote["ca-cert"] = c.RemoteSigner.CACert
+		}
+		if c.RemoteSigner.ClientCert != "" {
+			remote["client-cert"] = c.RemoteSigner.ClientCert
+			remote["client-key"] = c.RemoteSigner.ClientKey
+		}
+		m["remote-signer"] = remote
+	}
+	if c.KMSSigner != nil {
+		kms := map[string]string{
+			"provider": c.KMSSigner.Provider,
+			"key-id":   c.KMSSigner.KeyID,
+		}
+		if c.KMSSigner.Region != "" {
+			kms["region"] = c.KMSSigner.Region
+		}
+		if c.KMSSigner.Endpoint != "" {
+			kms["endpoint"] = c.KMSSigner.Endpoint
+		}
+		m["kms-signer"] = kms
+	}
+	if c.ExecSigner != nil {
+		execSigner := map[string]string{
+			"command": c.ExecSigner.Command,
+		}
+		if len(c.ExecSigner.Args) > 0 {
+			execSigner["args"] = strings.Join(c.ExecSigner.Args, " ")
+		}
+		if c.ExecSigner.Address != "" {
+			execSigner["address"] = c.ExecSigner.Address
 		}
+		m["exec-signer"] = execSigner
 	}
 	
 	return m
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+func TestLoadConfig_MigratesPlaintextSecrets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath, err := GetConfigPath()
+	require.NoError(t, err)
+
+	legacy := `{
+  "currentContext": "mainnet",
+  "contexts": {
+    "mainnet": {"ecdsaPrivateKey": "` + testPrivateKey + `"},
+    "holesky": {"keystorePath": "/keys/op.json", "keystorePassword": "hunter2"}
+  }
+}`
+	require.NoError(t, os.WriteFile(configPath, []byte(legacy), 0644))
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+
+	mainnet := cfg.Contexts["mainnet"]
+	assert.Empty(t, mainnet.ECDSAPrivateKey)
+	require.True(t, strings.HasPrefix(mainnet.ECDSAPrivateKeyRef, "file:"))
+	keyPath := strings.TrimPrefix(mainnet.ECDSAPrivateKeyRef, "file:")
+	assert.Equal(t, filepath.Join(home, ".flickr", "secrets", "mainnet", "ecdsa-private-key"), keyPath)
+
+	data, err := os.ReadFile(keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, testPrivateKey, strings.TrimSpace(string(data)))
+
+	holesky := cfg.Contexts["holesky"]
+	assert.Empty(t, holesky.KeystorePassword)
+	assert.True(t, strings.HasPrefix(holesky.KeystorePasswordRef, "file:"))
+
+	// The rewritten config holds no secrets and is private to the user
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), testPrivateKey)
+	assert.NotContains(t, string(data), "hunter2")
+
+	for _, path := range []string{configPath, keyPath} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), path)
+	}
+}
+
+func TestSaveConfig_NeverWritesPlaintext(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := &Config{
+		CurrentContext: "dev",
+		Contexts: map[string]*Context{
+			"dev": {ECDSAPrivateKey: testPrivateKey},
+		},
+	}
+	require.NoError(t, SaveConfig(cfg))
+
+	configPath, err := GetConfigPath()
+	require.NoError(t, err)
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), testPrivateKey)
+	assert.Contains(t, string(data), "ecdsaPrivateKeyRef")
+}
+
+func TestLoadConfig_TightensPermissions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	configPath, err := GetConfigPath()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"currentContext": "dev"}`), 0644))
+
+	_, err = LoadConfig()
+	require.NoError(t, err)
+
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestToMap_RedactsSecrets(t *testing.T) {
+	// Only references are shown
+	m := (&Context{ECDSAPrivateKey: testPrivateKey}).ToMap()
+	assert.NotContains(t, m, "ecdsa-private-key")
+
+	m = (&Context{ECDSAPrivateKeyRef: "env:FLICKR_KEY"}).ToMap()
+	assert.Equal(t, "env:FLICKR_KEY", m["ecdsa-private-key"])
+
+	m = (&Context{KeystorePath: "/keys/op.json", KeystorePassword: "hunter2"}).ToMap()
+	assert.NotContains(t, m, "keystore-password")
+
+	m = (&Context{KeystorePath: "/keys/op.json", KeystorePasswordRef: "file:/keys/password"}).ToMap()
+	assert.Equal(t, "file:/keys/password", m["keystore-password"])
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Deployment is one operator set served from a context, run as its own
+// container. Settings it leaves empty are taken from the context.
+type Deployment struct {
+	AVSAddress      string            `json:"avsAddress,omitempty"`      // Defaults to the context's AVS
+	OperatorSetID   uint32            `json:"operatorSetId,omitempty"`   // Operator set of the AVS
+	Name            string            `json:"name,omitempty"`            // Container name; defaults to <context name>-<deployment>
+	EnvironmentVars map[string]string `json:"environmentVars,omitempty"` // Merged over the context's variables
+	Ports           []string          `json:"ports,omitempty"`           // Added to the context's ports
+	Volumes         []string          `json:"volumes,omitempty"`         // Added to the context's volumes
+}
+
+// deploymentNamePattern matches deployment names, which are used in
+// container names
+var deploymentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ValidateDeploymentName checks that a deployment name can be used in
+// container names
+func ValidateDeploymentName(name string) error {
+	if !deploymentNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid deployment name %q (letters, digits, '_', '.' and '-')", name)
+	}
+	return nil
+}
+
+// DeploymentNames returns the names of the context's deployments, sorted
+func (c *Context) DeploymentNames() []string {
+	names := make([]string, 0, len(c.Deployments))
+	for name := range c.Deployments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelectDeployments returns the named deployments, or all deployments if no
+// names are given, sorted
+func (c *Context) SelectDeployments(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return c.DeploymentNames(), nil
+	}
+
+	selected := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, exists := c.Deployments[name]; !exists {
+			return nil, fmt.Errorf("deployment '%s' not found (available: %s)", name, strings.Join(c.DeploymentNames(), ", "))
+		}
+		if !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+	sort.Strings(selected)
+	return selected, nil
+}
+
+// Deployment returns the context a deployment runs with: the context with
+// the deployment's settings layered over it, and without deployments
+func (c *Context) Deployment(name string) (*Context, error) {
+	d, exists := c.Deployments[name]
+	if !exists {
+		return nil, fmt.Errorf("deployment '%s' not found", name)
+	}
+
+	ctx, err := c.Clone()
+	if err != nil {
+		return nil, err
+	}
+	ctx.Deployments = nil
+
+	if d.AVSAddress != "" {
+		ctx.AVSAddress = d.AVSAddress
+	}
+	ctx.OperatorSetID = d.OperatorSetID
+
+	switch {
+	case d.Name != "":
+		ctx.Name = d.Name
+	case c.Name != "":
+		ctx.Name = c.Name + "-" + name
+	default:
+		ctx.Name = name
+	}
+
+	if len(d.EnvironmentVars) > 0 && ctx.EnvironmentVars == nil {
+		ctx.EnvironmentVars = make(map[string]string)
+	}
+	for k, v := range d.EnvironmentVars {
+		ctx.EnvironmentVars[k] = v
+	}
+	ctx.Ports = append(ctx.Ports, d.Ports...)
+	ctx.Volumes = append(ctx.Volumes, d.Volumes...)
+
+	return ctx, nil
+}
+
+// validate checks that the settings of a deployment are well formed
+func (d *Deployment) validate() error {
+	if d == nil {
+		return fmt.Errorf("deployment is empty")
+	}
+	if d.AVSAddress != "" && !common.IsHexAddress(d.AVSAddress) {
+		return fmt.Errorf("avsAddress: invalid address %q", d.AVSAddress)
+	}
+	for _, volume := range d.Volumes {
+		if !strings.Contains(volume, ":") {
+			return fmt.Errorf("volumes: invalid volume %q (expected SOURCE:TARGET)", volume)
+		}
+	}
+	return nil
+}
+
+// toMap returns the deployment's settings for display, keyed like ToMap
+func (d *Deployment) toMap() map[string]interface{} {
+	m := map[string]interface{}{"operator-set-id": d.OperatorSetID}
+	if d.AVSAddress != "" {
+		m["avs-address"] = d.AVSAddress
+	}
+	if d.Name != "" {
+		m["name"] = d.Name
+	}
+	if len(d.EnvironmentVars) > 0 {
+		m["environment-vars"] = d.EnvironmentVars
+	}
+	if len(d.Ports) > 0 {
+		m["ports"] = d.Ports
+	}
+	if len(d.Volumes) > 0 {
+		m["volumes"] = d.Volumes
+	}
+	return m
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeploymentContext() *Context {
+	return &Context{
+		AVSAddress:      testAVS,
+		OperatorSetID:   9,
+		RPCURL:          "https://rpc.example.com",
+		Name:            "my-avs",
+		EnvironmentVars: map[string]string{"NETWORK": "holesky", "LOG_LEVEL": "info"},
+		Ports:           []string{"9000:9000"},
+		Deployments: map[string]*Deployment{
+			"aggregator": {
+				OperatorSetID:   1,
+				EnvironmentVars: map[string]string{"LOG_LEVEL": "debug"},
+				Ports:           []string{"9001:9001"},
+			},
+			"other": {
+				AVSAddress: testRM,
+				Name:       "other-avs",
+				Volumes:    []string{"data:/data"},
+			},
+		},
+	}
+}
+
+func TestContext_Deployment(t *testing.T) {
+	ctx := testDeploymentContext()
+
+	aggregator, err := ctx.Deployment("aggregator")
+	require.NoError(t, err)
+	assert.Equal(t, testAVS, aggregator.AVSAddress)
+	assert.Equal(t, uint32(1), aggregator.OperatorSetID)
+	assert.Equal(t, "my-avs-aggregator", aggregator.Name)
+	assert.Equal(t, "https://rpc.example.com", aggregator.RPCURL)
+	assert.Equal(t, map[string]string{"NETWORK": "holesky", "LOG_LEVEL": "debug"}, aggregator.EnvironmentVars)
+	assert.Equal(t, []string{"9000:9000", "9001:9001"}, aggregator.Ports)
+	assert.Nil(t, aggregator.Deployments)
+
+	other, err := ctx.Deployment("other")
+	require.NoError(t, err)
+	assert.Equal(t, testRM, other.AVSAddress)
+	assert.Equal(t, uint32(0), other.OperatorSetID)
+	assert.Equal(t, "other-avs", other.Name)
+	assert.Equal(t, []string{"data:/data"}, other.Volumes)
+
+	// The context is unchanged
+	assert.Equal(t, "info", ctx.EnvironmentVars["LOG_LEVEL"])
+	assert.Equal(t, []string{"9000:9000"}, ctx.Ports)
+
+	_, err = ctx.Deployment("missing")
+	assert.ErrorContains(t, err, "deployment 'missing' not found")
+}
+
+func TestContext_SelectDeployments(t *testing.T) {
+	ctx := testDeploymentContext()
+
+	names, err := ctx.SelectDeployments(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aggregator", "other"}, names)
+
+	names, err = ctx.SelectDeployments([]string{"other", "other"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, names)
+
+	_, err = ctx.SelectDeployments([]string{"missing"})
+	assert.ErrorContains(t, err, "deployment 'missing' not found (available: aggregator, other)")
+}
+
+func TestContext_ValidateDeployments(t *testing.T) {
+	tests := []struct {
+		name        string
+		deployments map[string]*Deployment
+		wantErr     string
+	}{
+		{
+			name:        "valid",
+			deployments: testDeploymentContext().Deployments,
+		},
+		{
+			name:        "invalid name",
+			deployments: map[string]*Deployment{"my avs": {}},
+			wantErr:     `deployments: invalid deployment name "my avs"`,
+		},
+		{
+			name:        "invalid address",
+			deployments: map[string]*Deployment{"a": {AVSAddress: "0x123"}},
+			wantErr:     `deployments.a: avsAddress: invalid address "0x123"`,
+		},
+		{
+			name:        "invalid volume",
+			deployments: map[string]*Deployment{"a": {Volumes: []string{"data"}}},
+			wantErr:     `deployments.a: volumes: invalid volume "data"`,
+		},
+		{
+			name:        "empty",
+			deployments: map[string]*Deployment{"a": nil},
+			wantErr:     "deployments.a: deployment is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Context{Deployments: tt.deployments}).Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Environment variables overriding the settings of the context. Signer keys
+// and passwords are read from the environment by the signer package.
+const (
+	EnvContext             = "FLICKR_CONTEXT" // Selects the context, overriding the current context
+	EnvAVSAddress          = "FLICKR_AVS_ADDRESS"
+	EnvOperatorSetID       = "FLICKR_OPERATOR_SET_ID"
+	EnvReleaseManager      = "FLICKR_RELEASE_MANAGER"
+	EnvRPCURL              = "FLICKR_RPC_URL"
+	EnvChainID             = "FLICKR_CHAIN_ID"
+	EnvContainerName       = "FLICKR_CONTAINER_NAME"
+	EnvPolicy              = "FLICKR_POLICY"
+	EnvPorts               = "FLICKR_PORTS"            // Comma-separated
+	EnvVolumes             = "FLICKR_VOLUMES"          // Comma-separated
+	EnvRegistryMirrors     = "FLICKR_REGISTRY_MIRRORS" // Comma-separated SOURCE=MIRROR
+	EnvKeystorePath        = "FLICKR_KEYSTORE_PATH"
+	EnvRemoteSignerURL     = "FLICKR_REMOTE_SIGNER_URL"
+	EnvRemoteSignerAddress = "FLICKR_REMOTE_SIGNER_ADDRESS"
+	EnvKMSProvider         = "FLICKR_KMS_PROVIDER"
+	EnvKMSKeyID            = "FLICKR_KMS_KEY_ID"
+	EnvKMSRegion           = "FLICKR_KMS_REGION"
+
+	// EnvContainerEnvPrefix sets container environment variables:
+	// FLICKR_ENV_LOG_LEVEL=debug sets LOG_LEVEL=debug
+	EnvContainerEnvPrefix = "FLICKR_ENV_"
+)
+
+// envSetting maps an environment variable to a context field
+type envSetting struct {
+	env   string
+	field string // JSON name of the context field
+	key   string // Name in ToMap
+	parse func(string) (interface{}, error)
+}
+
+var envSettings = []envSetting{
+	{EnvAVSAddress, "avsAddress", "avs-address", parseEnvString},
+	{EnvOperatorSetID, "operatorSetId", "operator-set-id", parseEnvUint32},
+	{EnvReleaseManager, "releaseManager", "release-manager", parseEnvString},
+	{EnvRPCURL, "rpcUrl", "rpc-url", parseEnvString},
+	{EnvChainID, "chainId", "chain-id", parseEnvUint64},
+	{EnvContainerName, "name", "name", parseEnvString},
+	{EnvPolicy, "policyPath", "policy", parseEnvString},
+	{EnvPorts, "ports", "ports", parseEnvList},
+	{EnvVolumes, "volumes", "volumes", parseEnvList},
+	{EnvRegistryMirrors, "registryMirrors", "registry-mirrors", parseEnvMap},
+}
+
+// signerFields are the JSON names of the mutually exclusive signer settings
+var signerFields = []string{"ecdsaPrivateKeyRef", "keystorePath", "keystorePasswordRef", "remoteSigner", "kmsSigner", "execSigner"}
+
+// envOverrides returns the context fields set by FLICKR_* variables in
+// environ, and the ToMap keys they set mapped to the variable names
+func envOverrides(environ []string) (map[string]interface{}, map[string]string, error) {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(k, "FLICKR_") && v != "" {
+			env[k] = v
+		}
+	}
+
+	fields := make(map[string]interface{})
+	origins := make(map[string]string)
+	for _, s := range envSettings {
+		value, ok := env[s.env]
+		if !ok {
+			continue
+		}
+		parsed, err := s.parse(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %w", s.env, err)
+		}
+		fields[s.field] = parsed
+		origins[s.key] = "env " + s.env
+	}
+
+	// Container environment variables
+	containerEnv := make(map[string]interface{})
+	for k, v := range env {
+		if name := strings.TrimPrefix(k, EnvContainerEnvPrefix); name != k && name != "" {
+			containerEnv[name] = v
+			origins["environment-vars."+name] = "env " + k
+		}
+	}
+	if len(containerEnv) > 0 {
+		fields["environmentVars"] = containerEnv
+	}
+
+	// A signer from the environment replaces the context's signer
+	var signer string
+	switch {
+	case env[EnvKeystorePath] != "":
+		signer = "keystore-path"
+		fields["keystorePath"] = env[EnvKeystorePath]
+		origins[signer] = "env " + EnvKeystorePath
+	case env[EnvRemoteSignerURL] != "":
+		signer = "remote-signer"
+		fields["remoteSigner"] = map[string]interface{}{
+			"url":     env[EnvRemoteSignerURL],
+			"address": env[EnvRemoteSignerAddress],
+		}
+		origins[signer] = "env " + EnvRemoteSignerURL
+	case env[EnvKMSProvider] != "":
+		signer = "kms-signer"
+		fields["kmsSigner"] = map[string]interface{}{
+			"provider": env[EnvKMSProvider],
+			"keyId":    env[EnvKMSKeyID],
+			"region":   env[EnvKMSRegion],
+		}
+		origins[signer] = "env " + EnvKMSProvider
+	}
+	if signer != "" {
+		for _, field := range signerFields {
+			if _, set := fields[field]; !set {
+				fields[field] = nil
+			}
+		}
+	}
+
+	return fields, origins, nil
+}
+
+func parseEnvString(value string) (interface{}, error) {
+	return value, nil
+}
+
+func parseEnvUint32(value string) (interface{}, error) {
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("expected a number, got %q", value)
+	}
+	return n, nil
+}
+
+func parseEnvUint64(value string) (interface{}, error) {
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected a number, got %q", value)
+	}
+	return n, nil
+}
+
+func parseEnvList(value string) (interface{}, error) {
+	var list []interface{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
+
+func parseEnvMap(value string) (interface{}, error) {
+	m := make(map[string]interface{})
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(item, "=")
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("expected SOURCE=MIRROR, got %q", item)
+		}
+		m[k] = v
+	}
+	return m, nil
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// UnsetFields are the context fields that can be cleared with Unset. The
+// names match the keys of ToMap.
+var UnsetFields = []string{
+	"avs-address",
+	"operator-set-id",
+	"release-manager",
+	"rpc-url",
+	"chain-id",
+	"name",
+	"environment-vars",
+	"registry-mirrors",
+	"policy",
+	"ports",
+	"volumes",
+	"deployments",
+	"registry-auth",
+	"ecdsa-private-key",
+	"keystore-path",
+	"keystore-password",
+	"remote-signer",
+	"kms-signer",
+	"exec-signer",
+	"signer",
+}
+
+// DeleteContext removes a context. Deleting the current context leaves no
+// context selected.
+func (cfg *Config) DeleteContext(name string) error {
+	if _, exists := cfg.Contexts[name]; !exists {
+		return fmt.Errorf("context '%s' not found", name)
+	}
+
+	delete(cfg.Contexts, name)
+	if cfg.CurrentContext == name {
+		cfg.CurrentContext = ""
+	}
+	return nil
+}
+
+// RenameContext renames a context, keeping it current if it was
+func (cfg *Config) RenameContext(oldName, newName string) error {
+	ctx, exists := cfg.Contexts[oldName]
+	if !exists {
+		return fmt.Errorf("context '%s' not found", oldName)
+	}
+	if newName == "" {
+		return fmt.Errorf("new context name is required")
+	}
+	if _, exists := cfg.Contexts[newName]; exists {
+		return fmt.Errorf("context '%s' already exists", newName)
+	}
+
+	cfg.Contexts[newName] = ctx
+	delete(cfg.Contexts, oldName)
+	if cfg.CurrentContext == oldName {
+		cfg.CurrentContext = newName
+	}
+	return nil
+}
+
+// CopyContext adds a context named dst with the settings of src. Secret
+// references are copied, so both contexts read the same secrets.
+func (cfg *Config) CopyContext(src, dst string) error {
+	ctx, exists := cfg.Contexts[src]
+	if !exists {
+		return fmt.Errorf("context '%s' not found", src)
+	}
+	if dst == "" {
+		return fmt.Errorf("new context name is required")
+	}
+	if _, exists := cfg.Contexts[dst]; exists {
+		return fmt.Errorf("context '%s' already exists", dst)
+	}
+
+	clone, err := ctx.Clone()
+	if err != nil {
+		return err
+	}
+	cfg.Contexts[dst] = clone
+	return nil
+}
+
+// Clone returns a deep copy of the context
+func (c *Context) Clone() (*Context, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy context: %w", err)
+	}
+	var clone Context
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, fmt.Errorf("failed to copy context: %w", err)
+	}
+	return &clone, nil
+}
+
+// Unset clears a field of the context. "signer" clears every signer setting.
+func (c *Context) Unset(field string) error {
+	switch field {
+	case "avs-address":
+		c.AVSAddress = ""
+	case "operator-set-id":
+		c.OperatorSetID = 0
+	case "release-manager":
+		c.ReleaseManager = ""
+	case "rpc-url":
+		c.RPCURL = ""
+	case "chain-id":
+		c.ChainID = 0
+	case "name":
+		c.Name = ""
+	case "environment-vars":
+		c.EnvironmentVars = nil
+	case "registry-mirrors":
+		c.RegistryMirrors = nil
+	case "policy":
+		c.PolicyPath = ""
+	case "ports":
+		c.Ports = nil
+	case "volumes":
+		c.Volumes = nil
+	case "deployments":
+		c.Deployments = nil
+	case "registry-auth":
+		c.RegistryAuth = nil
+	case "ecdsa-private-key":
+		c.ECDSAPrivateKey = ""
+		c.ECDSAPrivateKeyRef = ""
+	case "keystore-path":
+		// The password is meaningless without the keystore
+		c.KeystorePath = ""
+		c.KeystorePassword = ""
+		c.KeystorePasswordRef = ""
+	case "keystore-password":
+		c.KeystorePassword = ""
+		c.KeystorePasswordRef = ""
+	case "remote-signer":
+		c.RemoteSigner = nil
+	case "kms-signer":
+		c.KMSSigner = nil
+	case "exec-signer":
+		c.ExecSigner = nil
+	case "signer":
+		for _, f := range []string{"ecdsa-private-key", "keystore-path", "remote-signer", "kms-signer", "exec-signer"} {
+			c.Unset(f)
+		}
+	default:
+		return fmt.Errorf("unknown field %q (expected one of: %s)", field, strings.Join(UnsetFields, ", "))
+	}
+	return nil
+}
+
+// RemoveSecretFiles deletes the secret files written for a context under
+// ~/.flickr/secrets, unless a context in cfg still references them. It
+// returns the directory removed, if any.
+func RemoveSecretFiles(cfg *Config, contextName string) (string, error) {
+	dir, err := secretDir(contextName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	// Copied and renamed contexts keep references into the directory
+	prefix := "file:" + dir + string(filepath.Separator)
+	names := make([]string, 0, len(cfg.Contexts))
+	for name := range cfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ctx := cfg.Contexts[name]
+		if ctx == nil {
+			continue
+		}
+		if strings.HasPrefix(ctx.ECDSAPrivateKeyRef, prefix) || strings.HasPrefix(ctx.KeystorePasswordRef, prefix) {
+			return "", nil
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("failed to remove secret files: %w", err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_ContextLifecycle(t *testing.T) {
+	cfg := &Config{
+		CurrentContext: "holesky",
+		Contexts: map[string]*Context{
+			"holesky": {
+				AVSAddress:      "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+				EnvironmentVars: map[string]string{"LOG_LEVEL": "debug"},
+				RemoteSigner:    &RemoteSigner{URL: "https://signer", Address: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+			},
+		},
+	}
+
+	// Copies are independent of the source
+	require.NoError(t, cfg.CopyContext("holesky", "mainnet"))
+	mainnet := cfg.Contexts["mainnet"]
+	mainnet.EnvironmentVars["LOG_LEVEL"] = "info"
+	mainnet.RemoteSigner.URL = "https://mainnet-signer"
+	assert.Equal(t, "debug", cfg.Contexts["holesky"].EnvironmentVars["LOG_LEVEL"])
+	assert.Equal(t, "https://signer", cfg.Contexts["holesky"].RemoteSigner.URL)
+	assert.Equal(t, "holesky", cfg.CurrentContext)
+
+	assert.ErrorContains(t, cfg.CopyContext("holesky", "mainnet"), "already exists")
+	assert.ErrorContains(t, cfg.CopyContext("sepolia", "other"), "not found")
+
+	// Renaming the current context keeps it current
+	require.NoError(t, cfg.RenameContext("holesky", "hoodi"))
+	assert.Equal(t, "hoodi", cfg.CurrentContext)
+	assert.NotContains(t, cfg.Contexts, "holesky")
+	assert.ErrorContains(t, cfg.RenameContext("hoodi", "mainnet"), "already exists")
+
+	// Deleting the current context leaves none selected
+	require.NoError(t, cfg.DeleteContext("hoodi"))
+	assert.Empty(t, cfg.CurrentContext)
+	assert.Len(t, cfg.Contexts, 1)
+	assert.ErrorContains(t, cfg.DeleteContext("hoodi"), "not found")
+}
+
+func TestContext_Unset(t *testing.T) {
+	ctx := &Context{
+		AVSAddress:          "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		ReleaseManager:      "0x59c8d715dca616e032b744a753c017c9f3e16bf4",
+		EnvironmentVars:     map[string]string{"LOG_LEVEL": "debug"},
+		KeystorePath:        "/keys/op.json",
+		KeystorePasswordRef: "env:PASSWORD",
+	}
+
+	require.NoError(t, ctx.Unset("release-manager"))
+	require.NoError(t, ctx.Unset("environment-vars"))
+	assert.Empty(t, ctx.ReleaseManager)
+	assert.Nil(t, ctx.EnvironmentVars)
+	assert.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", ctx.AVSAddress)
+
+	require.NoError(t, ctx.Unset("signer"))
+	assert.Empty(t, ctx.KeystorePath)
+	assert.Empty(t, ctx.KeystorePasswordRef)
+
+	assert.ErrorContains(t, ctx.Unset("avs"), "unknown field")
+
+	// Every advertised field is accepted
+	for _, field := range UnsetFields {
+		assert.NoError(t, (&Context{}).Unset(field), field)
+	}
+}
+
+func TestRemoveSecretFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := &Config{
+		Contexts: map[string]*Context{
+			"dev": {ECDSAPrivateKey: testPrivateKey},
+		},
+	}
+	require.NoError(t, SaveConfig(cfg))
+	keyPath := strings.TrimPrefix(cfg.Contexts["dev"].ECDSAPrivateKeyRef, "file:")
+
+	// A copy still references the secret file
+	require.NoError(t, cfg.CopyContext("dev", "staging"))
+	require.NoError(t, cfg.DeleteContext("dev"))
+	removed, err := RemoveSecretFiles(cfg, "dev")
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.FileExists(t, keyPath)
+
+	require.NoError(t, cfg.DeleteContext("staging"))
+	removed, err = RemoveSecretFiles(cfg, "dev")
+	require.NoError(t, err)
+	assert.NotEmpty(t, removed)
+	_, err = os.Stat(keyPath)
+	assert.True(t, os.IsNotExist(err))
+}
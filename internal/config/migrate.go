@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+// CurrentVersion is the version of the config file format written by this
+// version of flickr. Files without a version are version 0.
+const CurrentVersion = 1
+
+// migration upgrades a config document to version. It works on the decoded
+// JSON so it can rename or restructure fields the Config type no longer has,
+// and returns notes shown to the user.
+type migration struct {
+	version     int
+	description string
+	apply       func(doc map[string]interface{}) ([]string, error)
+}
+
+// migrations are applied in order to files older than their version
+var migrations = []migration{
+	{
+		version:     1,
+		description: "move plaintext secrets to files readable only by the user",
+		apply:       migratePlaintextSecrets,
+	},
+}
+
+// upgrade records a config file upgraded by migrate
+type upgrade struct {
+	from, to int
+	backup   string
+	notes    []string
+}
+
+// migrate upgrades config data written by an older version of flickr. The
+// original file is kept as a backup next to the config. It returns the
+// upgraded data, and nil as the upgrade when the data is current.
+func migrate(configPath string, data []byte) ([]byte, *upgrade, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version > CurrentVersion {
+		return nil, nil, fmt.Errorf("config file version %d is newer than this flickr supports (%d); upgrade flickr", version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return data, nil, nil
+	}
+
+	// The backup may hold plaintext secrets of old configs, so it is as
+	// private as the secret files
+	u := &upgrade{
+		from:   version,
+		to:     CurrentVersion,
+		backup: fmt.Sprintf("%s.v%d.bak", configPath, version),
+	}
+	if err := os.WriteFile(u.backup, data, 0600); err != nil {
+		return nil, nil, fmt.Errorf("failed to back up config file before upgrading it from version %d (%s must be writable once to upgrade it): %w", version, configPath, err)
+	}
+	if err := os.Chmod(u.backup, 0600); err != nil {
+		return nil, nil, fmt.Errorf("failed to back up config file: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		notes, err := m.apply(doc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to upgrade config file to version %d (%s): %w", m.version, m.description, err)
+		}
+		u.notes = append(u.notes, notes...)
+	}
+	doc["version"] = CurrentVersion
+
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to upgrade config file: %w", err)
+	}
+	return upgraded, u, nil
+}
+
+func documentVersion(doc map[string]interface{}) (int, error) {
+	raw, exists := doc["version"]
+	if !exists {
+		return 0, nil
+	}
+	version, ok := raw.(float64)
+	if !ok || version < 0 || version != math.Trunc(version) {
+		return 0, fmt.Errorf("invalid config file version %v", raw)
+	}
+	return int(version), nil
+}
+
+// migratePlaintextSecrets moves the plaintext secrets of version 0 configs
+// into files under ~/.flickr/secrets, replacing them with file: references
+func migratePlaintextSecrets(doc map[string]interface{}) ([]string, error) {
+	contexts, _ := doc["contexts"].(map[string]interface{})
+
+	var notes []string
+	for name, raw := range contexts {
+		ctx, ok := raw.(map[string]interface{})
+		if !ok {
+			// Reported by validation
+			continue
+		}
+
+		for _, secret := range []struct{ field, ref, file string }{
+			{"ecdsaPrivateKey", "ecdsaPrivateKeyRef", "ecdsa-private-key"},
+			{"keystorePassword", "keystorePasswordRef", "keystore-password"},
+		} {
+			value, ok := ctx[secret.field].(string)
+			if !ok {
+				continue
+			}
+			delete(ctx, secret.field)
+			if value == "" {
+				continue
+			}
+
+			path, err := writeSecretFile(name, secret.file, value)
+			if err != nil {
+				return nil, err
+			}
+			ctx[secret.ref] = "file:" + path
+			notes = append(notes, fmt.Sprintf("Moved plaintext secret from config to %s", path))
+		}
+	}
+	sort.Strings(notes)
+	return notes, nil
+}
+
+// decodeConfig strictly decodes and validates a current config file. Contexts
+// are decoded one by one so errors name the offending context.
+func decodeConfig(data []byte) (*Config, error) {
+	// Mirrors Config, with contexts left encoded
+	var doc struct {
+		Version        int                        `json:"version"`
+		CurrentContext string                     `json:"currentContext,omitempty"`
+		Contexts       map[string]json.RawMessage `json:"contexts,omitempty"`
+	}
+	if err := strictUnmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
+		Version:        doc.Version,
+		CurrentContext: doc.CurrentContext,
+		Contexts:       make(map[string]*Context, len(doc.Contexts)),
+	}
+
+	names := make([]string, 0, len(doc.Contexts))
+	for name := range doc.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var ctx *Context
+		if err := strictUnmarshal(doc.Contexts[name], &ctx); err != nil {
+			return nil, fmt.Errorf("context '%s': %w", name, err)
+		}
+		if ctx != nil {
+			if err := ctx.Validate(); err != nil {
+				return nil, fmt.Errorf("context '%s': %w", name, err)
+			}
+		}
+		cfg.Contexts[name] = ctx
+	}
+
+	return cfg, nil
+}
+
+func strictUnmarshal(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
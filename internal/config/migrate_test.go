@@ -0,0 +1,186 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestConfig(t *testing.T, data string) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	configPath, err := GetConfigPath()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configPath, []byte(data), 0600))
+	return configPath
+}
+
+func TestLoadConfig_UpgradesWithBackup(t *testing.T) {
+	legacy := `{"currentContext": "dev", "contexts": {"dev": {"ecdsaPrivateKey": "` + testPrivateKey + `"}}}`
+	configPath := writeTestConfig(t, legacy)
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, cfg.Version)
+	assert.Equal(t, "dev", cfg.CurrentContext)
+
+	// The original is kept, private to the user
+	backup := configPath + ".v0.bak"
+	data, err := os.ReadFile(backup)
+	require.NoError(t, err)
+	assert.Equal(t, legacy, string(data))
+	info, err := os.Stat(backup)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// The upgraded file is current and loads without another upgrade
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, float64(CurrentVersion), doc["version"])
+
+	require.NoError(t, os.Remove(backup))
+	_, err = LoadConfig()
+	require.NoError(t, err)
+	_, err = os.Stat(backup)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLoadConfig_RejectsNewerVersion(t *testing.T) {
+	writeTestConfig(t, `{"version": 99}`)
+
+	_, err := LoadConfig()
+	assert.ErrorContains(t, err, "config file version 99 is newer")
+}
+
+func TestLoadConfig_StrictValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:    "unknown top-level field",
+			config:  `{"version": 1, "curentContext": "dev"}`,
+			wantErr: `unknown field "curentContext"`,
+		},
+		{
+			name:    "unknown context field",
+			config:  `{"version": 1, "contexts": {"dev": {}, "prod": {"avs": "0x1"}}}`,
+			wantErr: `context 'prod': json: unknown field "avs"`,
+		},
+		{
+			name:    "malformed field",
+			config:  `{"version": 1, "contexts": {"dev": {"operatorSetId": "one"}}}`,
+			wantErr: "context 'dev': json: cannot unmarshal string",
+		},
+		{
+			name:    "invalid address",
+			config:  `{"version": 1, "contexts": {"dev": {"avsAddress": "0x123"}}}`,
+			wantErr: `context 'dev': avsAddress: invalid address "0x123"`,
+		},
+		{
+			name:    "plaintext secret in current version",
+			config:  `{"version": 1, "contexts": {"dev": {"ecdsaPrivateKey": "0x01"}}}`,
+			wantErr: "context 'dev': plaintext secrets are not allowed",
+		},
+		{
+			name:    "invalid version",
+			config:  `{"version": "1"}`,
+			wantErr: "invalid config file version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := writeTestConfig(t, tt.config)
+
+			_, err := LoadConfig()
+			assert.ErrorContains(t, err, tt.wantErr)
+
+			// Invalid files are left as they are
+			data, err := os.ReadFile(configPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.config, string(data))
+		})
+	}
+}
+
+func TestContext_Validate(t *testing.T) {
+	address := "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+
+	tests := []struct {
+		name    string
+		ctx     Context
+		wantErr string
+	}{
+		{
+			name: "valid",
+			ctx: Context{
+				AVSAddress:   address,
+				RPCURL:       "https://rpc.example.com",
+				Volumes:      []string{"data:/data"},
+				RegistryAuth: map[string]*RegistryCredential{"ghcr.io": {Helper: "pass"}},
+				RemoteSigner: &RemoteSigner{URL: "https://signer", Address: address},
+			},
+		},
+		{
+			name:    "rpc url",
+			ctx:     Context{RPCURL: "localhost"},
+			wantErr: "rpcUrl: invalid URL",
+		},
+		{
+			name:    "volume",
+			ctx:     Context{Volumes: []string{"/data"}},
+			wantErr: "volumes: invalid volume",
+		},
+		{
+			name:    "registry credential",
+			ctx:     Context{RegistryAuth: map[string]*RegistryCredential{"ghcr.io": {Username: "me"}}},
+			wantErr: "registryAuth.ghcr.io: one of helper",
+		},
+		{
+			name:    "multiple signers",
+			ctx:     Context{ECDSAPrivateKeyRef: "env:KEY", ExecSigner: &ExecSigner{Command: "sign"}},
+			wantErr: "only one signer may be configured, found ecdsaPrivateKeyRef, execSigner",
+		},
+		{
+			name:    "kms provider",
+			ctx:     Context{KMSSigner: &KMSSigner{Provider: "azure", KeyID: "key"}},
+			wantErr: "kmsSigner.provider must be aws or gcp",
+		},
+		{
+			name:    "keystore password without keystore",
+			ctx:     Context{KeystorePasswordRef: "env:PASSWORD"},
+			wantErr: "keystorePasswordRef requires keystorePath",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ctx.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSaveConfig_RejectsInvalidContext(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	err := SaveConfig(&Config{Contexts: map[string]*Context{"dev": {AVSAddress: "nope"}}})
+	assert.ErrorContains(t, err, "context 'dev': avsAddress")
+
+	configPath, err := GetConfigPath()
+	require.NoError(t, err)
+	_, err = os.Stat(configPath)
+	assert.True(t, os.IsNotExist(err))
+}
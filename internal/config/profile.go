@@ -0,0 +1,196 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileKind identifies context profile documents
+const ProfileKind = "flickr/context-profile"
+
+// ProfileVersion is the current version of the profile format
+const ProfileVersion = 1
+
+// Profile is a shareable context, e.g. published by an AVS team so operators
+// can create a ready-made context with 'flickr context import'. Its context
+// uses the same fields as the config file.
+type Profile struct {
+	Kind        string   `json:"kind"`
+	Version     int      `json:"version"`
+	Name        string   `json:"name,omitempty"`        // Suggested context name
+	Description string   `json:"description,omitempty"` // Shown on import
+	Context     *Context `json:"context"`
+}
+
+// LocalFields are the profile settings specific to one machine or operator:
+// the signer, registry credential references, the policy path and volumes
+// mounting host paths. They are left out of profiles unless explicitly
+// included.
+var LocalFields = []string{"signer", "registry-auth", "policy", "host path volumes"}
+
+// localUnsetFields are the LocalFields cleared with Unset; host path volumes
+// are filtered out of the volume lists instead
+var localUnsetFields = []string{"signer", "registry-auth", "policy"}
+
+// NewProfile creates a profile from a context. Plaintext secrets are never
+// included; machine-specific settings are only included with includeLocal.
+func NewProfile(name string, ctx *Context, includeLocal bool) (*Profile, error) {
+	clone, err := ctx.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	clone.ECDSAPrivateKey = ""
+	clone.KeystorePassword = ""
+	if !includeLocal {
+		clone.stripLocal()
+	}
+
+	return &Profile{
+		Kind:    ProfileKind,
+		Version: ProfileVersion,
+		Name:    name,
+		Context: clone,
+	}, nil
+}
+
+// stripLocal clears the settings listed in LocalFields and reports whether
+// any were set
+func (c *Context) stripLocal() bool {
+	set := c.ECDSAPrivateKeyRef != "" || c.KeystorePath != "" || c.KeystorePasswordRef != "" ||
+		c.RemoteSigner != nil || c.KMSSigner != nil || c.ExecSigner != nil ||
+		len(c.RegistryAuth) > 0 || c.PolicyPath != ""
+	for _, field := range localUnsetFields {
+		c.Unset(field)
+	}
+
+	var stripped bool
+	c.Volumes, stripped = namedVolumes(c.Volumes)
+	set = set || stripped
+	for _, d := range c.Deployments {
+		if d != nil {
+			d.Volumes, stripped = namedVolumes(d.Volumes)
+			set = set || stripped
+		}
+	}
+	return set
+}
+
+// namedVolumes returns the named volumes of volumes, and whether any host
+// path volumes were dropped
+func namedVolumes(volumes []string) ([]string, bool) {
+	var named []string
+	for _, volume := range volumes {
+		if IsNamedVolume(volume) {
+			named = append(named, volume)
+		}
+	}
+	return named, len(named) != len(volumes)
+}
+
+// StripLocal removes machine-specific settings from the profile and reports
+// whether any were set
+func (p *Profile) StripLocal() bool {
+	return p.Context.stripLocal()
+}
+
+// MarshalProfile encodes a profile as "yaml" or "json"
+func MarshalProfile(p *Profile, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal profile: %w", err)
+	}
+
+	switch format {
+	case "json":
+		return append(data, '\n'), nil
+	case "yaml":
+		// Convert through a YAML node so the field names and order of the
+		// JSON encoding are kept
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, fmt.Errorf("failed to marshal profile: %w", err)
+		}
+		blockStyle(&node)
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return nil, fmt.Errorf("failed to marshal profile: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("failed to marshal profile: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown profile format %q (expected yaml or json)", format)
+	}
+}
+
+// blockStyle clears the flow and quoting styles the JSON input gave a node
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// ParseProfile decodes and validates a YAML or JSON profile
+func ParseProfile(data []byte) (*Profile, error) {
+	var p Profile
+	if err := decodeYAMLStrict(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse profile: %w", err)
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+	return &p, nil
+}
+
+func (p *Profile) validate() error {
+	if p.Kind != ProfileKind {
+		return fmt.Errorf("kind must be %q, got %q", ProfileKind, p.Kind)
+	}
+	if p.Version < 1 || p.Version > ProfileVersion {
+		return fmt.Errorf("unsupported version %d (this flickr supports up to %d)", p.Version, ProfileVersion)
+	}
+	if p.Context == nil {
+		return fmt.Errorf("context is required")
+	}
+
+	ctx := p.Context
+	if ctx.ECDSAPrivateKey != "" || ctx.KeystorePassword != "" {
+		return fmt.Errorf("profiles must not contain plaintext secrets")
+	}
+	if ctx.AVSAddress != "" && !common.IsHexAddress(ctx.AVSAddress) {
+		return fmt.Errorf("invalid AVS address %q", ctx.AVSAddress)
+	}
+	if ctx.ReleaseManager != "" && !common.IsHexAddress(ctx.ReleaseManager) {
+		return fmt.Errorf("invalid release manager address %q", ctx.ReleaseManager)
+	}
+	return nil
+}
+
+// decodeYAMLStrict decodes YAML or JSON into v using its JSON field names,
+// rejecting unknown fields
+func decodeYAMLStrict(data []byte, v interface{}) error {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return strictUnmarshal(jsonData, v)
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile_RoundTrip(t *testing.T) {
+	ctx := &Context{
+		AVSAddress:         "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		OperatorSetID:      1,
+		ReleaseManager:     "0x59c8d715dca616e032b744a753c017c9f3e16bf4",
+		RPCURL:             "https://rpc.example.com",
+		EnvironmentVars:    map[string]string{"LOG_LEVEL": "info"},
+		PolicyPath:         "/etc/flickr/policy.yaml",
+		ECDSAPrivateKeyRef: "env:PRIVATE_KEY",
+		ECDSAPrivateKey:    "0xdeadbeef",
+		ExecSigner:         &ExecSigner{Command: "/usr/local/bin/sign"},
+		RegistryAuth:       map[string]*RegistryCredential{"ghcr.io": {Helper: "pass"}},
+	}
+
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			profile, err := NewProfile("holesky", ctx, false)
+			require.NoError(t, err)
+
+			data, err := MarshalProfile(profile, format)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), "deadbeef")
+			assert.NotContains(t, string(data), "PRIVATE_KEY")
+
+			parsed, err := ParseProfile(data)
+			require.NoError(t, err)
+			assert.Equal(t, "holesky", parsed.Name)
+			assert.Equal(t, &Context{
+				AVSAddress:      ctx.AVSAddress,
+				OperatorSetID:   1,
+				ReleaseManager:  ctx.ReleaseManager,
+				RPCURL:          ctx.RPCURL,
+				EnvironmentVars: map[string]string{"LOG_LEVEL": "info"},
+			}, parsed.Context)
+			assert.False(t, parsed.StripLocal())
+		})
+	}
+
+	// Local settings are kept on request, secret values never are
+	profile, err := NewProfile("holesky", ctx, true)
+	require.NoError(t, err)
+	data, err := MarshalProfile(profile, "yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "deadbeef")
+
+	parsed, err := ParseProfile(data)
+	require.NoError(t, err)
+	assert.Equal(t, "env:PRIVATE_KEY", parsed.Context.ECDSAPrivateKeyRef)
+	assert.Equal(t, "/usr/local/bin/sign", parsed.Context.ExecSigner.Command)
+	assert.True(t, parsed.StripLocal())
+	assert.Nil(t, parsed.Context.ExecSigner)
+	assert.Empty(t, parsed.Context.ECDSAPrivateKeyRef)
+	assert.Empty(t, parsed.Context.PolicyPath)
+	assert.Nil(t, parsed.Context.RegistryAuth)
+	assert.NotNil(t, ctx.ExecSigner)
+}
+
+func TestProfile_StripLocalHostVolumes(t *testing.T) {
+	data := []byte(`kind: flickr/context-profile
+version: 1
+context:
+  volumes:
+    - avs-data:/data
+    - /var/run/docker.sock:/var/run/docker.sock
+  deployments:
+    eu:
+      operatorSetId: 2
+      volumes:
+        - /:/host
+        - eu-data:/data
+`)
+
+	profile, err := ParseProfile(data)
+	require.NoError(t, err)
+	assert.True(t, profile.StripLocal())
+	assert.Equal(t, []string{"avs-data:/data"}, profile.Context.Volumes)
+	assert.Equal(t, []string{"eu-data:/data"}, profile.Context.Deployments["eu"].Volumes)
+
+	// Named volumes alone are not local
+	assert.False(t, profile.StripLocal())
+}
+
+func TestParseProfile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		wantErr string
+	}{
+		{
+			name:    "wrong kind",
+			profile: "kind: policy\nversion: 1\ncontext: {}\n",
+			wantErr: "kind must be",
+		},
+		{
+			name:    "newer version",
+			profile: "kind: flickr/context-profile\nversion: 2\ncontext: {}\n",
+			wantErr: "unsupported version 2",
+		},
+		{
+			name:    "missing context",
+			profile: "kind: flickr/context-profile\nversion: 1\n",
+			wantErr: "context is required",
+		},
+		{
+			name:    "unknown field",
+			profile: "kind: flickr/context-profile\nversion: 1\ncontext:\n  avs: \"0x70997970C51812dc3A010C7d01b50e0d17dc79C8\"\n",
+			wantErr: "unknown field",
+		},
+		{
+			name:    "plaintext secret",
+			profile: `{"kind": "flickr/context-profile", "version": 1, "context": {"ecdsaPrivateKey": "0x01"}}`,
+			wantErr: "plaintext secrets",
+		},
+		{
+			name:    "invalid address",
+			profile: "kind: flickr/context-profile\nversion: 1\ncontext:\n  avsAddress: nope\n",
+			wantErr: "invalid AVS address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProfile([]byte(tt.profile))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
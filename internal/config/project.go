@@ -0,0 +1,367 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProjectFileName is the project config file looked up in the working directory
+const ProjectFileName = ".flickr.yaml"
+
+// projectFields maps the context fields a project config may set to their
+// names in ToMap. Signers and registry credentials are left out: a project
+// file is typically committed with the code, and must not make flickr run
+// programs or read secrets on the machines that check it out. The chain ID,
+// ReleaseManager and supply-chain policy are left out too: they decide what
+// the operator trusts, so a checked out repository cannot replace them.
+// Volumes, including those of deployments, must be named volumes, and the AVS
+// address and RPC URL can only be changed for a context pinned to a chain.
+var projectFields = map[string]string{
+	"avsAddress":      "avs-address",
+	"operatorSetId":   "operator-set-id",
+	"rpcUrl":          "rpc-url",
+	"name":            "name",
+	"environmentVars": "environment-vars",
+	"registryMirrors": "registry-mirrors",
+	"ports":           "ports",
+	"volumes":         "volumes",
+	"deployments":     "deployments",
+}
+
+// noticedFields are the project config fields that change what is run or
+// where it comes from. Overriding them is reported on every command, so a
+// project file cannot change them unnoticed.
+var noticedFields = []string{"avsAddress", "rpcUrl", "registryMirrors", "volumes", "deployments"}
+
+// Project is a project-local config file layered over the user's config:
+//
+//	currentContext: holesky  # context to use in this project
+//	context:                 # settings overriding those of the context
+//	  operatorSetId: 2
+//	  environmentVars:
+//	    LOG_LEVEL: debug
+type Project struct {
+	Path           string                 `json:"-"`
+	CurrentContext string                 `json:"currentContext,omitempty"`
+	Context        map[string]interface{} `json:"context,omitempty"`
+}
+
+// FindProject loads the project config file in dir, or returns nil if there
+// is none
+func FindProject(dir string) (*Project, error) {
+	path := filepath.Join(dir, ProjectFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read project config: %w", err)
+	}
+
+	project, err := ParseProject(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project config %s: %w", path, err)
+	}
+	project.Path = path
+	return project, nil
+}
+
+// ParseProject decodes and validates a project config file
+func ParseProject(data []byte) (*Project, error) {
+	var project Project
+	if err := decodeYAMLStrict(data, &project); err != nil {
+		return nil, err
+	}
+
+	for field := range project.Context {
+		if _, ok := projectFields[field]; !ok {
+			return nil, fmt.Errorf("context.%s cannot be set in a project config (allowed: %s)", field, strings.Join(sortedKeys(projectFields), ", "))
+		}
+	}
+
+	// Check the overrides decode as context fields
+	overrides, err := (&Context{}).overlay(project.Context)
+	if err != nil {
+		return nil, err
+	}
+	if err := overrides.checkNamedVolumes(); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+// chainFields returns the settings of the project that select the chain or
+// AVS, which are only accepted for contexts pinned to a chain
+func (p *Project) chainFields() []string {
+	var fields []string
+	for _, field := range []string{"avsAddress", "rpcUrl"} {
+		if _, set := p.Context[field]; set {
+			fields = append(fields, field)
+		}
+	}
+	deployments, _ := p.Context["deployments"].(map[string]interface{})
+	for _, name := range sortedKeys(deployments) {
+		if d, ok := deployments[name].(map[string]interface{}); ok {
+			if _, set := d["avsAddress"]; set {
+				fields = append(fields, "deployments."+name+".avsAddress")
+			}
+		}
+	}
+	return fields
+}
+
+// overlay returns a copy of the context with fields overridden. Environment
+// variables and registry mirrors are merged key by key.
+func (c *Context) overlay(fields map[string]interface{}) (*Context, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	for field, value := range fields {
+		if override, ok := value.(map[string]interface{}); ok {
+			if base, ok := doc[field].(map[string]interface{}); ok {
+				for k, v := range override {
+					base[k] = v
+				}
+				continue
+			}
+		}
+		doc[field] = value
+	}
+
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var result Context
+	if err := strictUnmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Effective is the context commands use: the current context of the user's
+// config with the project config layered over it
+type Effective struct {
+	Name    string
+	Context *Context // nil when no context is selected and no project config applies
+
+	// Origins records where each value came from, keyed like ToMap, with
+	// environment variables as environment-vars.KEY
+	Origins map[string]string
+
+	// ProjectPath is the project config layered over the context, and
+	// ProjectOverrides the settings it changes that are reported to the user
+	ProjectPath      string
+	ProjectOverrides []string
+}
+
+// Resolve returns the effective context of cfg, loaded from configPath, with
+// project and then the FLICKR_* variables of environ layered over it. project
+// may be nil.
+func Resolve(cfg *Config, configPath string, project *Project, environ []string) (*Effective, error) {
+	e := &Effective{Origins: make(map[string]string)}
+	var nameOrigin string
+	e.Name, nameOrigin = selectContext(cfg, configPath, project, environ)
+
+	// Without a context the project settings apply on their own
+	var base *Context
+	if e.Name != "" {
+		ctx, exists := cfg.Contexts[e.Name]
+		if !exists {
+			return nil, fmt.Errorf("context '%s' (set in %s) not found", e.Name, nameOrigin)
+		}
+		base = ctx
+		if base == nil {
+			base = &Context{}
+		}
+		e.Origins["current-context"] = nameOrigin
+		e.setOrigins(base, fmt.Sprintf("context '%s' in %s", e.Name, configPath))
+	}
+
+	if project != nil && len(project.Context) > 0 {
+		if base == nil {
+			base = &Context{}
+		}
+		// Without a chain pin, nothing checks that a project's RPC URL or AVS
+		// are on the chain the operator set up
+		if fields := project.chainFields(); len(fields) > 0 && base.ChainID == 0 {
+			return nil, fmt.Errorf("%s sets %s, but the context is not pinned to a chain; pin it with 'flickr context check' or 'flickr context set --chain-id'", project.Path, strings.Join(fields, ", "))
+		}
+		ctx, err := base.overlay(project.Context)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project config %s: %w", project.Path, err)
+		}
+		base = ctx
+
+		e.ProjectPath = project.Path
+		for _, field := range noticedFields {
+			if _, set := project.Context[field]; set {
+				e.ProjectOverrides = append(e.ProjectOverrides, projectFields[field])
+			}
+		}
+
+		for field, value := range project.Context {
+			key := projectFields[field]
+			if values, ok := value.(map[string]interface{}); ok && key == "environment-vars" {
+				for k := range values {
+					e.Origins[key+"."+k] = project.Path
+				}
+				continue
+			}
+			e.Origins[key] = project.Path
+		}
+	}
+
+	// Environment variables take precedence over both files
+	fields, envOrigins, err := envOverrides(environ)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		if base == nil {
+			base = &Context{}
+		}
+		ctx, err := base.overlay(fields)
+		if err != nil {
+			return nil, fmt.Errorf("invalid environment: %w", err)
+		}
+		base = ctx
+
+		// Signer settings replaced by the environment no longer apply
+		if _, set := fields["ecdsaPrivateKeyRef"]; set {
+			for key := range e.Origins {
+				if isSignerKey(key) {
+					delete(e.Origins, key)
+				}
+			}
+		}
+		for key, origin := range envOrigins {
+			e.Origins[key] = origin
+		}
+	}
+
+	e.Context = base
+	return e, nil
+}
+
+// selectContext returns the name of the context to use and where it was
+// selected: FLICKR_CONTEXT, then the project's currentContext, then the
+// current context of cfg
+func selectContext(cfg *Config, configPath string, project *Project, environ []string) (string, string) {
+	name, origin := cfg.CurrentContext, configPath
+	if project != nil && project.CurrentContext != "" {
+		name, origin = project.CurrentContext, project.Path
+	}
+	for _, kv := range environ {
+		if n, ok := strings.CutPrefix(kv, EnvContext+"="); ok && n != "" {
+			name, origin = n, "env "+EnvContext
+		}
+	}
+	return name, origin
+}
+
+// Current returns the name and stored settings of the context commands use,
+// selected like LoadEffective selects it. Commands that change a context
+// change this one, never the project or environment settings layered over it.
+func (cfg *Config) Current() (string, *Context, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	project, err := FindProject(wd)
+	if err != nil {
+		return "", nil, err
+	}
+
+	configPath, err := resolveConfigPath()
+	if err != nil {
+		return "", nil, err
+	}
+
+	name, origin := selectContext(cfg, configPath, project, os.Environ())
+	if name == "" {
+		return "", nil, fmt.Errorf("no current context set")
+	}
+	ctx, exists := cfg.Contexts[name]
+	if !exists {
+		return "", nil, fmt.Errorf("context '%s' (set in %s) not found", name, origin)
+	}
+	if ctx == nil {
+		ctx = &Context{}
+		cfg.Contexts[name] = ctx
+	}
+	return name, ctx, nil
+}
+
+func isSignerKey(key string) bool {
+	switch key {
+	case "ecdsa-private-key", "keystore-path", "keystore-password", "remote-signer", "kms-signer", "exec-signer":
+		return true
+	}
+	return false
+}
+
+func (e *Effective) setOrigins(ctx *Context, origin string) {
+	for key := range ctx.ToMap() {
+		if key == "environment-vars" {
+			continue
+		}
+		e.Origins[key] = origin
+	}
+	for k := range ctx.EnvironmentVars {
+		e.Origins["environment-vars."+k] = origin
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LoadEffective loads the user's config and the effective context, with the
+// project config of the working directory layered over it. If the config
+// loads but the project config or environment is invalid, the config is
+// returned with the error.
+func LoadEffective() (*Config, *Effective, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	configPath, err := resolveConfigPath()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return cfg, nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	project, err := FindProject(wd)
+	if err != nil {
+		return cfg, nil, err
+	}
+
+	effective, err := Resolve(cfg, configPath, project, os.Environ())
+	if err != nil {
+		return cfg, nil, err
+	}
+	return cfg, effective, nil
+}
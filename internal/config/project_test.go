@@ -0,0 +1,306 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetConfigPath(t *testing.T) {
+	home := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvConfig, "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	path, err := GetConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".flickr", "config.json"), path)
+
+	// XDG is used unless a config already exists in ~/.flickr
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	path, err = GetConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(xdg, "flickr", "config.json"), path)
+
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".flickr", "config.json"), []byte(`{}`), 0600))
+	path, err = GetConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".flickr", "config.json"), path)
+
+	// FLICKR_CONFIG, then --config, take precedence
+	envPath := filepath.Join(t.TempDir(), "env", "config.json")
+	t.Setenv(EnvConfig, envPath)
+	path, err = GetConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, envPath, path)
+	assert.DirExists(t, filepath.Dir(envPath))
+
+	flagPath := filepath.Join(t.TempDir(), "flag.json")
+	SetConfigPath(flagPath)
+	t.Cleanup(func() { SetConfigPath("") })
+	path, err = GetConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, flagPath, path)
+}
+
+func TestParseProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			project: "currentContext: holesky\ncontext:\n  operatorSetId: 2\n  environmentVars:\n    LOG_LEVEL: debug\n",
+		},
+		{
+			name:    "empty",
+			project: "",
+		},
+		{
+			name:    "unknown field",
+			project: "curentContext: holesky\n",
+			wantErr: `unknown field "curentContext"`,
+		},
+		{
+			name:    "context not a mapping",
+			project: "context: holesky\n",
+			wantErr: "cannot unmarshal",
+		},
+		{
+			name:    "signer",
+			project: "context:\n  execSigner:\n    command: /tmp/sign\n",
+			wantErr: "context.execSigner cannot be set in a project config",
+		},
+		{
+			name:    "policy",
+			project: "context:\n  policyPath: policy.yaml\n",
+			wantErr: "context.policyPath cannot be set in a project config",
+		},
+		{
+			name:    "release manager",
+			project: "context:\n  releaseManager: \"0x70997970C51812dc3A010C7d01b50e0d17dc79C8\"\n",
+			wantErr: "context.releaseManager cannot be set in a project config",
+		},
+		{
+			name:    "chain ID",
+			project: "context:\n  rpcUrl: https://rpc.example.com\n  chainId: 1\n",
+			wantErr: "context.chainId cannot be set in a project config",
+		},
+		{
+			name:    "named volume",
+			project: "context:\n  volumes:\n    - avs-data:/data\n",
+		},
+		{
+			name:    "host path volume",
+			project: "context:\n  volumes:\n    - /:/host\n",
+			wantErr: `volumes: invalid volume "/:/host": only named volumes are allowed`,
+		},
+		{
+			name:    "docker socket",
+			project: "context:\n  volumes:\n    - /var/run/docker.sock:/var/run/docker.sock\n",
+			wantErr: "only named volumes are allowed",
+		},
+		{
+			name:    "deployment host path volume",
+			project: "context:\n  deployments:\n    eu:\n      operatorSetId: 2\n      volumes:\n        - ./data:/data\n",
+			wantErr: `deployments.eu.volumes: invalid volume "./data:/data"`,
+		},
+		{
+			name:    "malformed value",
+			project: "context:\n  avsAddress: nope\n",
+			wantErr: "avsAddress: invalid address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProject([]byte(tt.project))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cfg := &Config{
+		CurrentContext: "mainnet",
+		Contexts: map[string]*Context{
+			"mainnet": {OperatorSetID: 1},
+			"holesky": {
+				AVSAddress:      "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+				OperatorSetID:   1,
+				EnvironmentVars: map[string]string{"LOG_LEVEL": "info", "NETWORK": "holesky"},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	project := "currentContext: holesky\ncontext:\n  operatorSetId: 2\n  environmentVars:\n    LOG_LEVEL: debug\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ProjectFileName), []byte(project), 0644))
+
+	p, err := FindProject(dir)
+	require.NoError(t, err)
+
+	e, err := Resolve(cfg, "/home/op/.flickr/config.json", p, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "holesky", e.Name)
+	assert.Equal(t, uint32(2), e.Context.OperatorSetID)
+	assert.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", e.Context.AVSAddress)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "NETWORK": "holesky"}, e.Context.EnvironmentVars)
+	assert.Empty(t, e.ProjectOverrides)
+
+	projectPath := filepath.Join(dir, ProjectFileName)
+	fromConfig := "context 'holesky' in /home/op/.flickr/config.json"
+	assert.Equal(t, map[string]string{
+		"current-context":            projectPath,
+		"avs-address":                fromConfig,
+		"operator-set-id":            projectPath,
+		"environment-vars.LOG_LEVEL": projectPath,
+		"environment-vars.NETWORK":   fromConfig,
+	}, e.Origins)
+
+	// The user's config is unchanged
+	assert.Equal(t, uint32(1), cfg.Contexts["holesky"].OperatorSetID)
+	assert.Equal(t, "info", cfg.Contexts["holesky"].EnvironmentVars["LOG_LEVEL"])
+
+	// Without a project the current context is used as is
+	e, err = Resolve(cfg, "/home/op/.flickr/config.json", nil, nil)
+	require.NoError(t, err)
+	assert.Same(t, cfg.Contexts["mainnet"], e.Context)
+
+	// Without a project file there is nothing to layer
+	p, err = FindProject(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	_, err = Resolve(cfg, "/home/op/.flickr/config.json", &Project{Path: projectPath, CurrentContext: "sepolia"}, nil)
+	assert.ErrorContains(t, err, "context 'sepolia' (set in "+projectPath+") not found")
+}
+
+func TestResolve_ProjectKeepsChainPin(t *testing.T) {
+	cfg := &Config{
+		CurrentContext: "sepolia",
+		Contexts: map[string]*Context{
+			"sepolia": {RPCURL: "https://sepolia.example.com", ChainID: 11155111},
+		},
+	}
+	project := &Project{
+		Path:    "/src/avs/.flickr.yaml",
+		Context: map[string]interface{}{"rpcUrl": "https://rpc.example.com"},
+	}
+
+	// The project's RPC URL must still serve the context's chain
+	e, err := Resolve(cfg, "/home/op/.flickr/config.json", project, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://rpc.example.com", e.Context.RPCURL)
+	assert.Equal(t, uint64(11155111), e.Context.ChainID)
+	assert.Equal(t, "context 'sepolia' in /home/op/.flickr/config.json", e.Origins["chain-id"])
+
+	// Changing the RPC URL is reported
+	assert.Equal(t, "/src/avs/.flickr.yaml", e.ProjectPath)
+	assert.Equal(t, []string{"rpc-url"}, e.ProjectOverrides)
+}
+
+func TestResolve_ProjectRequiresChainPin(t *testing.T) {
+	cfg := &Config{
+		CurrentContext: "sepolia",
+		Contexts: map[string]*Context{
+			"sepolia": {RPCURL: "https://sepolia.example.com"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		context map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "RPC URL",
+			context: map[string]interface{}{"rpcUrl": "https://rpc.example.com"},
+			wantErr: "sets rpcUrl, but the context is not pinned to a chain",
+		},
+		{
+			name:    "AVS address",
+			context: map[string]interface{}{"avsAddress": "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+			wantErr: "sets avsAddress, but the context is not pinned to a chain",
+		},
+		{
+			name: "deployment AVS address",
+			context: map[string]interface{}{"deployments": map[string]interface{}{
+				"eu": map[string]interface{}{"avsAddress": "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+			}},
+			wantErr: "sets deployments.eu.avsAddress, but the context is not pinned to a chain",
+		},
+		{
+			name:    "other settings",
+			context: map[string]interface{}{"operatorSetId": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := &Project{Path: "/src/avs/.flickr.yaml", Context: tt.context}
+			_, err := Resolve(cfg, "/home/op/.flickr/config.json", project, nil)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	// Without a context nothing is pinned either
+	project := &Project{Path: "/src/avs/.flickr.yaml", Context: map[string]interface{}{"rpcUrl": "https://rpc.example.com"}}
+	_, err := Resolve(&Config{Contexts: map[string]*Context{}}, "/home/op/.flickr/config.json", project, nil)
+	assert.ErrorContains(t, err, "not pinned to a chain")
+}
+
+func TestConfig_Current(t *testing.T) {
+	cfg := &Config{
+		CurrentContext: "mainnet",
+		Contexts: map[string]*Context{
+			"mainnet": {OperatorSetID: 1},
+			"holesky": {OperatorSetID: 2},
+			"sepolia": {OperatorSetID: 3},
+		},
+	}
+	SetConfigPath(filepath.Join(t.TempDir(), "config.json"))
+	t.Cleanup(func() { SetConfigPath("") })
+	t.Setenv(EnvContext, "")
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	name, ctx, err := cfg.Current()
+	require.NoError(t, err)
+	assert.Equal(t, "mainnet", name)
+	assert.Same(t, cfg.Contexts["mainnet"], ctx)
+
+	// The project's current context, then FLICKR_CONTEXT, are used like
+	// commands reading the effective context use them
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ProjectFileName), []byte("currentContext: holesky\n"), 0644))
+	name, ctx, err = cfg.Current()
+	require.NoError(t, err)
+	assert.Equal(t, "holesky", name)
+	assert.Same(t, cfg.Contexts["holesky"], ctx)
+
+	t.Setenv(EnvContext, "sepolia")
+	name, _, err = cfg.Current()
+	require.NoError(t, err)
+	assert.Equal(t, "sepolia", name)
+
+	t.Setenv(EnvContext, "goerli")
+	_, _, err = cfg.Current()
+	assert.ErrorContains(t, err, "context 'goerli' (set in env FLICKR_CONTEXT) not found")
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Flags reads command-line flags; *cli.Context implements it
+type Flags interface {
+	IsSet(name string) bool
+	String(name string) string
+	Uint64(name string) uint64
+}
+
+// Flags shared by the commands acting on an operator set
+const (
+	FlagAVS            = "avs"
+	FlagOperatorSet    = "operator-set"
+	FlagRPCURL         = "rpc-url"
+	FlagReleaseManager = "release-manager"
+)
+
+// Target is the AVS operator set and chain a command acts on
+type Target struct {
+	AVS            common.Address
+	OperatorSetID  uint32
+	RPCURL         string
+	ReleaseManager string // Empty for the chain's default ReleaseManager
+	ChainID        uint64 // Chain the RPC URL must serve; 0 if not pinned
+}
+
+// ResolveTarget resolves the target of a command with the precedence flag,
+// FLICKR_* environment variable, context, default. ctx is the effective
+// context, which already has the environment layered over it. Flags a
+// command doesn't define are skipped.
+func ResolveTarget(flags Flags, ctx *Context) (*Target, error) {
+	rpcURL, err := ResolveRPCURL(flags, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = &Context{}
+	}
+
+	avsAddress := flagString(flags, FlagAVS, ctx.AVSAddress)
+	if avsAddress == "" {
+		return nil, fmt.Errorf("--%s is required (or set %s, or set in context with 'flickr context set --avs-address')", FlagAVS, EnvAVSAddress)
+	}
+	if !common.IsHexAddress(avsAddress) {
+		return nil, fmt.Errorf("invalid AVS address: %s", avsAddress)
+	}
+
+	operatorSetID := ctx.OperatorSetID
+	if flags != nil && flags.IsSet(FlagOperatorSet) {
+		id := flags.Uint64(FlagOperatorSet)
+		if id > uint64(^uint32(0)) {
+			return nil, fmt.Errorf("invalid operator set ID: %d", id)
+		}
+		operatorSetID = uint32(id)
+	}
+
+	releaseManager := flagString(flags, FlagReleaseManager, ctx.ReleaseManager)
+	if releaseManager != "" && !common.IsHexAddress(releaseManager) {
+		return nil, fmt.Errorf("invalid ReleaseManager address: %s", releaseManager)
+	}
+
+	return &Target{
+		AVS:            common.HexToAddress(avsAddress),
+		OperatorSetID:  operatorSetID,
+		RPCURL:         rpcURL,
+		ReleaseManager: releaseManager,
+		ChainID:        ctx.ChainID,
+	}, nil
+}
+
+// ResolveRPCURL resolves the RPC URL with the precedence flag, FLICKR_RPC_URL,
+// context
+func ResolveRPCURL(flags Flags, ctx *Context) (string, error) {
+	var fallback string
+	if ctx != nil {
+		fallback = ctx.RPCURL
+	}
+	rpcURL := flagString(flags, FlagRPCURL, fallback)
+	if rpcURL == "" {
+		return "", fmt.Errorf("--%s is required (or set %s, or set in context with 'flickr context set --rpc-url')", FlagRPCURL, EnvRPCURL)
+	}
+	return rpcURL, nil
+}
+
+func flagString(flags Flags, name, fallback string) string {
+	if flags != nil {
+		if value := flags.String(name); value != "" {
+			return value
+		}
+	}
+	return fallback
+}
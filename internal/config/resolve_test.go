@@ -0,0 +1,156 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFlags implements Flags over a map of set flags
+type testFlags map[string]interface{}
+
+func (f testFlags) IsSet(name string) bool { _, ok := f[name]; return ok }
+
+func (f testFlags) String(name string) string {
+	s, _ := f[name].(string)
+	return s
+}
+
+func (f testFlags) Uint64(name string) uint64 {
+	n, _ := f[name].(uint64)
+	return n
+}
+
+const (
+	testAVS = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+	testRM  = "0x59c8d715dca616e032b744a753c017c9f3e16bf4"
+)
+
+func TestResolveTarget(t *testing.T) {
+	ctx := &Context{
+		AVSAddress:    testAVS,
+		OperatorSetID: 3,
+		RPCURL:        "https://context.example.com",
+		ChainID:       11155111,
+	}
+
+	tests := []struct {
+		name    string
+		flags   testFlags
+		environ []string
+		ctx     *Context
+		want    *Target
+		wantErr string
+	}{
+		{
+			name: "context",
+			ctx:  ctx,
+			want: &Target{AVS: common.HexToAddress(testAVS), OperatorSetID: 3, RPCURL: "https://context.example.com", ChainID: 11155111},
+		},
+		{
+			name:    "environment over context",
+			environ: []string{EnvOperatorSetID + "=4", EnvRPCURL + "=https://env.example.com", EnvReleaseManager + "=" + testRM, EnvChainID + "=17000"},
+			ctx:     ctx,
+			want:    &Target{AVS: common.HexToAddress(testAVS), OperatorSetID: 4, RPCURL: "https://env.example.com", ReleaseManager: testRM, ChainID: 17000},
+		},
+		{
+			name:    "flags over environment",
+			flags:   testFlags{FlagOperatorSet: uint64(0), FlagRPCURL: "https://flag.example.com"},
+			environ: []string{EnvOperatorSetID + "=4", EnvRPCURL + "=https://env.example.com"},
+			ctx:     ctx,
+			want:    &Target{AVS: common.HexToAddress(testAVS), OperatorSetID: 0, RPCURL: "https://flag.example.com", ChainID: 11155111},
+		},
+		{
+			name:    "environment only",
+			environ: []string{EnvAVSAddress + "=" + testAVS, EnvRPCURL + "=https://env.example.com"},
+			want:    &Target{AVS: common.HexToAddress(testAVS), RPCURL: "https://env.example.com"},
+		},
+		{
+			name:    "missing AVS",
+			flags:   testFlags{FlagRPCURL: "https://flag.example.com"},
+			wantErr: "--avs is required (or set FLICKR_AVS_ADDRESS",
+		},
+		{
+			name:    "missing RPC URL",
+			flags:   testFlags{FlagAVS: testAVS},
+			wantErr: "--rpc-url is required (or set FLICKR_RPC_URL",
+		},
+		{
+			name:    "invalid AVS",
+			flags:   testFlags{FlagAVS: "0x123", FlagRPCURL: "https://flag.example.com"},
+			wantErr: "invalid AVS address: 0x123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Contexts: map[string]*Context{}}
+			if tt.ctx != nil {
+				cfg.CurrentContext = "dev"
+				cfg.Contexts["dev"] = tt.ctx
+			}
+			e, err := Resolve(cfg, "config.json", nil, tt.environ)
+			require.NoError(t, err)
+
+			target, err := ResolveTarget(tt.flags, e.Context)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, target)
+		})
+	}
+}
+
+func TestResolve_Environment(t *testing.T) {
+	cfg := &Config{
+		CurrentContext: "mainnet",
+		Contexts: map[string]*Context{
+			"mainnet": {AVSAddress: testAVS, RemoteSigner: &RemoteSigner{URL: "https://signer", Address: testAVS}},
+			"holesky": {
+				AVSAddress:          testAVS,
+				KeystorePath:        "/keys/op.json",
+				KeystorePasswordRef: "env:PASSWORD",
+				EnvironmentVars:     map[string]string{"NETWORK": "holesky"},
+			},
+		},
+	}
+
+	environ := []string{
+		EnvContext + "=holesky",
+		EnvContainerEnvPrefix + "LOG_LEVEL=debug",
+		EnvPorts + "=9000:9000, 9001:9001",
+		EnvKMSProvider + "=aws",
+		EnvKMSKeyID + "=alias/operator",
+		"PATH=/usr/bin",
+	}
+	e, err := Resolve(cfg, "config.json", nil, environ)
+	require.NoError(t, err)
+
+	assert.Equal(t, "holesky", e.Name)
+	assert.Equal(t, map[string]string{"NETWORK": "holesky", "LOG_LEVEL": "debug"}, e.Context.EnvironmentVars)
+	assert.Equal(t, []string{"9000:9000", "9001:9001"}, e.Context.Ports)
+
+	// The environment's signer replaces the context's
+	assert.Equal(t, &KMSSigner{Provider: "aws", KeyID: "alias/operator"}, e.Context.KMSSigner)
+	assert.Empty(t, e.Context.KeystorePath)
+	assert.Empty(t, e.Context.KeystorePasswordRef)
+
+	assert.Equal(t, "env "+EnvContext, e.Origins["current-context"])
+	assert.Equal(t, "env "+EnvContainerEnvPrefix+"LOG_LEVEL", e.Origins["environment-vars.LOG_LEVEL"])
+	assert.Equal(t, "env "+EnvKMSProvider, e.Origins["kms-signer"])
+	assert.NotContains(t, e.Origins, "keystore-path")
+	assert.Equal(t, "context 'holesky' in config.json", e.Origins["avs-address"])
+
+	// The config is unchanged
+	assert.Equal(t, "/keys/op.json", cfg.Contexts["holesky"].KeystorePath)
+
+	_, err = Resolve(cfg, "config.json", nil, []string{EnvOperatorSetID + "=one"})
+	assert.ErrorContains(t, err, "invalid FLICKR_OPERATOR_SET_ID")
+
+	_, err = Resolve(cfg, "config.json", nil, []string{EnvAVSAddress + "=nope"})
+	assert.ErrorContains(t, err, "invalid environment: avsAddress")
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// namedVolumePattern matches Docker named volumes; any other volume source is
+// a host path
+var namedVolumePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// IsNamedVolume reports whether a volume in docker run -v syntax mounts a
+// named volume rather than a host path
+func IsNamedVolume(volume string) bool {
+	source, _, _ := strings.Cut(volume, ":")
+	return namedVolumePattern.MatchString(source)
+}
+
+// Validate checks that the settings of a context are well formed. It only
+// checks their shape; whether the chain, signer or files are reachable is
+// checked when they are used.
+func (c *Context) Validate() error {
+	if c.AVSAddress != "" && !common.IsHexAddress(c.AVSAddress) {
+		return fmt.Errorf("avsAddress: invalid address %q", c.AVSAddress)
+	}
+	if c.ReleaseManager != "" && !common.IsHexAddress(c.ReleaseManager) {
+		return fmt.Errorf("releaseManager: invalid address %q", c.ReleaseManager)
+	}
+	if c.RPCURL != "" {
+		if u, err := url.Parse(c.RPCURL); err != nil || u.Scheme == "" {
+			return fmt.Errorf("rpcUrl: invalid URL %q", c.RPCURL)
+		}
+	}
+
+	for source, mirror := range c.RegistryMirrors {
+		if source == "" || mirror == "" {
+			return fmt.Errorf("registryMirrors: empty registry in %q=%q", source, mirror)
+		}
+	}
+	for _, volume := range c.Volumes {
+		if !strings.Contains(volume, ":") {
+			return fmt.Errorf("volumes: invalid volume %q (expected SOURCE:TARGET)", volume)
+		}
+	}
+
+	for _, name := range c.DeploymentNames() {
+		if err := ValidateDeploymentName(name); err != nil {
+			return fmt.Errorf("deployments: %w", err)
+		}
+		if err := c.Deployments[name].validate(); err != nil {
+			return fmt.Errorf("deployments.%s: %w", name, err)
+		}
+	}
+
+	hosts := make([]string, 0, len(c.RegistryAuth))
+	for host := range c.RegistryAuth {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		cred := c.RegistryAuth[host]
+		if cred == nil {
+			return fmt.Errorf("registryAuth.%s: credential is empty", host)
+		}
+		if kind, _ := cred.Source(); kind == "none" {
+			return fmt.Errorf("registryAuth.%s: one of helper, tokenFile or tokenEnv is required", host)
+		}
+	}
+
+	return c.validateSigner()
+}
+
+func (c *Context) validateSigner() error {
+	if c.ECDSAPrivateKey != "" || c.KeystorePassword != "" {
+		return fmt.Errorf("plaintext secrets are not allowed; use ecdsaPrivateKeyRef or keystorePasswordRef")
+	}
+	if c.KeystorePasswordRef != "" && c.KeystorePath == "" {
+		return fmt.Errorf("keystorePasswordRef requires keystorePath")
+	}
+
+	var signers []string
+	if c.ECDSAPrivateKeyRef != "" {
+		signers = append(signers, "ecdsaPrivateKeyRef")
+	}
+	if c.KeystorePath != "" {
+		signers = append(signers, "keystorePath")
+	}
+	if c.RemoteSigner != nil {
+		signers = append(signers, "remoteSigner")
+		if c.RemoteSigner.URL == "" {
+			return fmt.Errorf("remoteSigner.url is required")
+		}
+		if !common.IsHexAddress(c.RemoteSigner.Address) {
+			return fmt.Errorf("remoteSigner.address: invalid address %q", c.RemoteSigner.Address)
+		}
+		if (c.RemoteSigner.ClientCert == "") != (c.RemoteSigner.ClientKey == "") {
+			return fmt.Errorf("remoteSigner.clientCert and remoteSigner.clientKey must be set together")
+		}
+	}
+	if c.KMSSigner != nil {
+		signers = append(signers, "kmsSigner")
+		if c.KMSSigner.Provider != KMSProviderAWS && c.KMSSigner.Provider != KMSProviderGCP {
+			return fmt.Errorf("kmsSigner.provider must be %s or %s, got %q", KMSProviderAWS, KMSProviderGCP, c.KMSSigner.Provider)
+		}
+		if c.KMSSigner.KeyID == "" {
+			return fmt.Errorf("kmsSigner.keyId is required")
+		}
+	}
+	if c.ExecSigner != nil {
+		signers = append(signers, "execSigner")
+		if c.ExecSigner.Command == "" {
+			return fmt.Errorf("execSigner.command is required")
+		}
+		if c.ExecSigner.Address != "" && !common.IsHexAddress(c.ExecSigner.Address) {
+			return fmt.Errorf("execSigner.address: invalid address %q", c.ExecSigner.Address)
+		}
+	}
+	if len(signers) > 1 {
+		return fmt.Errorf("only one signer may be configured, found %s", strings.Join(signers, ", "))
+	}
+
+	return nil
+}
+
+// checkNamedVolumes checks that the context and its deployments mount only
+// named volumes. Settings the operator didn't write themselves must not bind
+// host paths such as the Docker socket into the container.
+func (c *Context) checkNamedVolumes() error {
+	for _, volume := range c.Volumes {
+		if !IsNamedVolume(volume) {
+			return fmt.Errorf("volumes: invalid volume %q: only named volumes are allowed, not host paths", volume)
+		}
+	}
+	for _, name := range c.DeploymentNames() {
+		for _, volume := range c.Deployments[name].Volumes {
+			if !IsNamedVolume(volume) {
+				return fmt.Errorf("deployments.%s.volumes: invalid volume %q: only named volumes are allowed, not host paths", name, volume)
+			}
+		}
+	}
+	return nil
+}
@@ -136,6 +136,10 @@ func (d *dockerWithSleepWrapper) Run(ctx context.Context, ref string, opts docke
 	return d.inner.Run(ctx, ref, opts)
 }
 
+func (d *dockerWithSleepWrapper) RepoDigests(ctx context.Context, ref string) ([]string, error) {
+	return d.inner.RepoDigests(ctx, ref)
+}
+
 // TestRealDocker_HelloWorld tests with hello-world which exits immediately  
 func TestRealDocker_HelloWorld(t *testing.T) {
 	if testing.Short() {
@@ -92,8 +92,9 @@ func TestIntegration_Signer(t *testing.T) {
 	})
 
 	t.Run("Context with Signer", func(t *testing.T) {
+		t.Setenv("FLICKR_TEST_PRIVATE_KEY", "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
 		ctx := &config.Context{
-			ECDSAPrivateKey: "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+			ECDSAPrivateKeyRef: "env:FLICKR_TEST_PRIVATE_KEY",
 		}
 
 		sig, err := signer.FromContext(ctx)
@@ -3,17 +3,58 @@ package controller
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/yourorg/flickr/internal/approval"
 	"github.com/yourorg/flickr/internal/docker"
 	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/policy"
 	"github.com/yourorg/flickr/internal/ref"
 )
 
+// Labels recorded on containers started by Execute, so 'flickr verify' can
+// check them against the chain later
+const (
+	LabelAVS         = "flickr.avs"
+	LabelOperatorSet = "flickr.operator-set"
+	LabelReleaseID   = "flickr.release-id"
+	LabelReference   = "flickr.reference"
+)
+
 type Controller struct {
 	RM     eth.ReleaseManagerClient
 	Docker docker.Docker
+
+	// Attestations fetches attestations for policy checks (required when
+	// RunConfig.Policy is set)
+	Attestations AttestationSource
+	// Approvals provides release approvals (required when the policy has
+	// an approval quorum)
+	Approvals approval.Source
+	// Out receives policy results (defaults to stdout)
+	Out io.Writer
+}
+
+// AttestationSource fetches the attestations attached to an image
+type AttestationSource interface {
+	Attestations(ctx context.Context, ref string) (*policy.Attestations, error)
+}
+
+// RunnerAttestations is the AttestationSource of a docker.Runner, parsing the
+// raw attestations it fetches from the registry
+type RunnerAttestations struct {
+	Runner *docker.Runner
+}
+
+func (a RunnerAttestations) Attestations(ctx context.Context, ref string) (*policy.Attestations, error) {
+	provenance, sbom, err := a.Runner.Attestations(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return policy.ParseImagetools(provenance, sbom)
 }
 
 type RunConfig struct {
@@ -22,10 +63,22 @@ type RunConfig struct {
 	ReleaseID      *uint64
 	ReleaseManager common.Address
 	RPCURL         string
+	ChainID        uint64 // Chain RPCURL serves; approvals are bound to it
 	Name           string
 	Detached       bool
 	Env            map[string]string
 	Cmd            []string
+	Ports          []string
+	Volumes        []string
+
+	// Policy is evaluated against the artifact's attestations before pulling.
+	// A failing policy blocks the run unless IgnorePolicy is set.
+	Policy       *policy.Policy
+	IgnorePolicy bool
+
+	// RegistryMirrors rewrites artifact registries before pulling. The
+	// on-chain digest is always kept, so mirrored content is still verified.
+	RegistryMirrors map[string]string
 }
 
 func New(rm eth.ReleaseManagerClient, dockerRunner docker.Docker) *Controller {
@@ -89,17 +142,43 @@ To push a release, run:
 	// Convert digest to string format
 	digest := ref.Digest32ToSha256String(art.Digest32)
 	
-	// Build pullable reference
-	reference, err := ref.BuildReference(art.Registry, digest)
+	// Build pullable reference, fetching from a mirror if configured
+	registry := ref.RewriteRegistry(art.Registry, cfg.RegistryMirrors)
+	reference, err := ref.BuildReference(registry, digest)
 	if err != nil {
 		return fmt.Errorf("failed to build reference: %w", err)
 	}
 	
+	// Check supply-chain policy before anything is pulled
+	if cfg.Policy != nil {
+		if cfg.Policy.Approvals != nil {
+			subject := approval.NewSubject(cfg.ChainID, cfg.ReleaseManager, cfg.AVS, cfg.OperatorSetID, relID, rel)
+			if err := c.checkApprovals(ctx, cfg, subject); err != nil {
+				return err
+			}
+		}
+		// Attestations are only fetched from the registry if a rule needs them
+		if cfg.Policy.HasAttestationRules() {
+			if err := c.checkPolicy(ctx, cfg, reference); err != nil {
+				return err
+			}
+		}
+	}
+	
 	// 2) Docker pull
 	if err := c.Docker.Pull(ctx, reference); err != nil {
 		return fmt.Errorf("failed to pull image: %w", err)
 	}
 	
+	// Confirm the image that landed locally carries the on-chain digest
+	repoDigests, err := c.Docker.RepoDigests(ctx, reference)
+	if err != nil {
+		return fmt.Errorf("failed to inspect pulled image: %w", err)
+	}
+	if err := ref.VerifyRepoDigests(reference, digest, repoDigests); err != nil {
+		return fmt.Errorf("pulled image failed verification: %w", err)
+	}
+	
 	// 3) Docker run with AVS context
 	env := map[string]string{
 		"AVS_ADDRESS":     cfg.AVS.Hex(),
@@ -118,11 +197,87 @@ To push a release, run:
 		Detached: cfg.Detached,
 		Env:      env,
 		Cmd:      cfg.Cmd,
+		Ports:    cfg.Ports,
+		Volumes:  cfg.Volumes,
+		Labels: map[string]string{
+			LabelAVS:         cfg.AVS.Hex(),
+			LabelOperatorSet: fmt.Sprintf("%d", cfg.OperatorSetID),
+			LabelReleaseID:   fmt.Sprintf("%d", relID),
+			LabelReference:   reference,
+		},
 	}
 	
 	if err := c.Docker.Run(ctx, reference, runOpts); err != nil {
 		return fmt.Errorf("failed to run container: %w", err)
 	}
 	
+	return nil
+}
+
+// checkApprovals requires the policy's quorum of approvers to have signed the
+// release and its digests
+func (c *Controller) checkApprovals(ctx context.Context, cfg RunConfig, subject approval.Subject) error {
+	if c.Approvals == nil {
+		return fmt.Errorf("approval policy configured but no approval source available")
+	}
+
+	approvals, err := c.Approvals.Approvals(ctx, subject)
+	if err != nil {
+		if !cfg.IgnorePolicy {
+			return err
+		}
+		approvals = nil
+	}
+
+	result := approval.Evaluate(cfg.Policy.Approvals, subject, approvals)
+
+	out := c.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	result.Print(out)
+
+	if !result.Passed() {
+		if cfg.IgnorePolicy {
+			fmt.Fprintf(out, "Approval quorum not met, continuing because the policy is overridden\n")
+			return nil
+		}
+		return fmt.Errorf("release %d is not approved by a quorum (%s; use --ignore-policy to override)",
+			subject.ReleaseID, result.Checks[0].Detail)
+	}
+
+	return nil
+}
+
+func (c *Controller) checkPolicy(ctx context.Context, cfg RunConfig, reference string) error {
+	if c.Attestations == nil {
+		return fmt.Errorf("policy configured but no attestation source available")
+	}
+
+	att, err := c.Attestations.Attestations(ctx, reference)
+	if err != nil {
+		if !cfg.IgnorePolicy {
+			return fmt.Errorf("failed to fetch attestations: %w", err)
+		}
+		att = nil
+	}
+
+	result := cfg.Policy.Evaluate(reference, att)
+
+	out := c.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	result.Print(out)
+
+	if !result.Passed() {
+		if cfg.IgnorePolicy {
+			fmt.Fprintf(out, "Policy failed, continuing because the policy is overridden\n")
+			return nil
+		}
+		return fmt.Errorf("release artifact %s failed policy (%d of %d checks failed; use --ignore-policy to override)",
+			reference, len(result.Failures()), len(result.Checks))
+	}
+
 	return nil
 }
\ No newline at end of file
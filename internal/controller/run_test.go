@@ -2,14 +2,19 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/approval"
 	"github.com/yourorg/flickr/internal/docker"
 	"github.com/yourorg/flickr/internal/eth"
+	"github.com/yourorg/flickr/internal/policy"
+	"github.com/yourorg/flickr/internal/ref"
+	"github.com/yourorg/flickr/internal/signer"
 )
 
 // Mock ReleaseManager client
@@ -43,6 +48,10 @@ type captureDocker struct {
 	runOpts docker.RunOptions
 	pullErr error
 	runErr  error
+
+	// repoDigests overrides what the pulled image reports (defaults to the
+	// pulled reference)
+	repoDigests []string
 }
 
 func (d *captureDocker) Pull(ctx context.Context, ref string) error {
@@ -57,6 +66,13 @@ func (d *captureDocker) Run(ctx context.Context, ref string, opts docker.RunOpti
 	return d.runErr
 }
 
+func (d *captureDocker) RepoDigests(ctx context.Context, ref string) ([]string, error) {
+	if d.repoDigests != nil {
+		return d.repoDigests, nil
+	}
+	return []string{ref}, nil
+}
+
 func TestController_Execute_LatestRelease(t *testing.T) {
 	// Setup mock release manager
 	mockRelease := eth.Release{
@@ -123,6 +139,28 @@ func TestController_Execute_LatestRelease(t *testing.T) {
 	assert.True(t, dockerMock.runOpts.Detached)
 }
 
+func TestController_Execute_PortsAndVolumes(t *testing.T) {
+	rm := &mockRM{
+		latest: eth.Release{
+			Artifacts:     []eth.Artifact{{Registry: "ghcr.io/org/image", Digest32: [32]byte{0x01}}},
+			UpgradeByTime: 100,
+		},
+		latestID: 2,
+	}
+
+	dockerMock := &captureDocker{}
+	err := New(rm, dockerMock).Execute(context.Background(), RunConfig{
+		AVS:           common.HexToAddress("0x1234567890123456789012345678901234567890"),
+		OperatorSetID: 1,
+		Ports:         []string{"9000:9000"},
+		Volumes:       []string{"avs-data:/data"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"9000:9000"}, dockerMock.runOpts.Ports)
+	assert.Equal(t, []string{"avs-data:/data"}, dockerMock.runOpts.Volumes)
+}
+
 func TestController_Execute_SpecificRelease(t *testing.T) {
 	// Setup mock release manager
 	mockRelease := eth.Release{
@@ -211,12 +249,16 @@ func TestController_Execute_NoArtifacts(t *testing.T) {
 }
 
 func TestController_Execute_RegistryWithDigest(t *testing.T) {
-	// Test passthrough when registry already contains digest
+	// Test passthrough when registry already contains the on-chain digest
+	var digest32 [32]byte
+	for i := range digest32 {
+		digest32[i] = 0xcc
+	}
 	mockRelease := eth.Release{
 		Artifacts: []eth.Artifact{
 			{
 				Registry: "ghcr.io/org/image@sha256:" + strings.Repeat("cc", 32),
-				Digest32: [32]byte{}, // Should be ignored
+				Digest32: digest32,
 			},
 		},
 		UpgradeByTime: 100,
@@ -246,6 +288,37 @@ func TestController_Execute_RegistryWithDigest(t *testing.T) {
 	assert.Equal(t, expectedRef, dockerMock.ran)
 }
 
+func TestController_Execute_RegistryWithMismatchedDigest(t *testing.T) {
+	// A registry that embeds a digest other than the on-chain one is refused
+	var digest32 [32]byte
+	for i := range digest32 {
+		digest32[i] = 0xee
+	}
+	rm := &mockRM{
+		latest: eth.Release{
+			Artifacts: []eth.Artifact{
+				{
+					Registry: "ghcr.io/org/image@sha256:" + strings.Repeat("cc", 32),
+					Digest32: digest32,
+				},
+			},
+			UpgradeByTime: 100,
+		},
+		latestID: 5,
+	}
+
+	dockerMock := &captureDocker{}
+	err := New(rm, dockerMock).Execute(context.Background(), RunConfig{
+		AVS:           common.HexToAddress("0x1234567890123456789012345678901234567890"),
+		OperatorSetID: 1,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "embeds digest sha256:"+strings.Repeat("cc", 32))
+	assert.Contains(t, err.Error(), "the release attests sha256:"+strings.Repeat("ee", 32))
+	assert.Empty(t, dockerMock.pulled, "image must not be pulled")
+	assert.Empty(t, dockerMock.ran, "container must not start")
+}
+
 func TestController_Execute_MultipleArtifacts(t *testing.T) {
 	// MVP only uses first artifact
 	mockRelease := eth.Release{
@@ -291,4 +364,277 @@ func TestController_Execute_MultipleArtifacts(t *testing.T) {
 	// Should only use first artifact
 	assert.Contains(t, dockerMock.pulled, "first.io/image")
 	assert.NotContains(t, dockerMock.pulled, "second.io/image")
+}
+
+func TestController_Execute_RegistryMirror(t *testing.T) {
+	mockRelease := eth.Release{
+		Artifacts: []eth.Artifact{
+			{
+				Registry: "ghcr.io/org/image",
+				Digest32: func() [32]byte {
+					var d [32]byte
+					for i := range d {
+						d[i] = 0xdd
+					}
+					return d
+				}(),
+			},
+		},
+		UpgradeByTime: 300,
+	}
+
+	rm := &mockRM{
+		latest:   mockRelease,
+		latestID: 2,
+	}
+
+	dockerMock := &captureDocker{}
+	ctrl := New(rm, dockerMock)
+
+	cfg := RunConfig{
+		AVS:             common.HexToAddress("0x1234567890123456789012345678901234567890"),
+		OperatorSetID:   1,
+		ReleaseManager:  common.HexToAddress("0xabcdef1234567890abcdef1234567890abcdef12"),
+		RPCURL:          "https://eth.example.com",
+		RegistryMirrors: map[string]string{"ghcr.io": "mirror.internal"},
+	}
+
+	err := ctrl.Execute(context.Background(), cfg)
+	require.NoError(t, err)
+
+	// Should fetch from the mirror while keeping the on-chain digest
+	expectedRef := "mirror.internal/org/image@sha256:" + strings.Repeat("dd", 32)
+	assert.Equal(t, expectedRef, dockerMock.pulled)
+	assert.Equal(t, expectedRef, dockerMock.ran)
+}
+
+// Mock attestation source
+type fakeAttestations struct {
+	att *policy.Attestations
+	err error
+}
+
+func (f *fakeAttestations) Attestations(ctx context.Context, ref string) (*policy.Attestations, error) {
+	return f.att, f.err
+}
+
+func TestController_Execute_Policy(t *testing.T) {
+	mockRelease := eth.Release{
+		Artifacts: []eth.Artifact{
+			{Registry: "ghcr.io/org/image", Digest32: [32]byte{0x01}},
+		},
+		UpgradeByTime: 400,
+	}
+
+	att := &policy.Attestations{
+		Provenance: []policy.Provenance{{BuilderID: "https://github.com/actions/runner"}},
+	}
+
+	tests := []struct {
+		name         string
+		policy       *policy.Policy
+		ignore       bool
+		expectError  bool
+		expectPulled bool
+	}{
+		{
+			name:         "passing policy",
+			policy:       &policy.Policy{RequiredBuilder: "https://github.com/actions/runner"},
+			expectPulled: true,
+		},
+		{
+			name:        "failing policy blocks",
+			policy:      &policy.Policy{RequiredBuilder: "https://builder.example.com"},
+			expectError: true,
+		},
+		{
+			name:         "failing policy overridden",
+			policy:       &policy.Policy{RequiredBuilder: "https://builder.example.com"},
+			ignore:       true,
+			expectPulled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dockerMock := &captureDocker{}
+			ctrl := New(&mockRM{latest: mockRelease, latestID: 1}, dockerMock)
+			ctrl.Attestations = &fakeAttestations{att: att}
+			ctrl.Out = &strings.Builder{}
+
+			cfg := RunConfig{
+				AVS:           common.HexToAddress("0x1234567890123456789012345678901234567890"),
+				OperatorSetID: 1,
+				Policy:        tt.policy,
+				IgnorePolicy:  tt.ignore,
+			}
+
+			err := ctrl.Execute(context.Background(), cfg)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "failed policy")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectPulled, dockerMock.pulled != "")
+			assert.Contains(t, ctrl.Out.(*strings.Builder).String(), "builder")
+		})
+	}
+}
+
+func TestController_Execute_PolicyWithoutAttestationRules(t *testing.T) {
+	mockRelease := eth.Release{
+		Artifacts: []eth.Artifact{
+			{Registry: "ghcr.io/org/image", Digest32: [32]byte{0x01}},
+		},
+		UpgradeByTime: 400,
+	}
+
+	// The registry is not asked for attestations no rule needs
+	dockerMock := &captureDocker{}
+	ctrl := New(&mockRM{latest: mockRelease, latestID: 1}, dockerMock)
+	ctrl.Attestations = &fakeAttestations{err: errors.New("registry unreachable")}
+	ctrl.Out = &strings.Builder{}
+
+	err := ctrl.Execute(context.Background(), RunConfig{
+		AVS:           common.HexToAddress("0x1234567890123456789012345678901234567890"),
+		OperatorSetID: 1,
+		Policy:        &policy.Policy{},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, dockerMock.pulled)
+	assert.Empty(t, ctrl.Out.(*strings.Builder).String())
+}
+
+func TestController_Execute_DigestVerification(t *testing.T) {
+	var digest32 [32]byte
+	for i := range digest32 {
+		digest32[i] = 0xee
+	}
+
+	rm := &mockRM{
+		latest: eth.Release{
+			Artifacts:     []eth.Artifact{{Registry: "ghcr.io/org/image", Digest32: digest32}},
+			UpgradeByTime: 400,
+		},
+		latestID: 5,
+	}
+
+	cfg := RunConfig{
+		AVS:           common.HexToAddress("0x1234567890123456789012345678901234567890"),
+		OperatorSetID: 3,
+	}
+	expectedRef := "ghcr.io/org/image@sha256:" + strings.Repeat("ee", 32)
+
+	t.Run("matching digest labels the container", func(t *testing.T) {
+		dockerMock := &captureDocker{}
+		err := New(rm, dockerMock).Execute(context.Background(), cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedRef, dockerMock.ran)
+		assert.Equal(t, map[string]string{
+			LabelAVS:         "0x1234567890123456789012345678901234567890",
+			LabelOperatorSet: "3",
+			LabelReleaseID:   "5",
+			LabelReference:   expectedRef,
+		}, dockerMock.runOpts.Labels)
+	})
+
+	t.Run("mismatched digest blocks the run", func(t *testing.T) {
+		dockerMock := &captureDocker{
+			repoDigests: []string{"ghcr.io/org/image@sha256:" + strings.Repeat("ff", 32)},
+		}
+		err := New(rm, dockerMock).Execute(context.Background(), cfg)
+		require.Error(t, err)
+
+		var mismatch *ref.DigestMismatchError
+		assert.ErrorAs(t, err, &mismatch)
+		assert.Contains(t, err.Error(), strings.Repeat("ff", 32))
+		assert.Equal(t, expectedRef, dockerMock.pulled)
+		assert.Empty(t, dockerMock.ran, "container must not start")
+	})
+}
+
+// Mock approval source
+type fakeApprovals struct {
+	approvals []approval.Approval
+}
+
+func (f *fakeApprovals) Approvals(ctx context.Context, subject approval.Subject) ([]approval.Approval, error) {
+	return f.approvals, nil
+}
+
+func TestController_Execute_Approvals(t *testing.T) {
+	mockRelease := eth.Release{
+		Artifacts: []eth.Artifact{
+			{Registry: "ghcr.io/org/image", Digest32: [32]byte{0x02}},
+		},
+		UpgradeByTime: 400,
+	}
+	avs := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	rm := common.HexToAddress("0xabcdef1234567890abcdef1234567890abcdef12")
+
+	maintainer, err := signer.NewECDSASignerFromHex("0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.NoError(t, err)
+	approved, err := approval.Sign(maintainer, approval.NewSubject(31337, rm, avs, 1, 3, mockRelease))
+	require.NoError(t, err)
+
+	pol := &policy.Policy{
+		Approvals: &policy.ApprovalPolicy{
+			Threshold: 1,
+			Approvers: []string{maintainer.Address().Hex()},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		approvals    []approval.Approval
+		ignore       bool
+		expectError  bool
+		expectPulled bool
+	}{
+		{
+			name:         "quorum met",
+			approvals:    []approval.Approval{*approved},
+			expectPulled: true,
+		},
+		{
+			name:        "quorum not met blocks",
+			expectError: true,
+		},
+		{
+			name:         "quorum not met overridden",
+			ignore:       true,
+			expectPulled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dockerMock := &captureDocker{}
+			ctrl := New(&mockRM{latest: mockRelease, latestID: 3}, dockerMock)
+			ctrl.Approvals = &fakeApprovals{approvals: tt.approvals}
+			ctrl.Out = &strings.Builder{}
+
+			// No attestation rules, so no attestation source is needed
+			cfg := RunConfig{
+				AVS:            avs,
+				OperatorSetID:  1,
+				ReleaseManager: rm,
+				ChainID:        31337,
+				Policy:         pol,
+				IgnorePolicy:   tt.ignore,
+			}
+
+			err := ctrl.Execute(context.Background(), cfg)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "not approved by a quorum")
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectPulled, dockerMock.pulled != "")
+			assert.Contains(t, ctrl.Out.(*strings.Builder).String(), "approvals")
+		})
+	}
 }
\ No newline at end of file
@@ -1,9 +1,16 @@
 package docker
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"strings"
+
+	"github.com/yourorg/flickr/internal/ref"
 )
 
 type RunOptions struct {
@@ -11,21 +18,55 @@ type RunOptions struct {
 	Detached bool
 	Env      map[string]string
 	Cmd      []string // Optional command to run in container
+	Labels   map[string]string
+	Ports    []string // Published ports, in docker run -p syntax
+	Volumes  []string // Mounts, in docker run -v syntax
 }
 
 type Docker interface {
 	Pull(ctx context.Context, ref string) error
 	Run(ctx context.Context, ref string, opts RunOptions) error
+	RepoDigests(ctx context.Context, ref string) ([]string, error)
+}
+
+// Container describes a local container
+type Container struct {
+	ID     string
+	Name   string
+	Image  string // Image ID the container was created from
+	State  string
+	Labels map[string]string
 }
 
-type Runner struct{}
+type Runner struct {
+	// ConfigDir overrides the Docker client configuration directory
+	// (DOCKER_CONFIG), e.g. to supply per-context registry credentials
+	ConfigDir string
+}
 
 func New() *Runner {
 	return &Runner{}
 }
 
-func (r *Runner) Pull(ctx context.Context, ref string) error {
-	cmd := exec.CommandContext(ctx, "docker", "pull", ref)
+// command builds a docker CLI invocation using the runner's configuration
+func (r *Runner) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	if r.ConfigDir != "" {
+		cmd.Env = append(os.Environ(), "DOCKER_CONFIG="+r.ConfigDir)
+	}
+	return cmd
+}
+
+func (r *Runner) Pull(ctx context.Context, reference string) error {
+	// Digest references are immutable, so a cached copy (e.g. from
+	// 'flickr release import') can be used without contacting the registry.
+	// A loaded archive can name any image after a digest, so the copy is
+	// only used if Docker computed that digest for it.
+	if i := strings.Index(reference, "@sha256:"); i >= 0 && r.hasRepoDigest(ctx, reference, reference[i+1:]) {
+		return nil
+	}
+
+	cmd := r.command(ctx, "pull", reference)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("docker pull failed: %v\n%s", err, string(out))
@@ -48,6 +89,15 @@ func (r *Runner) Run(ctx context.Context, ref string, opts RunOptions) error {
 	for k, v := range opts.Env {
 		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
 	}
+	for k, v := range opts.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", k, v))
+	}
+	for _, port := range opts.Ports {
+		args = append(args, "-p", port)
+	}
+	for _, volume := range opts.Volumes {
+		args = append(args, "-v", volume)
+	}
 	args = append(args, ref)
 	
 	// Add optional command
@@ -55,10 +105,192 @@ func (r *Runner) Run(ctx context.Context, ref string, opts RunOptions) error {
 		args = append(args, opts.Cmd...)
 	}
 	
-	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd := r.command(ctx, args...)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("docker run failed: %v\n%s", err, string(out))
 	}
 	return nil
+}
+
+// Push pushes a local image to its registry
+func (r *Runner) Push(ctx context.Context, ref string) error {
+	cmd := r.command(ctx, "push", ref)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker push failed: %v\n%s", err, string(out))
+	}
+	return nil
+}
+
+// Login authenticates against a registry, storing the credentials in the
+// runner's configuration directory
+func (r *Runner) Login(ctx context.Context, host, username, password string) error {
+	cmd := r.command(ctx, "login", host, "--username", username, "--password-stdin")
+	cmd.Stdin = strings.NewReader(password)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker login failed: %v\n%s", err, string(out))
+	}
+	return nil
+}
+
+// RepoDigests returns the repository digests (repo@sha256:...) recorded for a
+// local image
+func (r *Runner) RepoDigests(ctx context.Context, ref string) ([]string, error) {
+	var stderr strings.Builder
+	cmd := r.command(ctx, "image", "inspect", "--format", "{{json .RepoDigests}}", ref)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker image inspect failed: %v\n%s", err, stderr.String())
+	}
+
+	var digests []string
+	if err := json.Unmarshal(bytes.TrimSpace(out), &digests); err != nil {
+		return nil, fmt.Errorf("failed to parse repo digests: %w", err)
+	}
+	return digests, nil
+}
+
+// hasRepoDigest reports whether the local image of reference has the expected
+// repository digest
+func (r *Runner) hasRepoDigest(ctx context.Context, reference, expected string) bool {
+	repoDigests, err := r.RepoDigests(ctx, reference)
+	return err == nil && ref.VerifyRepoDigests(reference, expected, repoDigests) == nil
+}
+
+// ImageID returns the ID of a local image
+func (r *Runner) ImageID(ctx context.Context, ref string) (string, error) {
+	var stderr strings.Builder
+	cmd := r.command(ctx, "image", "inspect", "--format", "{{.Id}}", ref)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("docker image inspect failed: %v\n%s", err, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Containers returns all containers, running or stopped, that have the given label
+func (r *Runner) Containers(ctx context.Context, label string) ([]Container, error) {
+	var stderr strings.Builder
+	cmd := r.command(ctx, "ps", "--all", "--quiet", "--no-trunc", "--filter", "label="+label)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker ps failed: %v\n%s", err, stderr.String())
+	}
+
+	ids := strings.Fields(string(out))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	stderr.Reset()
+	cmd = r.command(ctx, append([]string{"container", "inspect"}, ids...)...)
+	cmd.Stderr = &stderr
+	out, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker container inspect failed: %v\n%s", err, stderr.String())
+	}
+
+	var inspected []struct {
+		ID    string `json:"Id"`
+		Name  string
+		Image string
+		State struct {
+			Status string
+		}
+		Config struct {
+			Labels map[string]string
+		}
+	}
+	if err := json.Unmarshal(out, &inspected); err != nil {
+		return nil, fmt.Errorf("failed to parse container inspect output: %w", err)
+	}
+
+	containers := make([]Container, 0, len(inspected))
+	for _, c := range inspected {
+		containers = append(containers, Container{
+			ID:     c.ID,
+			Name:   strings.TrimPrefix(c.Name, "/"),
+			Image:  c.Image,
+			State:  c.State.Status,
+			Labels: c.Config.Labels,
+		})
+	}
+	return containers, nil
+}
+
+// Copy copies an image from src to dst registry-to-registry without going
+// through the local image store. Multi-arch indexes are copied as-is, so the
+// manifest digest is preserved.
+func (r *Runner) Copy(ctx context.Context, src, dst string) error {
+	cmd := r.command(ctx, "buildx", "imagetools", "create", "--tag", dst, src)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker buildx imagetools create failed: %v\n%s", err, string(out))
+	}
+	return nil
+}
+
+// RemoteExists reports whether a reference can be resolved in its registry
+func (r *Runner) RemoteExists(ctx context.Context, ref string) bool {
+	cmd := r.command(ctx, "buildx", "imagetools", "inspect", ref)
+	return cmd.Run() == nil
+}
+
+// ImageExists reports whether a reference is present in the local image store
+func (r *Runner) ImageExists(ctx context.Context, ref string) bool {
+	cmd := r.command(ctx, "image", "inspect", ref)
+	return cmd.Run() == nil
+}
+
+// Save writes the given images to w as a tar archive (an OCI image layout on
+// Docker 25 and later)
+func (r *Runner) Save(ctx context.Context, w io.Writer, refs ...string) error {
+	var stderr strings.Builder
+	cmd := r.command(ctx, append([]string{"save"}, refs...)...)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker save failed: %v\n%s", err, stderr.String())
+	}
+	return nil
+}
+
+// Load loads images from a tar archive produced by Save
+func (r *Runner) Load(ctx context.Context, path string) error {
+	cmd := r.command(ctx, "load", "--input", path)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker load failed: %v\n%s", err, string(out))
+	}
+	return nil
+}
+
+// Attestations fetches the provenance and SBOM attestations attached to an
+// image in its registry, as the raw JSON printed by imagetools inspect
+func (r *Runner) Attestations(ctx context.Context, ref string) ([]byte, []byte, error) {
+	provenance, err := r.inspectFormat(ctx, ref, "{{json .Provenance}}")
+	if err != nil {
+		return nil, nil, err
+	}
+	sbom, err := r.inspectFormat(ctx, ref, "{{json .SBOM}}")
+	if err != nil {
+		return nil, nil, err
+	}
+	return provenance, sbom, nil
+}
+
+func (r *Runner) inspectFormat(ctx context.Context, ref, format string) ([]byte, error) {
+	var stderr strings.Builder
+	cmd := r.command(ctx, "buildx", "imagetools", "inspect", ref, "--format", format)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker buildx imagetools inspect failed: %v\n%s", err, stderr.String())
+	}
+	return bytes.TrimSpace(out), nil
 }
\ No newline at end of file
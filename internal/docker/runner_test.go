@@ -0,0 +1,56 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDocker puts a docker script on PATH that logs its arguments and
+// reports repoDigests for every local image. It returns the log path.
+func fakeDocker(t *testing.T, repoDigests string) string {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "calls")
+	script := `#!/bin/sh
+echo "$@" >> "` + logPath + `"
+if [ "$1" = "image" ]; then
+	echo '` + repoDigests + `'
+fi
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return logPath
+}
+
+func TestRunner_Pull_LocalCopy(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	reference := "ghcr.io/org/avs@" + digest
+
+	tests := []struct {
+		name        string
+		repoDigests string
+		wantPull    bool
+	}{
+		{name: "Matching digest", repoDigests: `["ghcr.io/org/avs@` + digest + `"]`},
+		{name: "Named after the digest", repoDigests: `["ghcr.io/org/avs@sha256:` + strings.Repeat("cd", 32) + `"]`, wantPull: true},
+		{name: "Other repository", repoDigests: `["ghcr.io/org/other@` + digest + `"]`, wantPull: true},
+		{name: "No repo digests", repoDigests: `[]`, wantPull: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := fakeDocker(t, tt.repoDigests)
+
+			require.NoError(t, New().Pull(context.Background(), reference))
+
+			calls, err := os.ReadFile(logPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPull, strings.Contains(string(calls), "pull "+reference))
+		})
+	}
+}
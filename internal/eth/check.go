@@ -0,0 +1,242 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/AllocationManager"
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ReleaseManager"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/olekukonko/tablewriter"
+	"github.com/yourorg/flickr/internal/config"
+)
+
+// checkTimeout bounds the RPC calls of RunCheck
+const checkTimeout = 30 * time.Second
+
+// CheckBackend is the chain access needed to check a context
+type CheckBackend interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	CodeAt(ctx context.Context, addr common.Address) ([]byte, error)
+	TotalReleases(ctx context.Context, rm, avs common.Address, opSetID uint32) (*big.Int, error)
+	OperatorSetCount(ctx context.Context, am, avs common.Address) (*big.Int, error)
+	IsOperatorSet(ctx context.Context, am, avs common.Address, opSetID uint32) (bool, error)
+}
+
+// Check is the result of checking one context setting against the chain
+type Check struct {
+	Name    string // Setting checked
+	Result  string // What was found
+	Err     error  // Why the check failed
+	Skipped bool   // The check could not run, e.g. because the setting is not set
+}
+
+// CheckReport is the result of CheckContext
+type CheckReport struct {
+	ChainID uint64 // Chain the RPC URL serves; 0 if it did not respond
+	Checks  []Check
+}
+
+// Failed returns the number of failed checks
+func (r *CheckReport) Failed() int {
+	failed := 0
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Print writes the checks as a table
+func (r *CheckReport) Print(w io.Writer) {
+	table := tablewriter.NewWriter(w)
+	table.Header("SETTING", "RESULT", "STATUS")
+
+	for _, check := range r.Checks {
+		status := "ok"
+		switch {
+		case check.Err != nil:
+			status = "FAILED: " + check.Err.Error()
+		case check.Skipped:
+			status = "skipped"
+		}
+		table.Append([]string{check.Name, check.Result, status})
+	}
+	table.Render()
+}
+
+// RunCheck runs CheckContextRPC with a timeout and prints the report to w
+func RunCheck(w io.Writer, c *config.Context) *CheckReport {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	report := CheckContextRPC(ctx, c)
+	report.Print(w)
+	return report
+}
+
+// CheckContextRPC runs CheckContext against the context's RPC URL
+func CheckContextRPC(ctx context.Context, c *config.Context) *CheckReport {
+	if c.RPCURL == "" {
+		return rpcFailed(c, fmt.Errorf("not set"))
+	}
+
+	client, err := ethclient.DialContext(ctx, c.RPCURL)
+	if err != nil {
+		return rpcFailed(c, fmt.Errorf("failed to connect to Ethereum client: %w", err))
+	}
+	defer client.Close()
+
+	return CheckContext(ctx, &checkBackend{client: client}, c)
+}
+
+// rpcFailed reports a failed RPC check, skipping the checks that need it
+func rpcFailed(c *config.Context, err error) *CheckReport {
+	report := &CheckReport{Checks: []Check{{Name: "rpc-url", Result: c.RPCURL, Err: err}}}
+	for _, name := range []string{"release-manager", "avs-address", "operator-set-id"} {
+		report.Checks = append(report.Checks, Check{Name: name, Skipped: true})
+	}
+	return report
+}
+
+// CheckContext checks a context's settings against the chain: the RPC URL
+// responds and serves the chain the context is pinned to, the ReleaseManager
+// is deployed, the AVS has created operator sets and the operator set
+// exists. Checks that depend on a failed check are skipped.
+func CheckContext(ctx context.Context, backend CheckBackend, c *config.Context) *CheckReport {
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return rpcFailed(c, fmt.Errorf("RPC did not respond: %w", err))
+	}
+
+	if err := matchChainID(c.ChainID, chainID.Uint64()); err != nil {
+		return rpcFailed(c, err)
+	}
+
+	report := &CheckReport{ChainID: chainID.Uint64()}
+	add := func(check Check) {
+		report.Checks = append(report.Checks, check)
+	}
+	skip := func(name string) {
+		add(Check{Name: name, Skipped: true})
+	}
+
+	add(Check{Name: "rpc-url", Result: fmt.Sprintf("%s (chain %d)", getChainName(report.ChainID), report.ChainID)})
+
+	defaults, _ := GetDefaultContractAddresses(report.ChainID)
+	avs := common.HexToAddress(c.AVSAddress)
+
+	rmAddr := common.HexToAddress(c.ReleaseManager)
+	if c.ReleaseManager == "" && defaults != nil {
+		rmAddr = common.HexToAddress(defaults.ReleaseManager)
+	}
+	add(checkReleaseManager(ctx, backend, rmAddr, avs, c.OperatorSetID))
+
+	if c.AVSAddress == "" {
+		add(Check{Name: "avs-address", Result: "not set", Skipped: true})
+		skip("operator-set-id")
+		return report
+	}
+	if defaults == nil || defaults.AllocationManager == "" {
+		add(Check{Name: "avs-address", Result: fmt.Sprintf("no AllocationManager known for chain %d", report.ChainID), Skipped: true})
+		skip("operator-set-id")
+		return report
+	}
+	amAddr := common.HexToAddress(defaults.AllocationManager)
+
+	count, err := backend.OperatorSetCount(ctx, amAddr, avs)
+	switch {
+	case err != nil:
+		err = fmt.Errorf("failed to get operator sets: %w", err)
+	case count.Sign() == 0:
+		err = fmt.Errorf("%s has created no operator sets; is it registered as an AVS?", avs.Hex())
+	}
+	if err != nil {
+		add(Check{Name: "avs-address", Result: avs.Hex(), Err: err})
+		skip("operator-set-id")
+		return report
+	}
+	add(Check{Name: "avs-address", Result: fmt.Sprintf("%s (%s operator sets)", avs.Hex(), count)})
+
+	exists, err := backend.IsOperatorSet(ctx, amAddr, avs, c.OperatorSetID)
+	switch {
+	case err != nil:
+		err = fmt.Errorf("failed to check operator set: %w", err)
+	case !exists:
+		err = fmt.Errorf("operator set %d does not exist for AVS %s", c.OperatorSetID, avs.Hex())
+	}
+	add(Check{Name: "operator-set-id", Result: fmt.Sprintf("%d", c.OperatorSetID), Err: err})
+
+	return report
+}
+
+// checkReleaseManager checks that the ReleaseManager is deployed and answers
+// getTotalReleases
+func checkReleaseManager(ctx context.Context, backend CheckBackend, rmAddr, avs common.Address, opSetID uint32) Check {
+	check := Check{Name: "release-manager", Result: rmAddr.Hex()}
+	if rmAddr == (common.Address{}) {
+		check.Err = fmt.Errorf("not set and no default for this chain")
+		return check
+	}
+
+	code, err := backend.CodeAt(ctx, rmAddr)
+	if err != nil {
+		check.Err = fmt.Errorf("failed to get code: %w", err)
+		return check
+	}
+	if len(code) == 0 {
+		check.Err = fmt.Errorf("no contract deployed at %s", rmAddr.Hex())
+		return check
+	}
+
+	total, err := backend.TotalReleases(ctx, rmAddr, avs, opSetID)
+	if err != nil {
+		check.Err = fmt.Errorf("getTotalReleases failed, is %s a ReleaseManager? %w", rmAddr.Hex(), err)
+		return check
+	}
+	check.Result = fmt.Sprintf("%s (%s releases)", rmAddr.Hex(), total)
+	return check
+}
+
+// checkBackend implements CheckBackend with the contract bindings
+type checkBackend struct {
+	client *ethclient.Client
+}
+
+func (b *checkBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return b.client.ChainID(ctx)
+}
+
+func (b *checkBackend) CodeAt(ctx context.Context, addr common.Address) ([]byte, error) {
+	return b.client.CodeAt(ctx, addr, nil)
+}
+
+func (b *checkBackend) TotalReleases(ctx context.Context, rm, avs common.Address, opSetID uint32) (*big.Int, error) {
+	contract, err := ReleaseManager.NewReleaseManager(rm, b.client)
+	if err != nil {
+		return nil, err
+	}
+	return contract.GetTotalReleases(&bind.CallOpts{Context: ctx}, ReleaseManager.OperatorSet{Avs: avs, Id: opSetID})
+}
+
+func (b *checkBackend) OperatorSetCount(ctx context.Context, am, avs common.Address) (*big.Int, error) {
+	contract, err := AllocationManager.NewAllocationManager(am, b.client)
+	if err != nil {
+		return nil, err
+	}
+	return contract.GetOperatorSetCount(&bind.CallOpts{Context: ctx}, avs)
+}
+
+func (b *checkBackend) IsOperatorSet(ctx context.Context, am, avs common.Address, opSetID uint32) (bool, error) {
+	contract, err := AllocationManager.NewAllocationManager(am, b.client)
+	if err != nil {
+		return false, err
+	}
+	return contract.IsOperatorSet(&bind.CallOpts{Context: ctx}, AllocationManager.OperatorSet{Avs: avs, Id: opSetID})
+}
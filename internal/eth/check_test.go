@@ -0,0 +1,158 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/config"
+)
+
+// fakeCheckBackend is a chain with one ReleaseManager and one AVS with
+// operator sets 0 and 1
+type fakeCheckBackend struct {
+	chainErr error
+	rm       common.Address
+	avs      common.Address
+}
+
+func (f *fakeCheckBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(11155111), f.chainErr
+}
+
+func (f *fakeCheckBackend) CodeAt(ctx context.Context, addr common.Address) ([]byte, error) {
+	if addr == f.rm {
+		return []byte{0x60, 0x80}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeCheckBackend) TotalReleases(ctx context.Context, rm, avs common.Address, opSetID uint32) (*big.Int, error) {
+	return big.NewInt(2), nil
+}
+
+func (f *fakeCheckBackend) OperatorSetCount(ctx context.Context, am, avs common.Address) (*big.Int, error) {
+	if avs == f.avs {
+		return big.NewInt(2), nil
+	}
+	return big.NewInt(0), nil
+}
+
+func (f *fakeCheckBackend) IsOperatorSet(ctx context.Context, am, avs common.Address, opSetID uint32) (bool, error) {
+	return avs == f.avs && opSetID < 2, nil
+}
+
+func TestCheckContext(t *testing.T) {
+	defaults, err := GetDefaultContractAddresses(11155111)
+	require.NoError(t, err)
+
+	avs := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	backend := &fakeCheckBackend{rm: common.HexToAddress(defaults.ReleaseManager), avs: avs}
+
+	tests := []struct {
+		name    string
+		backend *fakeCheckBackend
+		ctx     *config.Context
+		failed  []string // Names of the failed checks
+		skipped []string // Names of the skipped checks
+	}{
+		{
+			name: "valid",
+			ctx:  &config.Context{RPCURL: "http://rpc", AVSAddress: avs.Hex(), OperatorSetID: 1},
+		},
+		{
+			name:    "no AVS",
+			ctx:     &config.Context{RPCURL: "http://rpc"},
+			skipped: []string{"avs-address", "operator-set-id"},
+		},
+		{
+			name:    "RPC down",
+			backend: &fakeCheckBackend{chainErr: errors.New("connection refused")},
+			ctx:     &config.Context{RPCURL: "http://rpc", AVSAddress: avs.Hex()},
+			failed:  []string{"rpc-url"},
+			skipped: []string{"release-manager", "avs-address", "operator-set-id"},
+		},
+		{
+			name:    "pinned to another chain",
+			ctx:     &config.Context{RPCURL: "http://rpc", ChainID: 1, AVSAddress: avs.Hex()},
+			failed:  []string{"rpc-url"},
+			skipped: []string{"release-manager", "avs-address", "operator-set-id"},
+		},
+		{
+			name:   "no ReleaseManager",
+			ctx:    &config.Context{RPCURL: "http://rpc", ReleaseManager: avs.Hex(), AVSAddress: avs.Hex()},
+			failed: []string{"release-manager"},
+		},
+		{
+			name:    "unknown AVS",
+			ctx:     &config.Context{RPCURL: "http://rpc", AVSAddress: "0x59c8d715dca616e032b744a753c017c9f3e16bf4"},
+			failed:  []string{"avs-address"},
+			skipped: []string{"operator-set-id"},
+		},
+		{
+			name:   "unknown operator set",
+			ctx:    &config.Context{RPCURL: "http://rpc", AVSAddress: avs.Hex(), OperatorSetID: 7},
+			failed: []string{"operator-set-id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := backend
+			if tt.backend != nil {
+				b = tt.backend
+			}
+			report := CheckContext(context.Background(), b, tt.ctx)
+
+			var failed, skipped, names []string
+			for _, check := range report.Checks {
+				names = append(names, check.Name)
+				if check.Err != nil {
+					failed = append(failed, check.Name)
+				}
+				if check.Skipped {
+					skipped = append(skipped, check.Name)
+				}
+			}
+			assert.Equal(t, []string{"rpc-url", "release-manager", "avs-address", "operator-set-id"}, names)
+			assert.Equal(t, tt.failed, failed)
+			assert.Equal(t, tt.skipped, skipped)
+			assert.Equal(t, len(tt.failed), report.Failed())
+			if len(tt.failed) == 0 || tt.failed[0] != "rpc-url" {
+				assert.Equal(t, uint64(11155111), report.ChainID)
+			}
+		})
+	}
+}
+
+func TestMatchChainID(t *testing.T) {
+	assert.NoError(t, matchChainID(0, 11155111))
+	assert.NoError(t, matchChainID(11155111, 11155111))
+
+	err := matchChainID(1, 11155111)
+	var mismatch *ChainMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, uint64(1), mismatch.Expected)
+	assert.Contains(t, err.Error(), "serves Sepolia Testnet (chain 11155111), but the context is pinned to Ethereum Mainnet (chain 1)")
+	assert.Contains(t, err.Error(), "flickr context set --chain-id 11155111")
+}
+
+func TestLookupNetwork(t *testing.T) {
+	n, err := LookupNetwork("sepolia")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11155111), n.ChainID)
+	assert.Equal(t, "Sepolia Testnet (chain 11155111)", n.String())
+
+	// Every network has default contracts
+	for _, n := range Networks {
+		_, err := GetDefaultContractAddresses(n.ChainID)
+		assert.NoError(t, err, n.Name)
+	}
+
+	_, err = LookupNetwork("holesky")
+	assert.ErrorContains(t, err, "expected one of: mainnet, sepolia, local")
+}
@@ -4,14 +4,17 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/yourorg/flickr/internal/config"
 )
 
 // DefaultContractAddresses contains default contract addresses for a chain
 type DefaultContractAddresses struct {
-	ReleaseManager string
+	ReleaseManager    string
+	AllocationManager string // EigenLayer AllocationManager, where AVSs create operator sets
 }
 
 // GetDefaultContractAddresses returns the default contract addresses for a given chain ID
@@ -19,21 +22,54 @@ func GetDefaultContractAddresses(chainID uint64) (*DefaultContractAddresses, err
 	switch chainID {
 	case 11155111: // Sepolia
 		return &DefaultContractAddresses{
-			ReleaseManager: "0xd9Cb89F1993292dEC2F973934bC63B0f2A702776",
+			ReleaseManager:    "0xd9Cb89F1993292dEC2F973934bC63B0f2A702776",
+			AllocationManager: "0x42583067658071247ec8CE0A516A58f682002d07",
 		}, nil
 	case 31337: // Local/Hardhat
 		return &DefaultContractAddresses{
-			ReleaseManager: "0xd9Cb89F1993292dEC2F973934bC63B0f2A702776",
+			ReleaseManager:    "0xd9Cb89F1993292dEC2F973934bC63B0f2A702776",
+			AllocationManager: "0x42583067658071247ec8CE0A516A58f682002d07",
 		}, nil
 	case 1: // Mainnet
 		return &DefaultContractAddresses{
-			ReleaseManager: "0x0000000000000000000000000000000000000000", // To be updated
+			ReleaseManager:    "0x0000000000000000000000000000000000000000", // To be updated
+			AllocationManager: "0x948a420b8CC1d6BFd0B6087C2E7c344a2CD0bc39",
 		}, nil
 	default:
 		return nil, fmt.Errorf("default contract addresses not found for chain ID %d", chainID)
 	}
 }
 
+// Network is a chain flickr knows default contract addresses for
+type Network struct {
+	Name    string // Short name, e.g. "sepolia"
+	ChainID uint64
+}
+
+// Networks are the chains with default contract addresses
+var Networks = []Network{
+	{Name: "mainnet", ChainID: 1},
+	{Name: "sepolia", ChainID: 11155111},
+	{Name: "local", ChainID: 31337},
+}
+
+// String describes the network, e.g. "Sepolia Testnet (chain 11155111)"
+func (n Network) String() string {
+	return fmt.Sprintf("%s (chain %d)", getChainName(n.ChainID), n.ChainID)
+}
+
+// LookupNetwork returns the known network with the given short name
+func LookupNetwork(name string) (Network, error) {
+	names := make([]string, 0, len(Networks))
+	for _, n := range Networks {
+		if n.Name == name {
+			return n, nil
+		}
+		names = append(names, n.Name)
+	}
+	return Network{}, fmt.Errorf("unknown network %q (expected one of: %s)", name, strings.Join(names, ", "))
+}
+
 // GetChainID retrieves the chain ID from an RPC endpoint
 func GetChainID(rpcURL string) (uint64, error) {
 	client, err := ethclient.Dial(rpcURL)
@@ -72,6 +108,80 @@ func GetReleaseManagerAddress(rpcURL string, providedAddress string) (common.Add
 	return common.HexToAddress(defaults.ReleaseManager), nil
 }
 
+// ChainMismatchError is returned when the RPC URL serves another chain than
+// the one the context is pinned to
+type ChainMismatchError struct {
+	Expected uint64
+	Actual   uint64
+}
+
+func (e *ChainMismatchError) Error() string {
+	return fmt.Sprintf("RPC URL serves %s (chain %d), but the context is pinned to %s (chain %d); refusing to continue.\n"+
+		"Fix the RPC URL, or if the context really moved chains, pin it with 'flickr context set --chain-id %d'",
+		getChainName(e.Actual), e.Actual, getChainName(e.Expected), e.Expected, e.Actual)
+}
+
+// matchChainID returns a *ChainMismatchError if actual is not the expected
+// chain. An expected chain ID of 0 matches any chain.
+func matchChainID(expected, actual uint64) error {
+	if expected != 0 && expected != actual {
+		return &ChainMismatchError{Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// CheckChainID returns a *ChainMismatchError if the RPC URL does not serve
+// the expected chain. An expected chain ID of 0 accepts any chain without
+// contacting the RPC.
+func CheckChainID(rpcURL string, expected uint64) error {
+	if expected == 0 {
+		return nil
+	}
+
+	chainID, err := GetChainID(rpcURL)
+	if err != nil {
+		return err
+	}
+	return matchChainID(expected, chainID)
+}
+
+// ResolveRPCURL resolves the RPC URL of a command like config.ResolveRPCURL,
+// and checks that it serves the chain the context is pinned to
+func ResolveRPCURL(flags config.Flags, ctx *config.Context) (string, error) {
+	rpcURL, err := config.ResolveRPCURL(flags, ctx)
+	if err != nil {
+		return "", err
+	}
+	if ctx != nil {
+		if err := CheckChainID(rpcURL, ctx.ChainID); err != nil {
+			return "", err
+		}
+	}
+	return rpcURL, nil
+}
+
+// ResolveTarget resolves the operator set a command acts on from its flags,
+// the environment and the context, with the ReleaseManager address falling
+// back to the chain default. It fails if the RPC URL does not serve the chain
+// the context is pinned to.
+func ResolveTarget(flags config.Flags, ctx *config.Context) (*config.Target, common.Address, error) {
+	target, err := config.ResolveTarget(flags, ctx)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	if err := CheckChainID(target.RPCURL, target.ChainID); err != nil {
+		return nil, common.Address{}, err
+	}
+
+	rmAddr, err := GetReleaseManagerAddress(target.RPCURL, target.ReleaseManager)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get ReleaseManager address: %w", err)
+	}
+
+	return target, rmAddr, nil
+}
+
 // NetworkInfo contains information about the connected network
 type NetworkInfo struct {
 	ChainID        *big.Int
@@ -0,0 +1,315 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gofrs/flock"
+	"github.com/yourorg/flickr/internal/config"
+)
+
+const (
+	// droppedAfter is how long a transaction may be missing from the node
+	// before it is considered dropped rather than not yet propagated
+	droppedAfter = 2 * time.Minute
+
+	lockRetryDelay = 100 * time.Millisecond
+)
+
+// Transaction states reported by the nonce manager
+const (
+	TxPending = "pending"
+	TxDropped = "dropped"
+)
+
+// NonceClient is the part of an Ethereum client the nonce manager needs
+type NonceClient interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+}
+
+// PendingTx is a locally-issued transaction that has not been confirmed yet
+type PendingTx struct {
+	Nonce       uint64        `json:"nonce"`
+	Hash        common.Hash   `json:"hash"`
+	Description string        `json:"description"`
+	Raw         hexutil.Bytes `json:"raw"`
+	SentAt      time.Time     `json:"sentAt"`
+
+	// Status is TxPending or TxDropped, as of the last sync with the node
+	Status string `json:"-"`
+}
+
+// Transaction decodes the signed transaction
+func (p *PendingTx) Transaction() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(p.Raw); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction %s: %w", p.Hash.Hex(), err)
+	}
+	return tx, nil
+}
+
+// GapError reports a locally-issued transaction that the node no longer has.
+// Transactions with later nonces would be stuck behind it.
+type GapError struct {
+	Tx PendingTx
+}
+
+func (e *GapError) Error() string {
+	return fmt.Sprintf("transaction %s (%s) with nonce %d was dropped by the node; later transactions would be stuck behind it.\n"+
+		"Resend it with 'flickr tx replace --nonce %d' or cancel it with 'flickr tx cancel --nonce %d'",
+		e.Tx.Hash.Hex(), e.Tx.Description, e.Tx.Nonce, e.Tx.Nonce, e.Tx.Nonce)
+}
+
+// nonceState is the persisted state for one signer on one chain
+type nonceState struct {
+	Next    uint64      `json:"next"`
+	Pending []PendingTx `json:"pending"`
+}
+
+// NonceManager hands out nonces for locally-issued transactions, per signer
+// and chain. Nonces are tracked in files under a directory and guarded with a
+// file lock, so back-to-back and concurrent flickr invocations don't reuse a
+// nonce while a public RPC's pending pool lags behind.
+type NonceManager struct {
+	dir string
+	now func() time.Time
+}
+
+// NewNonceManager returns a nonce manager keeping its state in dir
+func NewNonceManager(dir string) *NonceManager {
+	return &NonceManager{dir: dir, now: time.Now}
+}
+
+// DefaultNonceDir returns the default nonce state directory, ~/.flickr/nonces
+func DefaultNonceDir() (string, error) {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "nonces"), nil
+}
+
+// Send reserves the next nonce for from and calls send with it. The
+// transaction send returns is recorded until it is confirmed. Send fails with
+// a GapError if an earlier transaction was dropped.
+func (m *NonceManager) Send(ctx context.Context, client NonceClient, chainID *big.Int, from common.Address, description string, send func(nonce uint64) (*types.Transaction, error)) (*types.Transaction, error) {
+	var tx *types.Transaction
+	err := m.withState(ctx, client, chainID, from, func(state *nonceState) error {
+		for _, p := range state.Pending {
+			if p.Status == TxDropped {
+				return &GapError{Tx: p}
+			}
+		}
+
+		var err error
+		tx, err = send(state.Next)
+		if err != nil {
+			return err
+		}
+		if tx.Nonce() != state.Next {
+			return fmt.Errorf("transaction was sent with nonce %d, expected %d", tx.Nonce(), state.Next)
+		}
+
+		if err := m.record(state, tx, description); err != nil {
+			return err
+		}
+		state.Next++
+		return nil
+	})
+	return tx, err
+}
+
+// Pending returns the unconfirmed transactions issued from from, with their
+// status on the node
+func (m *NonceManager) Pending(ctx context.Context, client NonceClient, chainID *big.Int, from common.Address) ([]PendingTx, error) {
+	var pending []PendingTx
+	err := m.withState(ctx, client, chainID, from, func(state *nonceState) error {
+		pending = append(pending, state.Pending...)
+		return nil
+	})
+	return pending, err
+}
+
+// Replace calls send with the unconfirmed transaction at nonce and records
+// the transaction it returns in its place
+func (m *NonceManager) Replace(ctx context.Context, client NonceClient, chainID *big.Int, from common.Address, nonce uint64, description string, send func(old PendingTx) (*types.Transaction, error)) (*types.Transaction, error) {
+	var tx *types.Transaction
+	err := m.withState(ctx, client, chainID, from, func(state *nonceState) error {
+		var old *PendingTx
+		for i := range state.Pending {
+			if state.Pending[i].Nonce == nonce {
+				old = &state.Pending[i]
+				break
+			}
+		}
+		if old == nil {
+			return fmt.Errorf("no unconfirmed transaction with nonce %d from %s", nonce, from.Hex())
+		}
+
+		var err error
+		tx, err = send(*old)
+		if err != nil {
+			return err
+		}
+		if tx.Nonce() != nonce {
+			return fmt.Errorf("replacement was sent with nonce %d, expected %d", tx.Nonce(), nonce)
+		}
+		return m.record(state, tx, description)
+	})
+	return tx, err
+}
+
+// record stores tx as the pending transaction for its nonce
+func (m *NonceManager) record(state *nonceState, tx *types.Transaction, description string) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	entry := PendingTx{
+		Nonce:       tx.Nonce(),
+		Hash:        tx.Hash(),
+		Description: description,
+		Raw:         raw,
+		SentAt:      m.now().UTC(),
+		Status:      TxPending,
+	}
+
+	for i := range state.Pending {
+		if state.Pending[i].Nonce == entry.Nonce {
+			state.Pending[i] = entry
+			return nil
+		}
+	}
+	state.Pending = append(state.Pending, entry)
+	sort.Slice(state.Pending, func(i, j int) bool { return state.Pending[i].Nonce < state.Pending[j].Nonce })
+	return nil
+}
+
+// withState locks and loads the state for from on chainID, syncs it with the
+// node, runs fn and saves the state
+func (m *NonceManager) withState(ctx context.Context, client NonceClient, chainID *big.Int, from common.Address, fn func(*nonceState) error) error {
+	dir := filepath.Join(m.dir, chainID.String())
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create nonce directory: %w", err)
+	}
+	path := filepath.Join(dir, strings.ToLower(from.Hex())+".json")
+
+	lock := flock.New(path + ".lock")
+	locked, err := lock.TryLockContext(ctx, lockRetryDelay)
+	if err != nil {
+		return fmt.Errorf("failed to lock nonce state: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("failed to lock nonce state %s", path)
+	}
+	defer lock.Unlock()
+
+	state, err := loadNonceState(path)
+	if err != nil {
+		return err
+	}
+	if err := m.sync(ctx, client, from, state); err != nil {
+		return err
+	}
+
+	if err := fn(state); err != nil {
+		// Keep what sync learned even if fn failed
+		if saveErr := saveNonceState(path, state); saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+		return err
+	}
+	return saveNonceState(path, state)
+}
+
+// sync drops confirmed transactions, moves the next nonce past any the node
+// knows about, and marks transactions the node no longer has as dropped
+func (m *NonceManager) sync(ctx context.Context, client NonceClient, from common.Address, state *nonceState) error {
+	confirmed, err := client.NonceAt(ctx, from, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+	pending, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+
+	unconfirmed := state.Pending[:0]
+	for _, p := range state.Pending {
+		if p.Nonce >= confirmed {
+			unconfirmed = append(unconfirmed, p)
+		}
+	}
+	state.Pending = unconfirmed
+
+	// Transactions sent by other tools, or state lost locally
+	if state.Next < pending {
+		state.Next = pending
+	}
+	if state.Next < confirmed {
+		state.Next = confirmed
+	}
+
+	for i := range state.Pending {
+		p := &state.Pending[i]
+		p.Status = TxPending
+		// The node has every nonce below its pending nonce
+		if p.Nonce < pending {
+			continue
+		}
+		_, _, err := client.TransactionByHash(ctx, p.Hash)
+		if errors.Is(err, ethereum.NotFound) && m.now().Sub(p.SentAt) > droppedAfter {
+			p.Status = TxDropped
+		} else if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("failed to look up transaction %s: %w", p.Hash.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func loadNonceState(path string) (*nonceState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &nonceState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nonce state: %w", err)
+	}
+
+	var state nonceState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse nonce state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+func saveNonceState(path string, state *nonceState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so a crash never leaves a truncated state file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write nonce state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write nonce state: %w", err)
+	}
+	return nil
+}
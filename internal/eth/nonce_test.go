@@ -0,0 +1,250 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testChainID = big.NewInt(17000)
+	testFrom    = common.HexToAddress("0x1111111111111111111111111111111111111111")
+)
+
+// fakeNonceClient is a node whose pool lags behind: it only knows the
+// transactions listed in known
+type fakeNonceClient struct {
+	mu        sync.Mutex
+	confirmed uint64
+	pending   uint64
+	known     map[common.Hash]bool
+}
+
+func (f *fakeNonceClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pending, nil
+}
+
+func (f *fakeNonceClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.confirmed, nil
+}
+
+func (f *fakeNonceClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.known[hash] {
+		return nil, true, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func newTestTx(nonce uint64, gasPrice int64) *types.Transaction {
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: big.NewInt(gasPrice),
+		Gas:      100000,
+		To:       &to,
+		Value:    new(big.Int),
+		Data:     []byte{0x01},
+	})
+}
+
+func sendTestTx(t *testing.T, m *NonceManager, client NonceClient) *types.Transaction {
+	t.Helper()
+	tx, err := m.Send(context.Background(), client, testChainID, testFrom, "test", func(nonce uint64) (*types.Transaction, error) {
+		return newTestTx(nonce, 1000), nil
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestNonceManager_Send(t *testing.T) {
+	m := NewNonceManager(t.TempDir())
+	client := &fakeNonceClient{confirmed: 5, pending: 5, known: map[common.Hash]bool{}}
+
+	// The node's pending nonce doesn't move between back-to-back sends
+	for want := uint64(5); want < 8; want++ {
+		tx := sendTestTx(t, m, client)
+		assert.Equal(t, want, tx.Nonce())
+	}
+
+	pending, err := m.Pending(context.Background(), client, testChainID, testFrom)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	for i, p := range pending {
+		assert.Equal(t, uint64(5+i), p.Nonce)
+		assert.Equal(t, TxPending, p.Status)
+	}
+
+	// Confirmed transactions are pruned
+	client.confirmed, client.pending = 7, 8
+	pending, err = m.Pending(context.Background(), client, testChainID, testFrom)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, uint64(7), pending[0].Nonce)
+}
+
+func TestNonceManager_Send_FollowsNode(t *testing.T) {
+	m := NewNonceManager(t.TempDir())
+	client := &fakeNonceClient{known: map[common.Hash]bool{}}
+
+	sendTestTx(t, m, client)
+
+	// Transactions sent by other tools move the next nonce forward
+	client.confirmed, client.pending = 3, 4
+	tx := sendTestTx(t, m, client)
+	assert.Equal(t, uint64(4), tx.Nonce())
+}
+
+func TestNonceManager_Send_Error(t *testing.T) {
+	m := NewNonceManager(t.TempDir())
+	client := &fakeNonceClient{known: map[common.Hash]bool{}}
+
+	_, err := m.Send(context.Background(), client, testChainID, testFrom, "test", func(nonce uint64) (*types.Transaction, error) {
+		return nil, errors.New("execution reverted")
+	})
+	require.Error(t, err)
+
+	// A failed send doesn't use up the nonce
+	tx := sendTestTx(t, m, client)
+	assert.Equal(t, uint64(0), tx.Nonce())
+}
+
+func TestNonceManager_Gap(t *testing.T) {
+	m := NewNonceManager(t.TempDir())
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	client := &fakeNonceClient{known: map[common.Hash]bool{}}
+
+	tx := sendTestTx(t, m, client)
+
+	// Not yet propagated
+	tx2 := sendTestTx(t, m, client)
+	client.known[tx2.Hash()] = true
+
+	// Missing for too long
+	now = now.Add(droppedAfter + time.Second)
+	pending, err := m.Pending(context.Background(), client, testChainID, testFrom)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, TxDropped, pending[0].Status)
+	assert.Equal(t, TxPending, pending[1].Status)
+
+	_, err = m.Send(context.Background(), client, testChainID, testFrom, "test", func(nonce uint64) (*types.Transaction, error) {
+		t.Fatal("send called despite a gap")
+		return nil, nil
+	})
+	var gapErr *GapError
+	require.ErrorAs(t, err, &gapErr)
+	assert.Equal(t, tx.Hash(), gapErr.Tx.Hash)
+	assert.Contains(t, err.Error(), "flickr tx replace --nonce 0")
+
+	// Replacing the dropped transaction closes the gap
+	replaced, err := m.Replace(context.Background(), client, testChainID, testFrom, 0, "replacement", func(old PendingTx) (*types.Transaction, error) {
+		oldTx, err := old.Transaction()
+		require.NoError(t, err)
+		assert.Equal(t, tx.Hash(), oldTx.Hash())
+		return newTestTx(old.Nonce, 2000), nil
+	})
+	require.NoError(t, err)
+	client.known[replaced.Hash()] = true
+
+	next := sendTestTx(t, m, client)
+	assert.Equal(t, uint64(2), next.Nonce())
+}
+
+func TestNonceManager_Replace_Unknown(t *testing.T) {
+	m := NewNonceManager(t.TempDir())
+	client := &fakeNonceClient{known: map[common.Hash]bool{}}
+
+	_, err := m.Replace(context.Background(), client, testChainID, testFrom, 3, "replacement", func(old PendingTx) (*types.Transaction, error) {
+		t.Fatal("send called for an unknown nonce")
+		return nil, nil
+	})
+	assert.ErrorContains(t, err, "no unconfirmed transaction with nonce 3")
+}
+
+func TestNonceManager_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	client := &fakeNonceClient{known: map[common.Hash]bool{}}
+
+	const n = 10
+	nonces := make(chan uint64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Separate managers, as in separate processes
+			m := NewNonceManager(dir)
+			tx, err := m.Send(context.Background(), client, testChainID, testFrom, "test", func(nonce uint64) (*types.Transaction, error) {
+				return newTestTx(nonce, 1000), nil
+			})
+			if assert.NoError(t, err) {
+				nonces <- tx.Nonce()
+			}
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	seen := map[uint64]bool{}
+	for nonce := range nonces {
+		assert.False(t, seen[nonce], "nonce %d used twice", nonce)
+		seen[nonce] = true
+	}
+	assert.Len(t, seen, n)
+}
+
+func TestBumpFees(t *testing.T) {
+	t.Run("legacy", func(t *testing.T) {
+		inner, err := bumpFees(newTestTx(1, 1000), 20, big.NewInt(100))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1200), inner.(*types.LegacyTx).GasPrice)
+
+		// Never below the current gas price
+		inner, err = bumpFees(newTestTx(1, 1000), 20, big.NewInt(5000))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(5000), inner.(*types.LegacyTx).GasPrice)
+	})
+
+	t.Run("dynamic fee", func(t *testing.T) {
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   testChainID,
+			Nonce:     1,
+			GasTipCap: big.NewInt(100),
+			GasFeeCap: big.NewInt(1000),
+			Gas:       100000,
+		})
+		inner, err := bumpFees(tx, 10, big.NewInt(1))
+		require.NoError(t, err)
+		dyn := inner.(*types.DynamicFeeTx)
+		assert.Equal(t, big.NewInt(110), dyn.GasTipCap)
+		assert.Equal(t, big.NewInt(1100), dyn.GasFeeCap)
+		assert.Equal(t, uint64(1), dyn.Nonce)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		inner, err := bumpFees(newTestTx(1, 1000), 20, big.NewInt(100))
+		require.NoError(t, err)
+		cancelTx(inner, testFrom)
+		legacy := inner.(*types.LegacyTx)
+		assert.Equal(t, testFrom, *legacy.To)
+		assert.Empty(t, legacy.Data)
+		assert.Equal(t, uint64(21000), legacy.Gas)
+		assert.Equal(t, int64(0), legacy.Value.Int64())
+	})
+}
@@ -0,0 +1,208 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/PermissionController"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ReleaseFunction is a ReleaseManager function an AVS can let appointees call
+// through EigenLayer's PermissionController
+type ReleaseFunction struct {
+	// Name identifies the function on the command line
+	Name string
+	// Signature is the Solidity signature the selector is derived from
+	Signature string
+}
+
+var (
+	// PublishReleaseFunction is ReleaseManager.publishRelease
+	PublishReleaseFunction = ReleaseFunction{
+		Name:      "publish-release",
+		Signature: "publishRelease((address,uint32),((bytes32,string)[],uint32))",
+	}
+	// PublishMetadataURIFunction is ReleaseManager.publishMetadataURI
+	PublishMetadataURIFunction = ReleaseFunction{
+		Name:      "publish-metadata-uri",
+		Signature: "publishMetadataURI((address,uint32),string)",
+	}
+
+	// ReleaseFunctions are the permissioned ReleaseManager functions
+	ReleaseFunctions = []ReleaseFunction{PublishReleaseFunction, PublishMetadataURIFunction}
+)
+
+// Method returns the Solidity function name
+func (f ReleaseFunction) Method() string {
+	return f.Signature[:strings.Index(f.Signature, "(")]
+}
+
+// Selector returns the 4-byte function selector
+func (f ReleaseFunction) Selector() [4]byte {
+	var selector [4]byte
+	copy(selector[:], crypto.Keccak256([]byte(f.Signature))[:4])
+	return selector
+}
+
+// LookupReleaseFunction returns the permissioned function with the given name
+func LookupReleaseFunction(name string) (ReleaseFunction, error) {
+	names := make([]string, len(ReleaseFunctions))
+	for i, f := range ReleaseFunctions {
+		if f.Name == name {
+			return f, nil
+		}
+		names[i] = f.Name
+	}
+	return ReleaseFunction{}, fmt.Errorf("unknown function %q (expected one of: %s)", name, strings.Join(names, ", "))
+}
+
+// permissionController returns the PermissionController the ReleaseManager
+// checks callers against
+func (c *Client) permissionController(ctx context.Context) (*PermissionController.PermissionController, error) {
+	if c.pcContract != nil {
+		return c.pcContract, nil
+	}
+
+	pcAddr, err := c.rmContract.PermissionController(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PermissionController address: %w", err)
+	}
+	pcContract, err := PermissionController.NewPermissionController(pcAddr, c.ethClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate PermissionController contract: %w", err)
+	}
+
+	c.pcContract = pcContract
+	return pcContract, nil
+}
+
+// CanCall reports whether caller may call fn on the ReleaseManager on behalf
+// of avs
+func (c *Client) CanCall(ctx context.Context, avs, caller common.Address, fn ReleaseFunction) (bool, error) {
+	pc, err := c.permissionController(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	allowed, err := pc.CanCall(&bind.CallOpts{Context: ctx}, avs, caller, c.contractAddr, fn.Selector())
+	if err != nil {
+		return false, fmt.Errorf("failed to check permission: %w", err)
+	}
+	return allowed, nil
+}
+
+// CheckPermission returns an error if the client's signer may not call fn on
+// behalf of avs, so transactions fail before they are sent rather than with
+// an opaque revert
+func (c *Client) CheckPermission(ctx context.Context, avs common.Address, fn ReleaseFunction) error {
+	if c.signer == nil {
+		return fmt.Errorf("signer required for checking permissions")
+	}
+
+	allowed, err := c.CanCall(ctx, avs, c.signer.Address(), fn)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("signer %s is not authorized to call %s for AVS %s.\n"+
+			"An AVS admin can grant it with 'flickr permissions grant --appointee %s --function %s'",
+			c.signer.Address().Hex(), fn.Method(), avs.Hex(), c.signer.Address().Hex(), fn.Name)
+	}
+	return nil
+}
+
+// GetAdmins returns the admins of avs. An AVS without admins is its own admin.
+func (c *Client) GetAdmins(ctx context.Context, avs common.Address) ([]common.Address, error) {
+	pc, err := c.permissionController(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	admins, err := pc.GetAdmins(&bind.CallOpts{Context: ctx}, avs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admins: %w", err)
+	}
+	return admins, nil
+}
+
+// GetAppointees returns the addresses allowed to call fn on behalf of avs,
+// besides its admins
+func (c *Client) GetAppointees(ctx context.Context, avs common.Address, fn ReleaseFunction) ([]common.Address, error) {
+	pc, err := c.permissionController(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	appointees, err := pc.GetAppointees(&bind.CallOpts{Context: ctx}, avs, c.contractAddr, fn.Selector())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointees: %w", err)
+	}
+	return appointees, nil
+}
+
+// SetAppointee allows appointee to call fn on behalf of avs. The client's
+// signer must be an admin of avs.
+func (c *Client) SetAppointee(ctx context.Context, avs, appointee common.Address, fn ReleaseFunction, gasLimit uint64) (*types.Transaction, error) {
+	if err := c.checkAdmin(ctx, avs); err != nil {
+		return nil, err
+	}
+	pc, err := c.permissionController(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.transact(ctx, gasLimit, fmt.Sprintf("grant %s to %s for %s", fn.Method(), appointee.Hex(), avs.Hex()),
+		func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return pc.SetAppointee(opts, avs, appointee, c.contractAddr, fn.Selector())
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set appointee: %w", err)
+	}
+	return tx, nil
+}
+
+// RemoveAppointee revokes appointee's permission to call fn on behalf of
+// avs. The client's signer must be an admin of avs.
+func (c *Client) RemoveAppointee(ctx context.Context, avs, appointee common.Address, fn ReleaseFunction, gasLimit uint64) (*types.Transaction, error) {
+	if err := c.checkAdmin(ctx, avs); err != nil {
+		return nil, err
+	}
+	pc, err := c.permissionController(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.transact(ctx, gasLimit, fmt.Sprintf("revoke %s from %s for %s", fn.Method(), appointee.Hex(), avs.Hex()),
+		func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return pc.RemoveAppointee(opts, avs, appointee, c.contractAddr, fn.Selector())
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove appointee: %w", err)
+	}
+	return tx, nil
+}
+
+// checkAdmin returns an error if the client's signer is not an admin of avs
+func (c *Client) checkAdmin(ctx context.Context, avs common.Address) error {
+	if c.signer == nil {
+		return fmt.Errorf("signer required for managing permissions")
+	}
+	pc, err := c.permissionController(ctx)
+	if err != nil {
+		return err
+	}
+
+	isAdmin, err := pc.IsAdmin(&bind.CallOpts{Context: ctx}, avs, c.signer.Address())
+	if err != nil {
+		return fmt.Errorf("failed to check admin: %w", err)
+	}
+	if !isAdmin {
+		return fmt.Errorf("signer %s is not an admin of AVS %s", c.signer.Address().Hex(), avs.Hex())
+	}
+	return nil
+}
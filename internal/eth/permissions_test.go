@@ -0,0 +1,48 @@
+package eth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseManagerABI is the part of the ReleaseManager ABI with the
+// permissioned functions
+const releaseManagerABI = `[
+  {"type":"function","name":"publishRelease","stateMutability":"nonpayable","inputs":[
+    {"name":"operatorSet","type":"tuple","components":[{"name":"avs","type":"address"},{"name":"id","type":"uint32"}]},
+    {"name":"release","type":"tuple","components":[
+      {"name":"artifacts","type":"tuple[]","components":[{"name":"digest","type":"bytes32"},{"name":"registry","type":"string"}]},
+      {"name":"upgradeByTime","type":"uint32"}]}],
+   "outputs":[{"name":"releaseId","type":"uint256"}]},
+  {"type":"function","name":"publishMetadataURI","stateMutability":"nonpayable","inputs":[
+    {"name":"operatorSet","type":"tuple","components":[{"name":"avs","type":"address"},{"name":"id","type":"uint32"}]},
+    {"name":"metadataURI","type":"string"}],
+   "outputs":[]}
+]`
+
+func TestReleaseFunction_Selector(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(releaseManagerABI))
+	require.NoError(t, err)
+
+	for _, fn := range ReleaseFunctions {
+		t.Run(fn.Name, func(t *testing.T) {
+			method, ok := parsed.Methods[fn.Method()]
+			require.True(t, ok)
+			selector := fn.Selector()
+			assert.Equal(t, method.ID, selector[:])
+		})
+	}
+}
+
+func TestLookupReleaseFunction(t *testing.T) {
+	fn, err := LookupReleaseFunction("publish-release")
+	require.NoError(t, err)
+	assert.Equal(t, "publishRelease", fn.Method())
+
+	_, err = LookupReleaseFunction("publishRelease")
+	assert.ErrorContains(t, err, "expected one of: publish-release, publish-metadata-uri")
+}
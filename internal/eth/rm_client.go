@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/PermissionController"
 	"github.com/Layr-Labs/eigenlayer-contracts/pkg/bindings/ReleaseManager"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -37,7 +38,9 @@ type Client struct {
 	rmContract   *ReleaseManager.ReleaseManager
 	contractAddr common.Address
 	rpcURL       string
-	signer       signer.Signer // Optional signer for transactions
+	signer       signer.Signer                              // Optional signer for transactions
+	nonces       *NonceManager                              // Tracks nonces of transactions sent by signer
+	pcContract   *PermissionController.PermissionController // Resolved on first use
 }
 
 // NewClient creates a new ReleaseManager client
@@ -168,7 +171,13 @@ func NewClientWithSigner(rpcURL string, contractAddr common.Address, sig signer.
 	if err != nil {
 		return nil, err
 	}
+	dir, err := DefaultNonceDir()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
 	client.signer = sig
+	client.nonces = NewNonceManager(dir)
 	return client, nil
 }
 
@@ -178,39 +187,6 @@ func (c *Client) PublishMetadataURI(ctx context.Context, avs common.Address, opS
 		return nil, fmt.Errorf("signer required for publishing metadata URI")
 	}
 
-	// Get chain ID
-	chainID, err := c.ethClient.ChainID(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get chain ID: %w", err)
-	}
-
-	// Get nonce
-	nonce, err := c.ethClient.PendingNonceAt(ctx, c.signer.Address())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
-	}
-
-	// Get gas price
-	gasPrice, err := c.ethClient.SuggestGasPrice(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
-	}
-
-	// Create transaction options
-	opts := &bind.TransactOpts{
-		From:     c.signer.Address(),
-		Nonce:    big.NewInt(int64(nonce)),
-		GasLimit: gasLimit,
-		GasPrice: gasPrice,
-		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
-			if address != c.signer.Address() {
-				return nil, fmt.Errorf("unexpected signer address")
-			}
-			return c.signer.SignTransaction(tx, chainID)
-		},
-		Context: ctx,
-	}
-
 	// Create OperatorSet struct
 	operatorSet := ReleaseManager.OperatorSet{
 		Avs: avs,
@@ -218,7 +194,10 @@ func (c *Client) PublishMetadataURI(ctx context.Context, avs common.Address, opS
 	}
 
 	// Call the contract to publish metadata URI
-	tx, err := c.rmContract.PublishMetadataURI(opts, operatorSet, uri)
+	tx, err := c.transact(ctx, gasLimit, fmt.Sprintf("publish metadata URI for %s/%d", avs.Hex(), opSetID),
+		func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return c.rmContract.PublishMetadataURI(opts, operatorSet, uri)
+		})
 	if err != nil {
 		return nil, fmt.Errorf("failed to publish metadata URI: %w", err)
 	}
@@ -232,39 +211,6 @@ func (c *Client) PushRelease(ctx context.Context, avs common.Address, opSetID ui
 		return nil, fmt.Errorf("signer required for pushing releases")
 	}
 
-	// Get chain ID
-	chainID, err := c.ethClient.ChainID(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get chain ID: %w", err)
-	}
-
-	// Get nonce
-	nonce, err := c.ethClient.PendingNonceAt(ctx, c.signer.Address())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
-	}
-
-	// Get gas price
-	gasPrice, err := c.ethClient.SuggestGasPrice(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
-	}
-
-	// Create transaction options
-	opts := &bind.TransactOpts{
-		From:     c.signer.Address(),
-		Nonce:    big.NewInt(int64(nonce)),
-		GasLimit: gasLimit,
-		GasPrice: gasPrice,
-		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
-			if address != c.signer.Address() {
-				return nil, fmt.Errorf("unexpected signer address")
-			}
-			return c.signer.SignTransaction(tx, chainID)
-		},
-		Context: ctx,
-	}
-
 	// Create OperatorSet struct
 	operatorSet := ReleaseManager.OperatorSet{
 		Avs: avs,
@@ -287,7 +233,10 @@ func (c *Client) PushRelease(ctx context.Context, avs common.Address, opSetID ui
 	}
 
 	// Call the contract to publish the release
-	tx, err := c.rmContract.PublishRelease(opts, operatorSet, release)
+	tx, err := c.transact(ctx, gasLimit, fmt.Sprintf("publish release for %s/%d", avs.Hex(), opSetID),
+		func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return c.rmContract.PublishRelease(opts, operatorSet, release)
+		})
 	if err != nil {
 		return nil, fmt.Errorf("failed to publish release: %w", err)
 	}
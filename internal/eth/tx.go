@@ -0,0 +1,180 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultFeeBump is the default fee increase, in percent, of replacement
+// transactions. Nodes require at least 10% to accept a replacement.
+const DefaultFeeBump = 20
+
+// transact sends a contract transaction from the client's signer, with a
+// nonce from the nonce manager
+func (c *Client) transact(ctx context.Context, gasLimit uint64, description string, call func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	// Get chain ID
+	chainID, err := c.ethClient.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	// Get gas price
+	gasPrice, err := c.ethClient.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	return c.nonces.Send(ctx, c.ethClient, chainID, c.signer.Address(), description, func(nonce uint64) (*types.Transaction, error) {
+		// Create transaction options
+		opts := &bind.TransactOpts{
+			From:     c.signer.Address(),
+			Nonce:    new(big.Int).SetUint64(nonce),
+			GasLimit: gasLimit,
+			GasPrice: gasPrice,
+			Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+				if address != c.signer.Address() {
+					return nil, fmt.Errorf("unexpected signer address")
+				}
+				return c.signer.SignTransaction(tx, chainID)
+			},
+			Context: ctx,
+		}
+		return call(opts)
+	})
+}
+
+// PendingTransactions returns the unconfirmed transactions sent by the
+// client's signer
+func (c *Client) PendingTransactions(ctx context.Context) ([]PendingTx, error) {
+	if c.signer == nil {
+		return nil, fmt.Errorf("signer required for listing transactions")
+	}
+
+	chainID, err := c.ethClient.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	return c.nonces.Pending(ctx, c.ethClient, chainID, c.signer.Address())
+}
+
+// ReplaceTransaction resends the unconfirmed transaction at nonce with fees
+// bumped by bumpPercent
+func (c *Client) ReplaceTransaction(ctx context.Context, nonce uint64, bumpPercent int) (*types.Transaction, error) {
+	return c.replace(ctx, nonce, bumpPercent, false)
+}
+
+// CancelTransaction replaces the unconfirmed transaction at nonce with an
+// empty transfer to the signer itself, with fees bumped by bumpPercent
+func (c *Client) CancelTransaction(ctx context.Context, nonce uint64, bumpPercent int) (*types.Transaction, error) {
+	return c.replace(ctx, nonce, bumpPercent, true)
+}
+
+func (c *Client) replace(ctx context.Context, nonce uint64, bumpPercent int, cancel bool) (*types.Transaction, error) {
+	if c.signer == nil {
+		return nil, fmt.Errorf("signer required for replacing transactions")
+	}
+	if bumpPercent < 10 {
+		return nil, fmt.Errorf("fee bump must be at least 10%%, got %d%%", bumpPercent)
+	}
+
+	chainID, err := c.ethClient.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	gasPrice, err := c.ethClient.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	from := c.signer.Address()
+	description := "replacement"
+	if cancel {
+		description = "cancellation"
+	}
+
+	return c.nonces.Replace(ctx, c.ethClient, chainID, from, nonce, description, func(old PendingTx) (*types.Transaction, error) {
+		oldTx, err := old.Transaction()
+		if err != nil {
+			return nil, err
+		}
+
+		inner, err := bumpFees(oldTx, bumpPercent, gasPrice)
+		if err != nil {
+			return nil, err
+		}
+		if cancel {
+			cancelTx(inner, from)
+		}
+
+		signed, err := c.signer.SignTransaction(types.NewTx(inner), chainID)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.ethClient.SendTransaction(ctx, signed); err != nil {
+			return nil, fmt.Errorf("failed to send %s: %w", description, err)
+		}
+		return signed, nil
+	})
+}
+
+// bumpFees returns a copy of tx with its fees raised by bumpPercent, and at
+// least to the current gas price
+func bumpFees(tx *types.Transaction, bumpPercent int, gasPrice *big.Int) (types.TxData, error) {
+	bump := func(fee *big.Int) *big.Int {
+		bumped := new(big.Int).Mul(fee, big.NewInt(int64(100+bumpPercent)))
+		bumped.Div(bumped, big.NewInt(100))
+		// Round up so small fees still increase
+		if bumped.Cmp(fee) <= 0 {
+			bumped.Add(fee, big.NewInt(1))
+		}
+		return bumped
+	}
+	atLeast := func(fee, floor *big.Int) *big.Int {
+		if fee.Cmp(floor) < 0 {
+			return new(big.Int).Set(floor)
+		}
+		return fee
+	}
+
+	switch tx.Type() {
+	case types.LegacyTxType:
+		return &types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: atLeast(bump(tx.GasPrice()), gasPrice),
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		}, nil
+	case types.DynamicFeeTxType:
+		tip := bump(tx.GasTipCap())
+		return &types.DynamicFeeTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  tip,
+			GasFeeCap:  atLeast(atLeast(bump(tx.GasFeeCap()), gasPrice), tip),
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("cannot replace transaction type %d", tx.Type())
+	}
+}
+
+// cancelTx turns a transaction into an empty transfer to from
+func cancelTx(inner types.TxData, from common.Address) {
+	switch tx := inner.(type) {
+	case *types.LegacyTx:
+		tx.To, tx.Value, tx.Data, tx.Gas = &from, new(big.Int), nil, 21000
+	case *types.DynamicFeeTx:
+		tx.To, tx.Value, tx.Data, tx.Gas, tx.AccessList = &from, new(big.Int), nil, 21000, nil
+	}
+}
@@ -0,0 +1,139 @@
+package keys
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yourorg/flickr/internal/config"
+)
+
+// scryptN and scryptP are go-ethereum's standard keystore parameters. Tests
+// may lower them.
+var (
+	scryptN = keystore.StandardScryptN
+	scryptP = keystore.StandardScryptP
+)
+
+// Key is a key file in the keystore directory
+type Key struct {
+	Address common.Address
+	Path    string
+}
+
+// Keystore is a directory of encrypted key files in go-ethereum's (Web3
+// Secret Storage) format
+type Keystore struct {
+	dir string
+	ks  *keystore.KeyStore
+}
+
+// Dir returns the default keystore directory, ~/.flickr/keystore
+func Dir() (string, error) {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "keystore"), nil
+}
+
+// Open opens a keystore directory, creating it if needed
+func Open(dir string) (*Keystore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+	return &Keystore{dir: dir, ks: keystore.NewKeyStore(dir, scryptN, scryptP)}, nil
+}
+
+// New generates a key and stores it encrypted with password
+func (k *Keystore) New(password string) (Key, error) {
+	if password == "" {
+		return Key{}, fmt.Errorf("password must not be empty")
+	}
+	account, err := k.ks.NewAccount(password)
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to create key: %w", err)
+	}
+	return toKey(account), nil
+}
+
+// Import stores a hex-encoded private key encrypted with password
+func (k *Keystore) Import(privateKeyHex, password string) (Key, error) {
+	if password == "" {
+		return Key{}, fmt.Errorf("password must not be empty")
+	}
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(privateKeyHex), "0x"))
+	if err != nil {
+		return Key{}, fmt.Errorf("invalid private key: %w", err)
+	}
+	account, err := k.ks.ImportECDSA(privateKey, password)
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to import key: %w", err)
+	}
+	return toKey(account), nil
+}
+
+// List returns the keys in the keystore, sorted by path
+func (k *Keystore) List() []Key {
+	accounts := k.ks.Accounts()
+	keys := make([]Key, 0, len(accounts))
+	for _, account := range accounts {
+		keys = append(keys, toKey(account))
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Path < keys[j].Path })
+	return keys
+}
+
+// Find returns the key for an address or a key file path
+func (k *Keystore) Find(addressOrPath string) (Key, error) {
+	if common.IsHexAddress(addressOrPath) {
+		address := common.HexToAddress(addressOrPath)
+		var matches []Key
+		for _, key := range k.List() {
+			if key.Address == address {
+				matches = append(matches, key)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return Key{}, fmt.Errorf("no key for %s in %s", address.Hex(), k.dir)
+		case 1:
+			return matches[0], nil
+		default:
+			return Key{}, fmt.Errorf("multiple keys for %s in %s; pass the key file path instead", address.Hex(), k.dir)
+		}
+	}
+
+	path, err := filepath.Abs(addressOrPath)
+	if err != nil {
+		return Key{}, err
+	}
+	for _, key := range k.List() {
+		if key.Path == path || filepath.Base(key.Path) == addressOrPath {
+			return key, nil
+		}
+	}
+	return Key{}, fmt.Errorf("no key %s in %s", addressOrPath, k.dir)
+}
+
+// Export returns the key file of key re-encrypted with newPassword
+func (k *Keystore) Export(key Key, password, newPassword string) ([]byte, error) {
+	if newPassword == "" {
+		return nil, fmt.Errorf("password must not be empty")
+	}
+	data, err := k.ks.Export(accounts.Account{Address: key.Address, URL: accounts.URL{Scheme: keystore.KeyStoreScheme, Path: key.Path}}, password, newPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export key: %w", err)
+	}
+	return data, nil
+}
+
+func toKey(account accounts.Account) Key {
+	return Key{Address: account.Address, Path: account.URL.Path}
+}
@@ -0,0 +1,103 @@
+package keys
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/signer"
+)
+
+const (
+	testPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	testAddress    = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+)
+
+func openTestKeystore(t *testing.T) (*Keystore, string) {
+	t.Helper()
+	defaultN, defaultP := scryptN, scryptP
+	scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	t.Cleanup(func() { scryptN, scryptP = defaultN, defaultP })
+
+	dir := filepath.Join(t.TempDir(), "keystore")
+	ks, err := Open(dir)
+	require.NoError(t, err)
+	return ks, dir
+}
+
+func TestKeystore(t *testing.T) {
+	ks, dir := openTestKeystore(t)
+
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	assert.Empty(t, ks.List())
+
+	t.Run("New", func(t *testing.T) {
+		key, err := ks.New("hunter2")
+		require.NoError(t, err)
+		assert.Equal(t, dir, filepath.Dir(key.Path))
+
+		// The key file is usable by the keystore signer
+		sig, err := signer.NewKeystoreSigner(key.Path, "hunter2")
+		require.NoError(t, err)
+		assert.Equal(t, key.Address, sig.Address())
+
+		_, err = ks.New("")
+		assert.Error(t, err)
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		key, err := ks.Import(testPrivateKey, "hunter2")
+		require.NoError(t, err)
+		assert.Equal(t, testAddress, key.Address.Hex())
+
+		_, err = ks.Import("not-a-key", "hunter2")
+		assert.ErrorContains(t, err, "invalid private key")
+
+		// The same key cannot be imported twice
+		_, err = ks.Import(testPrivateKey, "hunter2")
+		assert.Error(t, err)
+	})
+
+	t.Run("List and find", func(t *testing.T) {
+		keys := ks.List()
+		require.Len(t, keys, 2)
+		assert.Less(t, keys[0].Path, keys[1].Path)
+
+		byAddress, err := ks.Find(testAddress)
+		require.NoError(t, err)
+		assert.Equal(t, testAddress, byAddress.Address.Hex())
+
+		byPath, err := ks.Find(byAddress.Path)
+		require.NoError(t, err)
+		assert.Equal(t, byAddress, byPath)
+
+		byName, err := ks.Find(filepath.Base(byAddress.Path))
+		require.NoError(t, err)
+		assert.Equal(t, byAddress, byName)
+
+		_, err = ks.Find("0x000000000000000000000000000000000000dEaD")
+		assert.ErrorContains(t, err, "no key")
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		key, err := ks.Find(testAddress)
+		require.NoError(t, err)
+
+		_, err = ks.Export(key, "wrong", "new-password")
+		assert.Error(t, err)
+
+		data, err := ks.Export(key, "hunter2", "new-password")
+		require.NoError(t, err)
+
+		_, err = keystore.DecryptKey(data, "hunter2")
+		assert.Error(t, err)
+		decrypted, err := keystore.DecryptKey(data, "new-password")
+		require.NoError(t, err)
+		assert.Equal(t, testAddress, decrypted.Address.Hex())
+	})
+}
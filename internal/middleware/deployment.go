@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+	"go.uber.org/zap"
+)
+
+// DeploymentFlag is the flag of commands acting on the deployments of a
+// context, for selecting some of them
+func DeploymentFlag() cli.Flag {
+	return &cli.StringSliceFlag{
+		Name:  "deployment",
+		Usage: "Deployment of the context to act on (repeatable; defaults to all deployments)",
+	}
+}
+
+// ForEachDeployment calls fn with the context of every deployment selected
+// with --deployment, or every deployment of currentCtx if none is selected.
+// A context without deployments, or an operator set given with --avs or
+// --operator-set, is acted on once with an empty deployment name. Every
+// deployment is attempted; the failures are returned together.
+func ForEachDeployment(c *cli.Context, currentCtx *config.Context, fn func(name string, ctx *config.Context) error) error {
+	selected := c.StringSlice("deployment")
+
+	if c.IsSet(config.FlagAVS) || c.IsSet(config.FlagOperatorSet) {
+		if len(selected) > 0 {
+			return fmt.Errorf("--deployment cannot be combined with --%s or --%s", config.FlagAVS, config.FlagOperatorSet)
+		}
+		return fn("", currentCtx)
+	}
+	if len(currentCtx.Deployments) == 0 {
+		if len(selected) > 0 {
+			return fmt.Errorf("the context has no deployments; add them with 'flickr context deployment add'")
+		}
+		return fn("", currentCtx)
+	}
+
+	names, err := currentCtx.SelectDeployments(selected)
+	if err != nil {
+		return err
+	}
+
+	log := GetLogger(c)
+	var errs []error
+	for _, name := range names {
+		ctx, err := currentCtx.Deployment(name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(c.App.Writer, "\n=== Deployment '%s' ===\n", name)
+		if err := fn(name, ctx); err != nil {
+			log.Error("Deployment failed", zap.String("deployment", name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("deployment '%s': %w", name, err))
+		}
+	}
+
+	if len(errs) == 1 && len(names) == 1 {
+		return errs[0]
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d deployments failed: %w", len(errs), len(names), errors.Join(errs...))
+	}
+	return nil
+}
@@ -2,12 +2,15 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/urfave/cli/v2"
 	"github.com/yourorg/flickr/internal/config"
 	"github.com/yourorg/flickr/internal/logger"
+	"github.com/yourorg/flickr/internal/signer"
 	"go.uber.org/zap"
 )
 
@@ -40,21 +43,42 @@ func ConfigBeforeFunc(c *cli.Context) error {
 		}
 	}
 
-	// Load config
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		l.Error("Failed to load config", zap.Error(err))
-		// Create empty config if it doesn't exist
+	if path := c.String("config"); path != "" {
+		config.SetConfigPath(path)
+	}
+
+	// Load config, with the project config of the working directory layered
+	// over the current context
+	cfg, effective, err := config.LoadEffective()
+	switch {
+	case err == nil:
+	case errors.Is(err, config.ErrNoHome):
+		// FLICKR_* variables still apply, e.g. in containers without a home
 		cfg = &config.Config{
 			Contexts: make(map[string]*config.Context),
 		}
+		if effective, err = config.Resolve(cfg, "", nil, os.Environ()); err != nil {
+			return fmt.Errorf("failed to read settings from the environment: %w", err)
+		}
+	case cfg != nil && isContextCommand(c):
+		// Context commands can still fix the context a project config or
+		// the environment is refused for
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	default:
+		// Never carry on with an empty config in place of one that failed
+		// to load
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Get current context
 	var currentCtx *config.Context
-	if cfg.CurrentContext != "" {
-		if ctx, exists := cfg.Contexts[cfg.CurrentContext]; exists {
-			currentCtx = ctx
+	if effective != nil {
+		currentCtx = effective.Context
+
+		// A project config is usually checked out with the code; say when
+		// it changes what is run or where it comes from
+		if len(effective.ProjectOverrides) > 0 {
+			fmt.Fprintf(os.Stderr, "Note: %s overrides %s of the context\n", effective.ProjectPath, strings.Join(effective.ProjectOverrides, ", "))
 		}
 	}
 
@@ -63,10 +87,14 @@ func ConfigBeforeFunc(c *cli.Context) error {
 		// For non-context commands, we need a context
 		l.Error("No context configured")
 		fmt.Fprintf(os.Stderr, "\nError: No context configured\n\n")
-		fmt.Fprintf(os.Stderr, "To create a context, run:\n")
+		fmt.Fprintf(os.Stderr, "To set up a context step by step, run:\n")
+		fmt.Fprintf(os.Stderr, "  flickr init\n\n")
+		fmt.Fprintf(os.Stderr, "To create an empty context, run:\n")
 		fmt.Fprintf(os.Stderr, "  flickr context create --name default --use\n\n")
 		fmt.Fprintf(os.Stderr, "To list available contexts:\n")
 		fmt.Fprintf(os.Stderr, "  flickr context list\n\n")
+		fmt.Fprintf(os.Stderr, "Or configure it from the environment:\n")
+		fmt.Fprintf(os.Stderr, "  export %s=0x... %s=https://...\n\n", config.EnvAVSAddress, config.EnvRPCURL)
 		return fmt.Errorf("no context configured")
 	}
 
@@ -151,7 +179,7 @@ func isContextCommand(c *cli.Context) bool {
 		return false
 	}
 	cmd := c.Args().Get(0)
-	return cmd == "context"
+	return cmd == "context" || cmd == "secrets" || cmd == "keys" || cmd == "init"
 }
 
 func isHelpCommand(c *cli.Context) bool {
@@ -160,4 +188,36 @@ func isHelpCommand(c *cli.Context) bool {
 	}
 	cmd := c.Args().Get(0)
 	return cmd == "help" || cmd == "version"
+}
+
+// ConfigFlag is the global flag selecting the config file, also read from
+// FLICKR_CONFIG
+func ConfigFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "config",
+		Usage:   "Path of the config file (default: ~/.flickr/config.json, or $XDG_CONFIG_HOME/flickr/config.json)",
+		EnvVars: []string{config.EnvConfig},
+	}
+}
+
+// KeystorePasswordFileFlag is the flag of commands that sign, for supplying
+// the keystore password from a file at runtime
+func KeystorePasswordFileFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "keystore-password-file",
+		Usage: "Read the keystore password from a file (overrides the context and " + signer.EnvKeystorePassword + ")",
+	}
+}
+
+// GetSigner creates the signer for the current context. Signer material from
+// --keystore-password-file and the environment takes precedence over the
+// context, and the source used is logged.
+func GetSigner(c *cli.Context, currentCtx *config.Context) (signer.Signer, error) {
+	log := GetLogger(c)
+	return signer.FromContextWithOptions(currentCtx, signer.Options{
+		KeystorePasswordFile: c.String("keystore-password-file"),
+		Report: func(material, source string) {
+			log.Info("Using "+material, zap.String("source", source))
+		},
+	})
 }
\ No newline at end of file
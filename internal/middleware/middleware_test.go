@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"github.com/yourorg/flickr/internal/config"
+)
+
+// runWithConfig runs command through ConfigBeforeFunc and returns the
+// context the command got
+func runWithConfig(t *testing.T, command string) (*config.Context, error) {
+	var got *config.Context
+	action := func(c *cli.Context) error {
+		got, _ = GetCurrentContext(c)
+		return nil
+	}
+	app := &cli.App{
+		Writer:    io.Discard,
+		ErrWriter: io.Discard,
+		Before:    ConfigBeforeFunc,
+		Commands: []*cli.Command{
+			{Name: "run", Action: action},
+			{Name: "context", Action: action},
+		},
+	}
+	err := app.RunContext(context.Background(), []string{"flickr", command})
+	return got, err
+}
+
+func TestConfigBeforeFunc_LoadErrors(t *testing.T) {
+	t.Setenv(config.EnvContext, "")
+	t.Setenv(config.EnvAVSAddress, "")
+
+	t.Run("Invalid config is not replaced by an empty one", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"version": 1, "contexts": {"a": {"avsAddress": "nope"}}}`), 0600))
+		config.SetConfigPath(path)
+		t.Cleanup(func() { config.SetConfigPath("") })
+
+		for _, command := range []string{"run", "context"} {
+			_, err := runWithConfig(t, command)
+			assert.ErrorContains(t, err, "failed to load config")
+		}
+	})
+
+	t.Run("Context commands run despite an invalid environment", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"version": 1, "currentContext": "a", "contexts": {"a": {}}}`), 0600))
+		config.SetConfigPath(path)
+		t.Cleanup(func() { config.SetConfigPath("") })
+		t.Setenv(config.EnvContext, "missing")
+
+		_, err := runWithConfig(t, "run")
+		assert.ErrorContains(t, err, "context 'missing' (set in env FLICKR_CONTEXT) not found")
+
+		ctx, err := runWithConfig(t, "context")
+		require.NoError(t, err)
+		assert.Equal(t, &config.Context{}, ctx)
+	})
+
+	t.Run("Environment without a home directory", func(t *testing.T) {
+		config.SetConfigPath("")
+		t.Setenv(config.EnvConfig, "")
+		t.Setenv("HOME", "")
+		t.Setenv(config.EnvAVSAddress, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+		ctx, err := runWithConfig(t, "run")
+		require.NoError(t, err)
+		assert.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", ctx.AVSAddress)
+	})
+}
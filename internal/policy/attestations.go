@@ -0,0 +1,213 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Attestations are the in-toto attestations attached to an image
+type Attestations struct {
+	Provenance []Provenance
+	SBOM       []SBOM
+}
+
+// Provenance is the subset of a SLSA provenance predicate used by policies
+type Provenance struct {
+	Platform  string
+	BuilderID string
+	SourceURI string
+}
+
+// SBOM is the package list of an SPDX or CycloneDX document
+type SBOM struct {
+	Platform string
+	Packages []Package
+}
+
+// Package is a package listed in an SBOM
+type Package struct {
+	Name    string
+	Version string
+}
+
+// ParseImagetools parses the output of
+// 'docker buildx imagetools inspect --format "{{json .Provenance}}"' and
+// '{{json .SBOM}}'. Multi-platform images produce one entry per platform.
+func ParseImagetools(provenance, sbom []byte) (*Attestations, error) {
+	att := &Attestations{}
+
+	provByPlatform, err := splitPlatforms(provenance, "SLSA")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse provenance: %w", err)
+	}
+	for _, platform := range sortedKeys(provByPlatform) {
+		prov, err := ParseProvenance(provByPlatform[platform])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse provenance for %s: %w", platform, err)
+		}
+		prov.Platform = platform
+		att.Provenance = append(att.Provenance, prov)
+	}
+
+	sbomByPlatform, err := splitPlatforms(sbom, "SPDX")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SBOM: %w", err)
+	}
+	for _, platform := range sortedKeys(sbomByPlatform) {
+		doc, err := ParseSBOM(sbomByPlatform[platform])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SBOM for %s: %w", platform, err)
+		}
+		doc.Platform = platform
+		att.SBOM = append(att.SBOM, doc)
+	}
+
+	return att, nil
+}
+
+// splitPlatforms unwraps imagetools output keyed by predicate type
+// ({"SLSA": ...}) or by platform ({"linux/amd64": {"SLSA": ...}})
+func splitPlatforms(data []byte, key string) (map[string]json.RawMessage, error) {
+	out := make(map[string]json.RawMessage)
+	if len(data) == 0 || string(data) == "null" {
+		return out, nil
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, err
+	}
+
+	if raw, ok := top[key]; ok {
+		if string(raw) != "null" {
+			out[""] = raw
+		}
+		return out, nil
+	}
+
+	for platform, raw := range top {
+		var inner map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &inner); err != nil {
+			return nil, err
+		}
+		if v, ok := inner[key]; ok && string(v) != "null" {
+			out[platform] = v
+		}
+	}
+	return out, nil
+}
+
+// ParseProvenance extracts the builder and source from a SLSA v0.2 or v1
+// provenance predicate, or an in-toto statement wrapping one
+func ParseProvenance(data []byte) (Provenance, error) {
+	var doc struct {
+		Predicate json.RawMessage `json:"predicate"`
+
+		// SLSA v0.2
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+		Invocation struct {
+			ConfigSource struct {
+				URI string `json:"uri"`
+			} `json:"configSource"`
+		} `json:"invocation"`
+
+		// SLSA v1
+		RunDetails struct {
+			Builder struct {
+				ID string `json:"id"`
+			} `json:"builder"`
+		} `json:"runDetails"`
+		BuildDefinition struct {
+			ExternalParameters struct {
+				ConfigSource struct {
+					URI string `json:"uri"`
+				} `json:"configSource"`
+			} `json:"externalParameters"`
+		} `json:"buildDefinition"`
+
+		// BuildKit records the VCS source in its metadata
+		Metadata map[string]json.RawMessage `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Provenance{}, err
+	}
+
+	if len(doc.Predicate) > 0 {
+		return ParseProvenance(doc.Predicate)
+	}
+
+	prov := Provenance{
+		BuilderID: firstNonEmpty(doc.Builder.ID, doc.RunDetails.Builder.ID),
+		SourceURI: firstNonEmpty(doc.Invocation.ConfigSource.URI, doc.BuildDefinition.ExternalParameters.ConfigSource.URI),
+	}
+
+	if raw, ok := doc.Metadata["https://mobyproject.org/buildkit@v1#metadata"]; ok && prov.SourceURI == "" {
+		var bk struct {
+			VCS struct {
+				Source string `json:"source"`
+			} `json:"vcs"`
+		}
+		if err := json.Unmarshal(raw, &bk); err == nil {
+			prov.SourceURI = bk.VCS.Source
+		}
+	}
+
+	return prov, nil
+}
+
+// ParseSBOM extracts the package list from an SPDX or CycloneDX document,
+// or an in-toto statement wrapping one
+func ParseSBOM(data []byte) (SBOM, error) {
+	var doc struct {
+		Predicate json.RawMessage `json:"predicate"`
+
+		// SPDX
+		Packages []struct {
+			Name        string `json:"name"`
+			VersionInfo string `json:"versionInfo"`
+		} `json:"packages"`
+
+		// CycloneDX
+		Components []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return SBOM{}, err
+	}
+
+	if len(doc.Predicate) > 0 {
+		return ParseSBOM(doc.Predicate)
+	}
+
+	var sbom SBOM
+	for _, pkg := range doc.Packages {
+		sbom.Packages = append(sbom.Packages, Package{Name: pkg.Name, Version: pkg.VersionInfo})
+	}
+	for _, c := range doc.Components {
+		sbom.Packages = append(sbom.Packages, Package{Name: c.Name, Version: c.Version})
+	}
+	return sbom, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,243 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// Policy describes the supply-chain requirements a release artifact must meet
+type Policy struct {
+	// RequiredBuilder is the SLSA builder ID that must have produced the image
+	RequiredBuilder string `yaml:"requiredBuilder,omitempty"`
+	// RequiredSource is the source repository the image must be built from
+	RequiredSource string `yaml:"requiredSource,omitempty"`
+	// ForbiddenPackages are packages that must not appear in the SBOM,
+	// either as a bare name or as name@version
+	ForbiddenPackages []string `yaml:"forbiddenPackages,omitempty"`
+
+	// Approvals requires a quorum of signed maintainer approvals of the
+	// release before it runs
+	Approvals *ApprovalPolicy `yaml:"approvals,omitempty"`
+}
+
+// ApprovalPolicy requires Threshold of the Approvers to have signed an
+// approval of the release
+type ApprovalPolicy struct {
+	Threshold int      `yaml:"threshold"`
+	Approvers []string `yaml:"approvers"`
+	// Source is a directory or URL holding the approvals. When empty, the
+	// "approvals" URL of the operator set's metadata is used.
+	Source string `yaml:"source,omitempty"`
+}
+
+// ApproverAddresses returns the approvers as addresses
+func (a *ApprovalPolicy) ApproverAddresses() []common.Address {
+	addresses := make([]common.Address, 0, len(a.Approvers))
+	for _, approver := range a.Approvers {
+		addresses = append(addresses, common.HexToAddress(approver))
+	}
+	return addresses
+}
+
+func (a *ApprovalPolicy) validate() error {
+	seen := make(map[common.Address]bool)
+	for _, approver := range a.Approvers {
+		if !common.IsHexAddress(approver) {
+			return fmt.Errorf("invalid approver address %q", approver)
+		}
+		address := common.HexToAddress(approver)
+		if seen[address] {
+			return fmt.Errorf("duplicate approver %s", address.Hex())
+		}
+		seen[address] = true
+	}
+	if a.Threshold < 1 || a.Threshold > len(a.Approvers) {
+		return fmt.Errorf("approval threshold must be between 1 and the number of approvers (%d), got %d", len(a.Approvers), a.Threshold)
+	}
+	return nil
+}
+
+// Load reads a policy from a YAML file
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var p Policy
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+
+	if p.Approvals != nil {
+		if err := p.Approvals.validate(); err != nil {
+			return nil, fmt.Errorf("invalid approvals in policy file %s: %w", path, err)
+		}
+		// Approval directories are relative to the policy file
+		source := p.Approvals.Source
+		if source != "" && !strings.Contains(source, "://") && !filepath.IsAbs(source) {
+			p.Approvals.Source = filepath.Join(filepath.Dir(path), source)
+		}
+	}
+
+	return &p, nil
+}
+
+// Check is the outcome of a single policy rule
+type Check struct {
+	Name   string
+	Passed bool
+	Detail string
+}
+
+// Result is the outcome of evaluating a policy against an artifact
+type Result struct {
+	Reference string
+	Checks    []Check
+}
+
+// Passed reports whether every check passed
+func (r *Result) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failures returns the checks that did not pass
+func (r *Result) Failures() []Check {
+	var failed []Check
+	for _, check := range r.Checks {
+		if !check.Passed {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
+// Print writes a human readable summary of the result
+func (r *Result) Print(w io.Writer) {
+	fmt.Fprintf(w, "Policy checks for %s:\n", r.Reference)
+	if len(r.Checks) == 0 {
+		fmt.Fprintf(w, "  (no rules configured)\n")
+		return
+	}
+	for _, check := range r.Checks {
+		status := "PASS"
+		if !check.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(w, "  [%s] %s: %s\n", status, check.Name, check.Detail)
+	}
+}
+
+// HasAttestationRules reports whether the policy has rules that need the
+// artifact's attestations
+func (p *Policy) HasAttestationRules() bool {
+	return p.RequiredBuilder != "" || p.RequiredSource != "" || len(p.ForbiddenPackages) > 0
+}
+
+// Evaluate checks attestations against the policy
+func (p *Policy) Evaluate(reference string, att *Attestations) *Result {
+	result := &Result{Reference: reference}
+	if att == nil {
+		att = &Attestations{}
+	}
+
+	if p.RequiredBuilder != "" {
+		result.Checks = append(result.Checks, p.checkProvenance(att, "builder", p.RequiredBuilder,
+			func(prov Provenance) string { return prov.BuilderID },
+			func(got, want string) bool { return got == want }))
+	}
+
+	if p.RequiredSource != "" {
+		result.Checks = append(result.Checks, p.checkProvenance(att, "source", p.RequiredSource,
+			func(prov Provenance) string { return prov.SourceURI },
+			func(got, want string) bool { return normalizeSource(got) == normalizeSource(want) }))
+	}
+
+	if len(p.ForbiddenPackages) > 0 {
+		result.Checks = append(result.Checks, p.checkPackages(att))
+	}
+
+	return result
+}
+
+func (p *Policy) checkProvenance(att *Attestations, name, want string, field func(Provenance) string, match func(got, want string) bool) Check {
+	check := Check{Name: name}
+	if len(att.Provenance) == 0 {
+		check.Detail = "no provenance attestation found"
+		return check
+	}
+
+	for _, prov := range att.Provenance {
+		got := field(prov)
+		if !match(got, want) {
+			if got == "" {
+				got = "<none>"
+			}
+			check.Detail = fmt.Sprintf("%s%s is %s, want %s", name, platformSuffix(prov.Platform), got, want)
+			return check
+		}
+	}
+
+	check.Passed = true
+	check.Detail = want
+	return check
+}
+
+func (p *Policy) checkPackages(att *Attestations) Check {
+	check := Check{Name: "forbidden packages"}
+	if len(att.SBOM) == 0 {
+		check.Detail = "no SBOM attestation found"
+		return check
+	}
+
+	var found []string
+	for _, sbom := range att.SBOM {
+		for _, pkg := range sbom.Packages {
+			for _, forbidden := range p.ForbiddenPackages {
+				if forbidden == pkg.Name || forbidden == pkg.Name+"@"+pkg.Version {
+					found = append(found, fmt.Sprintf("%s@%s%s", pkg.Name, pkg.Version, platformSuffix(sbom.Platform)))
+				}
+			}
+		}
+	}
+
+	if len(found) > 0 {
+		check.Detail = "found " + strings.Join(found, ", ")
+		return check
+	}
+
+	check.Passed = true
+	check.Detail = "none found"
+	return check
+}
+
+func platformSuffix(platform string) string {
+	if platform == "" {
+		return ""
+	}
+	return " (" + platform + ")"
+}
+
+// normalizeSource strips VCS decorations so repository URLs compare equal
+func normalizeSource(uri string) string {
+	uri = strings.TrimPrefix(uri, "git+")
+	if i := strings.Index(uri, "#"); i >= 0 {
+		uri = uri[:i]
+	}
+	uri = strings.TrimSuffix(uri, "/")
+	return strings.TrimSuffix(uri, ".git")
+}
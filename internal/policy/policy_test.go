@@ -0,0 +1,194 @@
+package policy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const slsaV02 = `{
+	"builder": {"id": "https://github.com/actions/runner"},
+	"invocation": {"configSource": {"uri": "https://github.com/org/avs.git#refs/tags/v1.0.0"}}
+}`
+
+const slsaV1 = `{
+	"buildDefinition": {"externalParameters": {"configSource": {"uri": "https://github.com/org/avs"}}},
+	"runDetails": {"builder": {"id": "https://github.com/actions/runner"}}
+}`
+
+const spdx = `{
+	"spdxVersion": "SPDX-2.3",
+	"packages": [
+		{"name": "busybox", "versionInfo": "1.36.1"},
+		{"name": "openssl", "versionInfo": "3.0.1"}
+	]
+}`
+
+func TestParseImagetools(t *testing.T) {
+	t.Run("single platform", func(t *testing.T) {
+		att, err := ParseImagetools([]byte(`{"SLSA": `+slsaV02+`}`), []byte(`{"SPDX": `+spdx+`}`))
+		require.NoError(t, err)
+
+		require.Len(t, att.Provenance, 1)
+		assert.Equal(t, "https://github.com/actions/runner", att.Provenance[0].BuilderID)
+		assert.Equal(t, "https://github.com/org/avs.git#refs/tags/v1.0.0", att.Provenance[0].SourceURI)
+
+		require.Len(t, att.SBOM, 1)
+		assert.Equal(t, []Package{{"busybox", "1.36.1"}, {"openssl", "3.0.1"}}, att.SBOM[0].Packages)
+	})
+
+	t.Run("multi platform", func(t *testing.T) {
+		prov := `{"linux/amd64": {"SLSA": ` + slsaV1 + `}, "linux/arm64": {"SLSA": ` + slsaV02 + `}}`
+		att, err := ParseImagetools([]byte(prov), nil)
+		require.NoError(t, err)
+
+		require.Len(t, att.Provenance, 2)
+		assert.Equal(t, "linux/amd64", att.Provenance[0].Platform)
+		assert.Equal(t, "https://github.com/org/avs", att.Provenance[0].SourceURI)
+		assert.Equal(t, "linux/arm64", att.Provenance[1].Platform)
+		assert.Empty(t, att.SBOM)
+	})
+
+	t.Run("no attestations", func(t *testing.T) {
+		att, err := ParseImagetools([]byte("null"), []byte(""))
+		require.NoError(t, err)
+		assert.Empty(t, att.Provenance)
+		assert.Empty(t, att.SBOM)
+	})
+}
+
+func TestParseSBOM_CycloneDX(t *testing.T) {
+	sbom, err := ParseSBOM([]byte(`{"predicate": {"bomFormat": "CycloneDX", "components": [{"name": "log4j-core", "version": "2.14.1"}]}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []Package{{"log4j-core", "2.14.1"}}, sbom.Packages)
+}
+
+func TestEvaluate(t *testing.T) {
+	att, err := ParseImagetools([]byte(`{"SLSA": `+slsaV02+`}`), []byte(`{"SPDX": `+spdx+`}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		policy       Policy
+		att          *Attestations
+		expectPassed bool
+		expectDetail string
+	}{
+		{
+			name: "all rules pass",
+			policy: Policy{
+				RequiredBuilder:   "https://github.com/actions/runner",
+				RequiredSource:    "https://github.com/org/avs",
+				ForbiddenPackages: []string{"log4j-core"},
+			},
+			att:          att,
+			expectPassed: true,
+		},
+		{
+			name:         "wrong builder",
+			policy:       Policy{RequiredBuilder: "https://builder.example.com"},
+			att:          att,
+			expectDetail: "builder is https://github.com/actions/runner",
+		},
+		{
+			name:         "wrong source",
+			policy:       Policy{RequiredSource: "https://github.com/evil/avs"},
+			att:          att,
+			expectDetail: "want https://github.com/evil/avs",
+		},
+		{
+			name:         "forbidden package by name",
+			policy:       Policy{ForbiddenPackages: []string{"openssl"}},
+			att:          att,
+			expectDetail: "found openssl@3.0.1",
+		},
+		{
+			name:         "forbidden package by version",
+			policy:       Policy{ForbiddenPackages: []string{"openssl@3.0.2"}},
+			att:          att,
+			expectPassed: true,
+		},
+		{
+			name:         "missing provenance",
+			policy:       Policy{RequiredBuilder: "https://github.com/actions/runner"},
+			att:          nil,
+			expectDetail: "no provenance attestation found",
+		},
+		{
+			name:         "missing SBOM",
+			policy:       Policy{ForbiddenPackages: []string{"openssl"}},
+			att:          &Attestations{},
+			expectDetail: "no SBOM attestation found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.policy.Evaluate("ghcr.io/org/avs@sha256:aa", tt.att)
+			assert.Equal(t, tt.expectPassed, result.Passed())
+
+			var out bytes.Buffer
+			result.Print(&out)
+			if tt.expectDetail != "" {
+				assert.Contains(t, out.String(), tt.expectDetail)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("requiredBuilder: https://github.com/actions/runner\nforbiddenPackages:\n  - openssl\n"), 0644))
+
+	p, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/actions/runner", p.RequiredBuilder)
+	assert.Equal(t, []string{"openssl"}, p.ForbiddenPackages)
+
+	// Unknown fields are rejected so typos don't silently disable rules
+	bad := filepath.Join(dir, "bad.yaml")
+	require.NoError(t, os.WriteFile(bad, []byte("requiredBuildr: x\n"), 0644))
+	_, err = Load(bad)
+	assert.Error(t, err)
+}
+
+func TestLoad_Approvals(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`approvals:
+  threshold: 2
+  approvers:
+    - 0x70997970C51812dc3A010C7d01b50e0d17dc79C8
+    - 0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC
+  source: approvals
+`), 0644))
+
+	p, err := Load(path)
+	require.NoError(t, err)
+	require.NotNil(t, p.Approvals)
+	assert.Equal(t, 2, p.Approvals.Threshold)
+	assert.Len(t, p.Approvals.ApproverAddresses(), 2)
+	assert.Equal(t, filepath.Join(dir, "approvals"), p.Approvals.Source)
+	assert.False(t, p.HasAttestationRules())
+
+	for name, content := range map[string]string{
+		"threshold above approvers": "approvals:\n  threshold: 2\n  approvers: [0x70997970C51812dc3A010C7d01b50e0d17dc79C8]\n",
+		"zero threshold":            "approvals:\n  threshold: 0\n  approvers: [0x70997970C51812dc3A010C7d01b50e0d17dc79C8]\n",
+		"invalid approver":          "approvals:\n  threshold: 1\n  approvers: [alice]\n",
+		"duplicate approver":        "approvals:\n  threshold: 1\n  approvers: [0x70997970C51812dc3A010C7d01b50e0d17dc79C8, 0x70997970c51812dc3a010c7d01b50e0d17dc79c8]\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			bad := filepath.Join(dir, "bad.yaml")
+			require.NoError(t, os.WriteFile(bad, []byte(content), 0644))
+			_, err := Load(bad)
+			assert.ErrorContains(t, err, "invalid approvals")
+		})
+	}
+}
\ No newline at end of file
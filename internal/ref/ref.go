@@ -10,15 +10,150 @@ func Digest32ToSha256String(d [32]byte) string {
 	return "sha256:" + strings.ToLower(hex.EncodeToString(d[:]))
 }
 
+// BuildReference pins registry to digest. A registry that already embeds a
+// digest is returned as-is only if that digest is the same one; the digest
+// attested on-chain is the only one an image may be fetched by.
 func BuildReference(registry string, digest string) (string, error) {
 	if registry == "" {
 		return "", fmt.Errorf("empty registry")
 	}
-	if strings.Contains(registry, "@sha256:") {
-		return registry, nil
-	}
 	if !strings.HasPrefix(digest, "sha256:") {
 		return "", fmt.Errorf("invalid digest format")
 	}
+	if i := strings.Index(registry, "@"); i >= 0 {
+		if embedded := registry[i+1:]; embedded != digest {
+			return "", fmt.Errorf("registry %s embeds digest %s, but the release attests %s", registry, embedded, digest)
+		}
+		return registry, nil
+	}
 	return registry + "@" + digest, nil
-}
\ No newline at end of file
+}
+
+// SplitRepository splits a registry reference into its registry host and
+// repository path, applying Docker Hub defaults for short names. Any tag or
+// digest suffix is dropped.
+func SplitRepository(registry string) (string, string) {
+	registry, _ = splitSuffix(registry)
+
+	parts := strings.SplitN(registry, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0], parts[1]
+	}
+
+	if !strings.Contains(registry, "/") {
+		return "docker.io", "library/" + registry
+	}
+	return "docker.io", registry
+}
+
+// RewriteRegistry maps a registry onto a mirror using the longest matching
+// source prefix in mirrors. Sources may be a bare registry host (ghcr.io) or a
+// repository prefix (ghcr.io/org). Registries without a matching entry are
+// returned unchanged. Any tag or digest suffix on the registry is preserved.
+func RewriteRegistry(registry string, mirrors map[string]string) string {
+	if len(mirrors) == 0 || registry == "" {
+		return registry
+	}
+
+	_, suffix := splitSuffix(registry)
+	host, path := SplitRepository(registry)
+	full := host + "/" + path
+
+	match, target := "", ""
+	for source, mirror := range mirrors {
+		prefix := normalizeSource(source)
+		if full != prefix && !strings.HasPrefix(full, prefix+"/") {
+			continue
+		}
+		if len(prefix) > len(match) {
+			match, target = prefix, strings.TrimSuffix(mirror, "/")
+		}
+	}
+	if match == "" {
+		return registry
+	}
+
+	return target + strings.TrimPrefix(full, match) + suffix
+}
+
+// splitSuffix splits a registry reference into its name and its ":tag",
+// "@digest" or ":tag@digest" suffix. A colon before the last slash belongs to
+// the registry host's port, not to a tag.
+func splitSuffix(registry string) (string, string) {
+	name, suffix := registry, ""
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, suffix = name[:i], name[i:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, suffix = name[:i], name[i:]+suffix
+	}
+	return name, suffix
+}
+
+// normalizeSource normalizes a mirror source so it can be prefix-matched
+// against the output of SplitRepository
+func normalizeSource(source string) string {
+	source = strings.TrimSuffix(source, "/")
+	if !strings.Contains(source, "/") && (strings.ContainsAny(source, ".:") || source == "localhost") {
+		return source
+	}
+	host, path := SplitRepository(source)
+	return host + "/" + path
+}
+
+// MirrorRepository returns the repository under target that mirrors the given
+// registry, keeping the repository path and replacing the registry host.
+func MirrorRepository(registry, target string) (string, error) {
+	if registry == "" {
+		return "", fmt.Errorf("empty registry")
+	}
+	target = strings.TrimSuffix(target, "/")
+	if target == "" {
+		return "", fmt.Errorf("empty target registry")
+	}
+	_, path := SplitRepository(registry)
+	return target + "/" + path, nil
+}
+
+// DigestMismatchError reports that a local image does not carry the digest it
+// was pulled by
+type DigestMismatchError struct {
+	Reference   string
+	Expected    string
+	RepoDigests []string
+}
+
+func (e *DigestMismatchError) Error() string {
+	found := "no repository digests"
+	if len(e.RepoDigests) > 0 {
+		found = strings.Join(e.RepoDigests, ", ")
+	}
+	return fmt.Sprintf("digest mismatch for %s: expected %s, local image has %s", e.Reference, e.Expected, found)
+}
+
+// VerifyRepoDigests checks that one of a local image's repository digests
+// (repo@sha256:...) has the same repository as reference and the expected
+// digest, which is the one attested on-chain rather than whatever reference
+// is pinned to. Short Docker Hub names are normalized before comparing.
+func VerifyRepoDigests(reference, expected string, repoDigests []string) error {
+	if !strings.HasPrefix(expected, "sha256:") {
+		return fmt.Errorf("invalid digest format")
+	}
+	host, path := SplitRepository(reference)
+
+	for _, repoDigest := range repoDigests {
+		i := strings.Index(repoDigest, "@")
+		if i < 0 || repoDigest[i+1:] != expected {
+			continue
+		}
+		if h, p := SplitRepository(repoDigest); h == host && p == path {
+			return nil
+		}
+	}
+
+	return &DigestMismatchError{
+		Reference:   reference,
+		Expected:    expected,
+		RepoDigests: repoDigests,
+	}
+}
@@ -79,9 +79,15 @@ func TestBuildReference(t *testing.T) {
 		{
 			name:     "passthrough - registry already has digest",
 			registry: "ghcr.io/org/image@sha256:" + strings.Repeat("22", 32),
-			digest:   "ignored",
+			digest:   "sha256:" + strings.Repeat("22", 32),
 			expected: "ghcr.io/org/image@sha256:" + strings.Repeat("22", 32),
 		},
+		{
+			name:        "registry embeds a different digest",
+			registry:    "ghcr.io/org/image@sha256:" + strings.Repeat("22", 32),
+			digest:      validDigest,
+			shouldError: true,
+		},
 		{
 			name:        "empty registry",
 			registry:    "",
@@ -119,9 +125,12 @@ func TestBuildReference(t *testing.T) {
 func TestBuildReference_Passthrough(t *testing.T) {
 	// Special test for passthrough behavior
 	keep := "ghcr.io/org/image@sha256:" + strings.Repeat("22", 32)
-	got, err := BuildReference(keep, "this-should-be-ignored")
+	got, err := BuildReference(keep, "sha256:"+strings.Repeat("22", 32))
 	require.NoError(t, err)
 	assert.Equal(t, keep, got)
+
+	_, err = BuildReference(keep, "sha256:"+strings.Repeat("33", 32))
+	assert.ErrorContains(t, err, "embeds digest sha256:"+strings.Repeat("22", 32))
 }
 
 func TestDigestFormat(t *testing.T) {
@@ -147,4 +156,228 @@ func TestDigestFormat(t *testing.T) {
 	for _, c := range hexPart {
 		assert.True(t, (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f'))
 	}
+}
+
+func TestSplitRepository(t *testing.T) {
+	tests := []struct {
+		name         string
+		registry     string
+		expectedHost string
+		expectedPath string
+	}{
+		{
+			name:         "registry host",
+			registry:     "ghcr.io/org/image",
+			expectedHost: "ghcr.io",
+			expectedPath: "org/image",
+		},
+		{
+			name:         "registry with port",
+			registry:     "localhost:5000/myimage",
+			expectedHost: "localhost:5000",
+			expectedPath: "myimage",
+		},
+		{
+			name:         "docker hub short name",
+			registry:     "alpine",
+			expectedHost: "docker.io",
+			expectedPath: "library/alpine",
+		},
+		{
+			name:         "docker hub user image",
+			registry:     "myuser/myimage",
+			expectedHost: "docker.io",
+			expectedPath: "myuser/myimage",
+		},
+		{
+			name:         "tag suffix dropped",
+			registry:     "ghcr.io/org/image:v1.2.0",
+			expectedHost: "ghcr.io",
+			expectedPath: "org/image",
+		},
+		{
+			name:         "tag suffix dropped with registry port",
+			registry:     "localhost:5000/myimage:latest",
+			expectedHost: "localhost:5000",
+			expectedPath: "myimage",
+		},
+		{
+			name:         "docker hub short name with tag",
+			registry:     "alpine:3.19",
+			expectedHost: "docker.io",
+			expectedPath: "library/alpine",
+		},
+		{
+			name:         "tag and digest suffix dropped",
+			registry:     "ghcr.io/org/image:v1@sha256:" + strings.Repeat("22", 32),
+			expectedHost: "ghcr.io",
+			expectedPath: "org/image",
+		},
+		{
+			name:         "digest suffix dropped",
+			registry:     "ghcr.io/org/image@sha256:" + strings.Repeat("22", 32),
+			expectedHost: "ghcr.io",
+			expectedPath: "org/image",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, path := SplitRepository(tt.registry)
+			assert.Equal(t, tt.expectedHost, host)
+			assert.Equal(t, tt.expectedPath, path)
+		})
+	}
+}
+
+func TestRewriteRegistry(t *testing.T) {
+	mirrors := map[string]string{
+		"ghcr.io":         "mirror.internal",
+		"ghcr.io/special": "special.internal/ghcr",
+		"docker.io":       "mirror.internal/hub",
+	}
+
+	tests := []struct {
+		name     string
+		registry string
+		mirrors  map[string]string
+		expected string
+	}{
+		{
+			name:     "no mirrors",
+			registry: "ghcr.io/org/image",
+			expected: "ghcr.io/org/image",
+		},
+		{
+			name:     "host rewrite",
+			registry: "ghcr.io/org/image",
+			mirrors:  mirrors,
+			expected: "mirror.internal/org/image",
+		},
+		{
+			name:     "longest prefix wins",
+			registry: "ghcr.io/special/image",
+			mirrors:  mirrors,
+			expected: "special.internal/ghcr/image",
+		},
+		{
+			name:     "docker hub short name",
+			registry: "alpine",
+			mirrors:  mirrors,
+			expected: "mirror.internal/hub/library/alpine",
+		},
+		{
+			name:     "unmatched registry unchanged",
+			registry: "quay.io/org/image",
+			mirrors:  mirrors,
+			expected: "quay.io/org/image",
+		},
+		{
+			name:     "prefix must end on path boundary",
+			registry: "ghcr.io/specialty/image",
+			mirrors:  map[string]string{"ghcr.io/special": "special.internal"},
+			expected: "ghcr.io/specialty/image",
+		},
+		{
+			name:     "digest suffix preserved",
+			registry: "ghcr.io/org/image@sha256:" + strings.Repeat("22", 32),
+			mirrors:  mirrors,
+			expected: "mirror.internal/org/image@sha256:" + strings.Repeat("22", 32),
+		},		{
+			name:     "tag suffix preserved",
+			registry: "ghcr.io/org/image:v1",
+			mirrors:  mirrors,
+			expected: "mirror.internal/org/image:v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RewriteRegistry(tt.registry, tt.mirrors))
+		})
+	}
+}
+
+func TestMirrorRepository(t *testing.T) {
+	got, err := MirrorRepository("ghcr.io/org/image", "mirror.internal/")
+	require.NoError(t, err)
+	assert.Equal(t, "mirror.internal/org/image", got)
+
+	got, err = MirrorRepository("alpine", "mirror.internal")
+	require.NoError(t, err)
+	assert.Equal(t, "mirror.internal/library/alpine", got)
+
+	_, err = MirrorRepository("", "mirror.internal")
+	assert.Error(t, err)
+
+	_, err = MirrorRepository("ghcr.io/org/image", "")
+	assert.Error(t, err)
+}
+
+func TestVerifyRepoDigests(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	other := "sha256:" + strings.Repeat("cd", 32)
+
+	tests := []struct {
+		name        string
+		reference   string
+		repoDigests []string
+		expectError bool
+	}{
+		{
+			name:        "exact match",
+			reference:   "ghcr.io/org/image@" + digest,
+			repoDigests: []string{"ghcr.io/org/image@" + digest},
+		},
+		{
+			name:        "docker hub short name",
+			reference:   "docker.io/library/alpine@" + digest,
+			repoDigests: []string{"alpine@" + digest},
+		},
+		{
+			name:        "one of several repositories",
+			reference:   "mirror.internal/org/image@" + digest,
+			repoDigests: []string{"ghcr.io/org/image@" + digest, "mirror.internal/org/image@" + digest},
+		},
+		{
+			name:        "different digest",
+			reference:   "ghcr.io/org/image@" + digest,
+			repoDigests: []string{"ghcr.io/org/image@" + other},
+			expectError: true,
+		},
+		{
+			name:        "same digest in another repository",
+			reference:   "ghcr.io/org/image@" + digest,
+			repoDigests: []string{"ghcr.io/evil/image@" + digest},
+			expectError: true,
+		},
+		{
+			name:        "no repository digests",
+			reference:   "ghcr.io/org/image@" + digest,
+			expectError: true,
+		},
+		{
+			name:        "reference pinned to another digest",
+			reference:   "ghcr.io/org/image@" + other,
+			repoDigests: []string{"ghcr.io/org/image@" + other},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyRepoDigests(tt.reference, digest, tt.repoDigests)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	err := VerifyRepoDigests("ghcr.io/org/image@"+digest, digest, []string{"ghcr.io/org/image@" + other})
+	var mismatch *DigestMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, digest, mismatch.Expected)
+	assert.Contains(t, err.Error(), other)
 }
\ No newline at end of file
@@ -0,0 +1,219 @@
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/docker"
+)
+
+// dockerHubAuthKey is the key Docker uses for Docker Hub credentials
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// Validate checks that a credential reference is well formed
+func Validate(cred *config.RegistryCredential) error {
+	sources := 0
+	for _, v := range []string{cred.Helper, cred.TokenFile, cred.TokenEnv} {
+		if v != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("exactly one of credential helper, token file or token env var is required")
+	}
+
+	if cred.Helper != "" {
+		if cred.Username != "" {
+			return fmt.Errorf("username is provided by the credential helper")
+		}
+		if _, err := exec.LookPath("docker-credential-" + cred.Helper); err != nil {
+			return fmt.Errorf("credential helper docker-credential-%s not found in PATH", cred.Helper)
+		}
+		return nil
+	}
+
+	if cred.Username == "" {
+		return fmt.Errorf("username is required for token credentials")
+	}
+	return nil
+}
+
+// Token resolves the password or token of a token credential
+func Token(cred *config.RegistryCredential) (string, error) {
+	switch {
+	case cred.TokenFile != "":
+		data, err := os.ReadFile(cred.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", cred.TokenFile)
+		}
+		return token, nil
+	case cred.TokenEnv != "":
+		token := os.Getenv(cred.TokenEnv)
+		if token == "" {
+			return "", fmt.Errorf("environment variable %s is not set", cred.TokenEnv)
+		}
+		return token, nil
+	default:
+		return "", fmt.Errorf("credential has no token source")
+	}
+}
+
+// authKey returns the key Docker stores credentials for a registry host under
+func authKey(host string) string {
+	if host == "docker.io" || host == "index.docker.io" {
+		return dockerHubAuthKey
+	}
+	return host
+}
+
+// userConfigDir returns the Docker client configuration directory in use
+func userConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker")
+}
+
+// DockerConfig writes a temporary Docker client configuration directory that
+// layers the given credentials over the user's Docker configuration. Token
+// credentials are resolved now and exist only in the temporary directory,
+// which the returned cleanup function removes.
+func DockerConfig(creds map[string]*config.RegistryCredential) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "flickr-docker-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create docker config directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	if err := writeDockerConfig(dir, creds); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return dir, cleanup, nil
+}
+
+func writeDockerConfig(dir string, creds map[string]*config.RegistryCredential) error {
+	cfg := make(map[string]interface{})
+	userDir := userConfigDir()
+
+	if userDir != "" {
+		// Keep the user's settings (other registries, credsStore, proxies)
+		if data, err := os.ReadFile(filepath.Join(userDir, "config.json")); err == nil {
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				return fmt.Errorf("failed to parse docker config: %w", err)
+			}
+		}
+
+		// Link everything else (CLI plugins, buildx builders, contexts)
+		entries, _ := os.ReadDir(userDir)
+		for _, entry := range entries {
+			if entry.Name() == "config.json" {
+				continue
+			}
+			if err := os.Symlink(filepath.Join(userDir, entry.Name()), filepath.Join(dir, entry.Name())); err != nil {
+				return fmt.Errorf("failed to link docker config %s: %w", entry.Name(), err)
+			}
+		}
+	}
+
+	auths, _ := cfg["auths"].(map[string]interface{})
+	if auths == nil {
+		auths = make(map[string]interface{})
+	}
+	helpers, _ := cfg["credHelpers"].(map[string]interface{})
+	if helpers == nil {
+		helpers = make(map[string]interface{})
+	}
+
+	tokenKeys := make(map[string]bool)
+	for host, cred := range creds {
+		key := authKey(host)
+		if cred.Helper != "" {
+			helpers[key] = cred.Helper
+			delete(auths, key)
+			continue
+		}
+		tokenKeys[key] = true
+
+		token, err := Token(cred)
+		if err != nil {
+			return fmt.Errorf("failed to resolve credentials for %s: %w", host, err)
+		}
+		auths[key] = map[string]string{
+			"auth": base64.StdEncoding.EncodeToString([]byte(cred.Username + ":" + token)),
+		}
+		delete(helpers, key)
+	}
+
+	// Docker asks a credsStore before reading auths, which would hide the
+	// token credentials. Pin the hosts the store holds credentials for to it
+	// instead, and drop it.
+	if store, _ := cfg["credsStore"].(string); store != "" && len(tokenKeys) > 0 {
+		for _, server := range storedServers(store) {
+			if _, pinned := helpers[server]; !pinned && !tokenKeys[server] {
+				helpers[server] = store
+			}
+		}
+		delete(cfg, "credsStore")
+	}
+
+	cfg["auths"] = auths
+	cfg["credHelpers"] = helpers
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal docker config: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to write docker config: %w", err)
+	}
+	return nil
+}
+
+// storedServers lists the registries a credential store holds credentials
+// for. A store that cannot be listed holds none.
+func storedServers(store string) []string {
+	out, err := exec.Command("docker-credential-"+store, "list").Output()
+	if err != nil {
+		return nil
+	}
+	var servers map[string]string
+	if err := json.Unmarshal(out, &servers); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(servers))
+	for server := range servers {
+		names = append(names, server)
+	}
+	return names
+}
+
+// NewRunner returns a Docker runner authenticated with the registry
+// credentials of a context. The cleanup function must be called when done.
+func NewRunner(ctx *config.Context) (*docker.Runner, func(), error) {
+	runner := docker.New()
+	if ctx == nil || len(ctx.RegistryAuth) == 0 {
+		return runner, func() {}, nil
+	}
+
+	dir, cleanup, err := DockerConfig(ctx.RegistryAuth)
+	if err != nil {
+		return nil, nil, err
+	}
+	runner.ConfigDir = dir
+	return runner, cleanup, nil
+}
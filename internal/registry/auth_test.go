@@ -0,0 +1,168 @@
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/config"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cred        config.RegistryCredential
+		expectError bool
+	}{
+		{
+			name: "token env with username",
+			cred: config.RegistryCredential{Username: "bot", TokenEnv: "TOKEN"},
+		},
+		{
+			name: "token file with username",
+			cred: config.RegistryCredential{Username: "bot", TokenFile: "/tmp/token"},
+		},
+		{
+			name:        "token without username",
+			cred:        config.RegistryCredential{TokenEnv: "TOKEN"},
+			expectError: true,
+		},
+		{
+			name:        "no source",
+			cred:        config.RegistryCredential{Username: "bot"},
+			expectError: true,
+		},
+		{
+			name:        "multiple sources",
+			cred:        config.RegistryCredential{Username: "bot", TokenEnv: "TOKEN", TokenFile: "/tmp/token"},
+			expectError: true,
+		},
+		{
+			name:        "helper not installed",
+			cred:        config.RegistryCredential{Helper: "flickr-test-missing"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.cred)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestToken(t *testing.T) {
+	t.Setenv("FLICKR_TEST_TOKEN", "env-secret")
+	token, err := Token(&config.RegistryCredential{Username: "bot", TokenEnv: "FLICKR_TEST_TOKEN"})
+	require.NoError(t, err)
+	assert.Equal(t, "env-secret", token)
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("file-secret\n"), 0600))
+	token, err = Token(&config.RegistryCredential{Username: "bot", TokenFile: path})
+	require.NoError(t, err)
+	assert.Equal(t, "file-secret", token)
+
+	_, err = Token(&config.RegistryCredential{Username: "bot", TokenEnv: "FLICKR_TEST_UNSET"})
+	assert.Error(t, err)
+}
+
+func TestDockerConfig(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", userDir)
+	t.Setenv("FLICKR_TEST_TOKEN", "secret")
+
+	userConfig := `{
+		"auths": {"quay.io": {"auth": "dXNlcjpwYXNz"}, "ghcr.io": {"auth": "b2xkOm9sZA=="}},
+		"credHelpers": {"https://index.docker.io/v1/": "desktop"},
+		"proxies": {"default": {"httpProxy": "http://proxy:3128"}}
+	}`
+	require.NoError(t, os.WriteFile(filepath.Join(userDir, "config.json"), []byte(userConfig), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(userDir, "cli-plugins"), 0755))
+
+	dir, cleanup, err := DockerConfig(map[string]*config.RegistryCredential{
+		"ghcr.io":   {Username: "bot", TokenEnv: "FLICKR_TEST_TOKEN"},
+		"docker.io": {Username: "hub", TokenEnv: "FLICKR_TEST_TOKEN"},
+		"gcr.io":    {Helper: "gcloud"},
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+
+	var cfg struct {
+		Auths       map[string]map[string]string `json:"auths"`
+		CredHelpers map[string]string            `json:"credHelpers"`
+		Proxies     map[string]interface{}       `json:"proxies"`
+	}
+	require.NoError(t, json.Unmarshal(data, &cfg))
+
+	// Existing registries and settings are kept
+	assert.Equal(t, "dXNlcjpwYXNz", cfg.Auths["quay.io"]["auth"])
+	assert.Contains(t, cfg.Proxies, "default")
+
+	// Context credentials override the user's
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("bot:secret")), cfg.Auths["ghcr.io"]["auth"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("hub:secret")), cfg.Auths[dockerHubAuthKey]["auth"])
+	assert.NotContains(t, cfg.CredHelpers, dockerHubAuthKey)
+	assert.Equal(t, "gcloud", cfg.CredHelpers["gcr.io"])
+
+	// Other entries are linked, and the user's config is untouched
+	_, err = os.Stat(filepath.Join(dir, "cli-plugins"))
+	assert.NoError(t, err)
+	original, err := os.ReadFile(filepath.Join(userDir, "config.json"))
+	require.NoError(t, err)
+	assert.Equal(t, userConfig, string(original))
+
+	info, err := os.Stat(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	cleanup()
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDockerConfig_CredsStore(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", userDir)
+	t.Setenv("FLICKR_TEST_TOKEN", "secret")
+
+	// A credential store holding credentials for Docker Hub and quay.io
+	binDir := t.TempDir()
+	helper := "#!/bin/sh\necho '{\"https://index.docker.io/v1/\": \"user\", \"quay.io\": \"user\"}'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "docker-credential-flickrtest"), []byte(helper), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	userConfig := `{"auths": {"quay.io": {}, "https://index.docker.io/v1/": {}}, "credsStore": "flickrtest"}`
+	require.NoError(t, os.WriteFile(filepath.Join(userDir, "config.json"), []byte(userConfig), 0600))
+
+	dir, cleanup, err := DockerConfig(map[string]*config.RegistryCredential{
+		"docker.io": {Username: "hub", TokenEnv: "FLICKR_TEST_TOKEN"},
+	})
+	require.NoError(t, err)
+	defer cleanup()
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+
+	var cfg map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &cfg))
+
+	// The store no longer shadows the token, but still serves its other hosts
+	assert.NotContains(t, cfg, "credsStore")
+	helpers := cfg["credHelpers"].(map[string]interface{})
+	assert.Equal(t, "flickrtest", helpers["quay.io"])
+	assert.NotContains(t, helpers, dockerHubAuthKey)
+	auths := cfg["auths"].(map[string]interface{})
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("hub:secret")), auths[dockerHubAuthKey].(map[string]interface{})["auth"])
+}
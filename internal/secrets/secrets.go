@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Reference schemes. A secret reference has the form <scheme>:<value>.
+const (
+	SchemeEnv    = "env"    // env:FLICKR_PRIVATE_KEY
+	SchemeFile   = "file"   // file:/home/me/.flickr/key
+	SchemeCmd    = "cmd"    // cmd:pass show flickr/key
+	SchemeSecret = "secret" // secret:mainnet-key (encrypted secrets file)
+)
+
+// RefHelp describes the accepted reference formats, for flag usage strings
+const RefHelp = "env:VAR, file:PATH, cmd:COMMAND or secret:NAME"
+
+// ParseRef splits a secret reference into its scheme and value
+func ParseRef(ref string) (string, string, error) {
+	scheme, value, ok := strings.Cut(ref, ":")
+	if !ok || value == "" {
+		return "", "", fmt.Errorf("invalid secret reference %q (expected %s)", ref, RefHelp)
+	}
+
+	switch scheme {
+	case SchemeEnv, SchemeFile, SchemeCmd, SchemeSecret:
+		return scheme, value, nil
+	default:
+		return "", "", fmt.Errorf("unknown secret reference scheme %q (expected %s)", scheme, RefHelp)
+	}
+}
+
+// Resolve returns the secret a reference points to. Surrounding whitespace is
+// trimmed from the result.
+func Resolve(ref string) (string, error) {
+	scheme, value, err := ParseRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	var secret string
+	switch scheme {
+	case SchemeEnv:
+		secret = os.Getenv(value)
+		if secret == "" {
+			return "", fmt.Errorf("environment variable %s is not set", value)
+		}
+	case SchemeFile:
+		data, err := os.ReadFile(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		secret = string(data)
+	case SchemeCmd:
+		var stdout bytes.Buffer
+		cmd := exec.Command("sh", "-c", value)
+		cmd.Stdout = &stdout
+		cmd.Stderr = os.Stderr
+		cmd.Stdin = os.Stdin
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("secret command failed: %w", err)
+		}
+		secret = stdout.String()
+	case SchemeSecret:
+		passphrase, err := Passphrase(false)
+		if err != nil {
+			return "", err
+		}
+		store, err := OpenStore(passphrase)
+		if err != nil {
+			return "", err
+		}
+		s, ok := store.Get(value)
+		if !ok {
+			return "", fmt.Errorf("secret %q not found in secrets file", value)
+		}
+		secret = s
+	}
+
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return "", fmt.Errorf("secret reference %s resolved to an empty value", ref)
+	}
+	return secret, nil
+}
@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("FLICKR_TEST_SECRET", "from-env")
+
+	path := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0600))
+
+	tests := []struct {
+		name        string
+		ref         string
+		expected    string
+		expectError bool
+	}{
+		{name: "env", ref: "env:FLICKR_TEST_SECRET", expected: "from-env"},
+		{name: "file", ref: "file:" + path, expected: "from-file"},
+		{name: "cmd", ref: "cmd:echo from-cmd", expected: "from-cmd"},
+		{name: "unset env", ref: "env:FLICKR_TEST_UNSET", expectError: true},
+		{name: "missing file", ref: "file:" + path + ".missing", expectError: true},
+		{name: "failing cmd", ref: "cmd:exit 1", expectError: true},
+		{name: "empty cmd output", ref: "cmd:true", expectError: true},
+		{name: "unknown scheme", ref: "vault:kv/key", expectError: true},
+		{name: "plaintext value", ref: "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80", expectError: true},
+		{name: "empty value", ref: "env:", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret, err := Resolve(tt.ref)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, secret)
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	defaultN := scryptN
+	scryptN = 1 << 10
+	t.Cleanup(func() { scryptN = defaultN })
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(PassphraseEnv, "correct horse")
+
+	assert.False(t, StoreExists())
+
+	store, err := OpenStore([]byte("correct horse"))
+	require.NoError(t, err)
+	store.Set("mainnet-key", "0xabc")
+	store.Set("keystore-password", "hunter2")
+	require.NoError(t, store.Save())
+
+	path, err := StorePath()
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// The file doesn't contain the secrets in the clear
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hunter2")
+
+	reopened, err := OpenStore([]byte("correct horse"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"keystore-password", "mainnet-key"}, reopened.Names())
+
+	_, err = OpenStore([]byte("wrong"))
+	assert.ErrorContains(t, err, "wrong passphrase")
+
+	// Resolve unlocks the store with the passphrase from the environment
+	secret, err := Resolve("secret:mainnet-key")
+	require.NoError(t, err)
+	assert.Equal(t, "0xabc", secret)
+
+	_, err = Resolve("secret:missing")
+	assert.Error(t, err)
+
+	assert.True(t, reopened.Delete("mainnet-key"))
+	assert.False(t, reopened.Delete("mainnet-key"))
+}
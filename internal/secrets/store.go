@@ -0,0 +1,255 @@
+package secrets
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/flickr/internal/config"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+// PassphraseEnv holds the passphrase of the secrets file for non-interactive use
+const PassphraseEnv = "FLICKR_SECRETS_PASSPHRASE"
+
+const (
+	storeFileName = "secrets.enc"
+	storeVersion  = 1
+
+	scryptR = 8
+	scryptP = 1
+	keyLen  = 32
+)
+
+// scryptN is the scrypt cost parameter, as used by go-ethereum's standard
+// keystore. It is recorded in the file, so tests may lower it.
+var scryptN = 1 << 18
+
+// storeFile is the on-disk format of the secrets file
+type storeFile struct {
+	Version    int    `json:"version"`
+	KDF        kdf    `json:"kdf"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type kdf struct {
+	Name string `json:"name"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+}
+
+// Store is the encrypted secrets file: named secrets sealed with AES-256-GCM
+// under a key derived from a passphrase with scrypt
+type Store struct {
+	path       string
+	passphrase []byte
+	secrets    map[string]string
+}
+
+// StorePath returns the path of the secrets file
+func StorePath() (string, error) {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), storeFileName), nil
+}
+
+// StoreExists reports whether the secrets file has been created
+func StoreExists() bool {
+	path, err := StorePath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// OpenStore opens the secrets file, returning an empty store if it doesn't
+// exist yet
+func OpenStore(passphrase []byte) (*Store, error) {
+	path, err := StorePath()
+	if err != nil {
+		return nil, err
+	}
+	return openStore(path, passphrase)
+}
+
+func openStore(path string, passphrase []byte) (*Store, error) {
+	s := &Store{
+		path:       path,
+		passphrase: passphrase,
+		secrets:    make(map[string]string),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	var f storeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file: %w", err)
+	}
+	if f.Version != storeVersion || f.KDF.Name != "scrypt" {
+		return nil, fmt.Errorf("unsupported secrets file version %d", f.Version)
+	}
+
+	gcm, err := newGCM(passphrase, f.KDF)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secrets file (wrong passphrase?)")
+	}
+
+	if err := json.Unmarshal(plaintext, &s.secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted secrets: %w", err)
+	}
+	return s, nil
+}
+
+// Get returns a named secret
+func (s *Store) Get(name string) (string, bool) {
+	v, ok := s.secrets[name]
+	return v, ok
+}
+
+// Set adds or replaces a named secret
+func (s *Store) Set(name, value string) {
+	s.secrets[name] = value
+}
+
+// Delete removes a named secret, reporting whether it existed
+func (s *Store) Delete(name string) bool {
+	_, ok := s.secrets[name]
+	delete(s.secrets, name)
+	return ok
+}
+
+// Names returns the sorted names of all secrets
+func (s *Store) Names() []string {
+	names := make([]string, 0, len(s.secrets))
+	for name := range s.secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save encrypts the store with a fresh salt and nonce and writes it to disk
+func (s *Store) Save() error {
+	plaintext, err := json.Marshal(s.secrets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets: %w", err)
+	}
+
+	params := kdf{Name: "scrypt", N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, 32)}
+	if _, err := rand.Read(params.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := newGCM(s.passphrase, params)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	data, err := json.MarshalIndent(storeFile{
+		Version:    storeVersion,
+		KDF:        params,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets file: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	return os.Chmod(s.path, 0600)
+}
+
+func newGCM(passphrase []byte, params kdf) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, params.Salt, params.N, params.R, params.P, keyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Passphrase returns the passphrase of the secrets file from
+// FLICKR_SECRETS_PASSPHRASE, or prompts for it on the terminal. With confirm
+// set, an interactive passphrase must be entered twice.
+func Passphrase(confirm bool) ([]byte, error) {
+	if p := os.Getenv(PassphraseEnv); p != "" {
+		return []byte(p), nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("secrets file passphrase required: set %s or run interactively", PassphraseEnv)
+	}
+
+	passphrase, err := ReadPassword("Secrets file passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("passphrase must not be empty")
+	}
+
+	if confirm {
+		again, err := ReadPassword("Confirm passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if string(again) != string(passphrase) {
+			return nil, fmt.Errorf("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// ReadPassword prompts on stderr and reads a line from stdin without echoing
+// it when stdin is a terminal
+func ReadPassword(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		password, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read password: %w", err)
+		}
+		return password, nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("failed to read password: %w", err)
+	}
+	return []byte(strings.TrimRight(line, "\r\n")), nil
+}
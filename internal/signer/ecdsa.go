@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 // ECDSASigner implements Signer using an ECDSA private key
@@ -75,6 +76,23 @@ func (s *ECDSASigner) SignMessage(msg []byte) ([]byte, error) {
 	return sig, nil
 }
 
+// SignTypedData signs EIP-712 typed data
+func (s *ECDSASigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+
+	sig, err := crypto.Sign(hash, s.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+
+	// Transform V from 0/1 to 27/28 as for SignMessage
+	sig[64] += 27
+	return sig, nil
+}
+
 // PublicKey returns the public key
 func (s *ECDSASigner) PublicKey() *ecdsa.PublicKey {
 	return &s.privateKey.PublicKey
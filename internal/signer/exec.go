@@ -0,0 +1,249 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// execTimeout bounds each run of the signer program. It is generous as
+// programs may wait for an operator to approve the signature.
+const execTimeout = 2 * time.Minute
+
+// ExecProtocolVersion is the version of the JSON contract between flickr and
+// signer programs
+const ExecProtocolVersion = 1
+
+// Signing request kinds sent to signer programs
+const (
+	ExecKindTransaction = "transaction"
+	ExecKindMessage     = "message"
+	ExecKindTypedData   = "typedData"
+)
+
+// ExecSignerConfig configures a signer program
+type ExecSignerConfig struct {
+	Command string
+	Args    []string
+
+	// Address of the program's key. When empty the program is asked for it.
+	Address common.Address
+}
+
+// ExecRequest is written as JSON to the signer program's stdin. Method is
+// "address" to ask for the key's address, or "sign" to sign Hash. The
+// original payload is included so the program can show or check what it
+// signs.
+type ExecRequest struct {
+	Version int    `json:"version"`
+	Method  string `json:"method"`
+
+	Address     *common.Address     `json:"address,omitempty"`
+	Kind        string              `json:"kind,omitempty"`
+	Hash        hexutil.Bytes       `json:"hash,omitempty"`
+	ChainID     *hexutil.Big        `json:"chainId,omitempty"`
+	Transaction hexutil.Bytes       `json:"transaction,omitempty"` // Unsigned, EIP-2718 encoded
+	Message     hexutil.Bytes       `json:"message,omitempty"`
+	TypedData   *apitypes.TypedData `json:"typedData,omitempty"`
+}
+
+// ExecResponse is read as JSON from the signer program's stdout. Signature
+// is a 65-byte [R || S || V] signature over the request's hash, with V
+// either 0/1 or 27/28.
+type ExecResponse struct {
+	Address   *common.Address `json:"address,omitempty"`
+	Signature hexutil.Bytes   `json:"signature,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// ExecSigner implements Signer by running an external program for every
+// signature, so in-house HSMs and signing services can be plugged in without
+// changes to flickr. Each signature the program returns is checked against
+// the key's address.
+type ExecSigner struct {
+	command   string
+	args      []string
+	address   common.Address
+	publicKey *ecdsa.PublicKey
+}
+
+// NewExecSigner creates a signer running the configured program. Without a
+// configured address, the program is asked for it.
+func NewExecSigner(cfg ExecSignerConfig) (*ExecSigner, error) {
+	if cfg.Command == "" {
+		return nil, fmt.Errorf("exec signer command is required")
+	}
+
+	s := &ExecSigner{
+		command: cfg.Command,
+		args:    cfg.Args,
+		address: cfg.Address,
+	}
+	if s.address != (common.Address{}) {
+		return s, nil
+	}
+
+	resp, err := s.run(ExecRequest{Method: "address"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exec signer address: %w", err)
+	}
+	if resp.Address == nil || *resp.Address == (common.Address{}) {
+		return nil, fmt.Errorf("exec signer returned no address")
+	}
+	s.address = *resp.Address
+	return s, nil
+}
+
+// Address returns the Ethereum address of the signer
+func (s *ExecSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTransaction signs a transaction with the signer program
+func (s *ExecSigner) SignTransaction(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	signer := types.NewLondonSigner(chainID)
+	sig, err := s.sign(signer.Hash(tx).Bytes(), ExecRequest{
+		Kind:        ExecKindTransaction,
+		ChainID:     (*hexutil.Big)(chainID),
+		Transaction: raw,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	// WithSignature expects V as 0/1
+	sig[64] -= 27
+	return tx.WithSignature(signer, sig)
+}
+
+// SignMessage signs a message using EIP-191 with the signer program
+func (s *ExecSigner) SignMessage(msg []byte) ([]byte, error) {
+	sig, err := s.sign(accounts.TextHash(msg), ExecRequest{
+		Kind:    ExecKindMessage,
+		Message: msg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+	return sig, nil
+}
+
+// SignTypedData signs EIP-712 typed data with the signer program
+func (s *ExecSigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+
+	sig, err := s.sign(hash, ExecRequest{
+		Kind:      ExecKindTypedData,
+		TypedData: &data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+	return sig, nil
+}
+
+// PublicKey returns the public key, or nil if nothing has been signed yet
+func (s *ExecSigner) PublicKey() *ecdsa.PublicKey {
+	return s.publicKey
+}
+
+// sign asks the program to sign hash and checks the signature was made with
+// the signer's key. The returned signature is low-s (EIP-2), with V as 27/28.
+func (s *ExecSigner) sign(hash []byte, req ExecRequest) ([]byte, error) {
+	req.Method = "sign"
+	req.Address = &s.address
+	req.Hash = hash
+
+	resp, err := s.run(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := []byte(resp.Signature)
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("exec signer returned a %d-byte signature, expected 65", len(sig))
+	}
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+	if sig[64] != 27 && sig[64] != 28 {
+		return nil, fmt.Errorf("invalid signature from exec signer: recovery ID %d", sig[64])
+	}
+
+	// Ethereum only accepts low-s signatures; negating S flips the recovery ID
+	if v := new(big.Int).SetBytes(sig[32:64]); v.Cmp(secp256k1HalfN) > 0 {
+		v.Sub(secp256k1N, v).FillBytes(sig[32:64])
+		sig[64] = 55 - sig[64]
+	}
+
+	// Recovery expects V as 0/1
+	recoverable := make([]byte, 65)
+	copy(recoverable, sig)
+	recoverable[64] -= 27
+	pub, err := crypto.SigToPub(hash, recoverable)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from exec signer: %w", err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != s.address {
+		return nil, fmt.Errorf("exec signer signed with %s, expected %s", signer.Hex(), s.address.Hex())
+	}
+	if s.publicKey == nil {
+		s.publicKey = pub
+	}
+
+	return sig, nil
+}
+
+// run runs the program with req on stdin and decodes its response
+func (s *ExecSigner) run(req ExecRequest) (*ExecResponse, error) {
+	req.Version = ExecProtocolVersion
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.command, s.args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("exec signer %s failed: %w: %s", s.command, err, msg)
+		}
+		return nil, fmt.Errorf("exec signer %s failed: %w", s.command, err)
+	}
+
+	var resp ExecResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode exec signer response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("exec signer error: %s", resp.Error)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,163 @@
+package signer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/signer"
+)
+
+// TestExecSignerHelper is the signer program run by the exec signer tests.
+// It signs with the key in FLICKR_TEST_EXEC_SIGNER_KEY.
+func TestExecSignerHelper(t *testing.T) {
+	if os.Getenv("FLICKR_TEST_EXEC_SIGNER") != "1" {
+		return
+	}
+
+	respond := func(resp signer.ExecResponse) {
+		json.NewEncoder(os.Stdout).Encode(resp)
+		os.Exit(0)
+	}
+
+	switch os.Getenv("FLICKR_TEST_EXEC_SIGNER_MODE") {
+	case "error":
+		respond(signer.ExecResponse{Error: "request denied by operator"})
+	case "crash":
+		fmt.Fprintln(os.Stderr, "HSM unavailable")
+		os.Exit(3)
+	}
+
+	key, err := crypto.HexToECDSA(os.Getenv("FLICKR_TEST_EXEC_SIGNER_KEY"))
+	if err != nil {
+		respond(signer.ExecResponse{Error: err.Error()})
+	}
+
+	var req signer.ExecRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil || req.Version != signer.ExecProtocolVersion {
+		respond(signer.ExecResponse{Error: "bad request"})
+	}
+
+	switch req.Method {
+	case "address":
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		respond(signer.ExecResponse{Address: &address})
+	case "sign":
+		// Check the hash matches the payload where that's cheap to do
+		if req.Kind == signer.ExecKindMessage && !bytes.Equal(accounts.TextHash(req.Message), req.Hash) {
+			respond(signer.ExecResponse{Error: "hash does not match message"})
+		}
+		sig, err := crypto.Sign(req.Hash, key)
+		if err != nil {
+			respond(signer.ExecResponse{Error: err.Error()})
+		}
+		if os.Getenv("FLICKR_TEST_EXEC_SIGNER_MODE") == "high-s" {
+			// The same signature with S negated, as some HSMs return
+			n := crypto.S256().Params().N
+			new(big.Int).Sub(n, new(big.Int).SetBytes(sig[32:64])).FillBytes(sig[32:64])
+			sig[64] ^= 1
+		}
+		respond(signer.ExecResponse{Signature: sig})
+	}
+	respond(signer.ExecResponse{Error: "unknown method " + req.Method})
+}
+
+// execSignerConfig runs the test binary as the signer program
+func execSignerConfig(t *testing.T, keyHex, mode string) signer.ExecSignerConfig {
+	t.Helper()
+	t.Setenv("FLICKR_TEST_EXEC_SIGNER", "1")
+	t.Setenv("FLICKR_TEST_EXEC_SIGNER_KEY", keyHex)
+	t.Setenv("FLICKR_TEST_EXEC_SIGNER_MODE", mode)
+	return signer.ExecSignerConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestExecSignerHelper$"},
+	}
+}
+
+func TestExecSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keyHex := hexutil.Encode(crypto.FromECDSA(key))[2:]
+
+	sig, err := signer.NewExecSigner(execSignerConfig(t, keyHex, ""))
+	require.NoError(t, err)
+	assert.Nil(t, sig.PublicKey())
+
+	checkKMSSigner(t, sig, key)
+	assert.Equal(t, key.PublicKey, *sig.PublicKey())
+}
+
+func TestExecSigner_HighS(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keyHex := hexutil.Encode(crypto.FromECDSA(key))[2:]
+
+	// High-s signatures are normalized rather than rejected by nodes
+	sig, err := signer.NewExecSigner(execSignerConfig(t, keyHex, "high-s"))
+	require.NoError(t, err)
+	checkKMSSigner(t, sig, key)
+}
+
+func TestExecSigner_Errors(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keyHex := hexutil.Encode(crypto.FromECDSA(key))[2:]
+
+	t.Run("Requires a command", func(t *testing.T) {
+		_, err := signer.NewExecSigner(signer.ExecSignerConfig{})
+		assert.ErrorContains(t, err, "command is required")
+	})
+
+	t.Run("Reports program errors", func(t *testing.T) {
+		cfg := execSignerConfig(t, keyHex, "error")
+		_, err := signer.NewExecSigner(cfg)
+		assert.ErrorContains(t, err, "request denied by operator")
+	})
+
+	t.Run("Reports program failures with stderr", func(t *testing.T) {
+		cfg := execSignerConfig(t, keyHex, "crash")
+		cfg.Address = crypto.PubkeyToAddress(key.PublicKey)
+		sig, err := signer.NewExecSigner(cfg)
+		require.NoError(t, err)
+
+		_, err = sig.SignMessage([]byte("hello"))
+		assert.ErrorContains(t, err, "HSM unavailable")
+	})
+
+	t.Run("Rejects signatures from another key", func(t *testing.T) {
+		cfg := execSignerConfig(t, keyHex, "")
+		cfg.Address = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		sig, err := signer.NewExecSigner(cfg)
+		require.NoError(t, err)
+
+		_, err = sig.SignMessage([]byte("hello"))
+		assert.ErrorContains(t, err, "expected 0x0000000000000000000000000000000000000001")
+	})
+}
+
+func TestFromContext_ExecSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	cfg := execSignerConfig(t, hexutil.Encode(crypto.FromECDSA(key))[2:], "")
+
+	sig, err := signer.FromContext(&config.Context{
+		ExecSigner: &config.ExecSigner{Command: cfg.Command, Args: cfg.Args},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), sig.Address())
+
+	_, err = signer.FromContext(&config.Context{
+		ExecSigner: &config.ExecSigner{Command: cfg.Command, Address: "not-an-address"},
+	})
+	assert.ErrorContains(t, err, "invalid exec signer address")
+}
@@ -1,25 +1,116 @@
 package signer
 
 import (
+	"context"
 	"fmt"
+	"os"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/secrets"
+	"golang.org/x/term"
 )
 
-// FromContext creates a signer from the context configuration
+// FromContext creates a signer from the context configuration, with signer
+// material supplied at runtime by the environment taking precedence
 func FromContext(ctx *config.Context) (Signer, error) {
+	return FromContextWithOptions(ctx, Options{})
+}
+
+// FromContextWithOptions creates a signer from the context configuration and
+// the signer material in opts and the environment
+func FromContextWithOptions(ctx *config.Context, opts Options) (Signer, error) {
+	// A runtime private key must not silently replace a keystore, remote, KMS
+	// or exec signer, e.g. from a variable left over in the environment
+	if kind := keyHolder(ctx); kind != "" {
+		for _, s := range opts.runtimePrivateKeySources() {
+			if s.available() {
+				return nil, fmt.Errorf("%s supplies an ECDSA private key, but the context uses its %s; remove one of them", s.name, kind)
+			}
+		}
+	}
+
 	// Check for ECDSA private key
-	if ctx.ECDSAPrivateKey != "" {
-		return NewECDSASignerFromHex(ctx.ECDSAPrivateKey)
+	privateKey, source, err := resolveFirst(opts.privateKeySources(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve ECDSA private key: %w", err)
+	}
+	if privateKey != "" {
+		opts.report("ECDSA private key", source)
+		return NewECDSASignerFromHex(privateKey)
 	}
 
 	// Check for keystore
 	if ctx.KeystorePath != "" {
-		if ctx.KeystorePassword == "" {
-			return nil, fmt.Errorf("keystore password is required")
+		password, source, err := resolveFirst(opts.keystorePasswordSources(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve keystore password: %w", err)
+		}
+		if password == "" {
+			// Prompt rather than requiring the password on the command line
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				return nil, fmt.Errorf("keystore password is required: set --keystore-password-ref, %s or --keystore-password-file, or run interactively", EnvKeystorePassword)
+			}
+			p, err := secrets.ReadPassword(fmt.Sprintf("Password for %s: ", ctx.KeystorePath))
+			if err != nil {
+				return nil, err
+			}
+			password, source = string(p), "prompt"
+		}
+		opts.report("keystore password", source)
+		return NewKeystoreSigner(ctx.KeystorePath, password)
+	}
+
+	// Check for remote signer
+	if ctx.RemoteSigner != nil {
+		if !common.IsHexAddress(ctx.RemoteSigner.Address) {
+			return nil, fmt.Errorf("invalid remote signer address: %s", ctx.RemoteSigner.Address)
 		}
-		return NewKeystoreSigner(ctx.KeystorePath, ctx.KeystorePassword)
+		return NewRemoteSigner(RemoteSignerConfig{
+			URL:        ctx.RemoteSigner.URL,
+			Address:    common.HexToAddress(ctx.RemoteSigner.Address),
+			CACert:     ctx.RemoteSigner.CACert,
+			ClientCert: ctx.RemoteSigner.ClientCert,
+			ClientKey:  ctx.RemoteSigner.ClientKey,
+		})
+	}
+
+	// Check for KMS signer
+	if ctx.KMSSigner != nil {
+		return kmsSignerFromConfig(ctx.KMSSigner)
+	}
+
+	// Check for exec signer
+	if ctx.ExecSigner != nil {
+		cfg := ExecSignerConfig{Command: ctx.ExecSigner.Command, Args: ctx.ExecSigner.Args}
+		if ctx.ExecSigner.Address != "" {
+			if !common.IsHexAddress(ctx.ExecSigner.Address) {
+				return nil, fmt.Errorf("invalid exec signer address: %s", ctx.ExecSigner.Address)
+			}
+			cfg.Address = common.HexToAddress(ctx.ExecSigner.Address)
+		}
+		return NewExecSigner(cfg)
 	}
 
 	return nil, fmt.Errorf("no signer configured in context")
+}
+
+func kmsSignerFromConfig(cfg *config.KMSSigner) (Signer, error) {
+	c, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	var kms KMS
+	var err error
+	switch cfg.Provider {
+	case config.KMSProviderAWS:
+		kms, err = NewAWSKMS(c, AWSKMSConfig{KeyID: cfg.KeyID, Region: cfg.Region, Endpoint: cfg.Endpoint})
+	case config.KMSProviderGCP:
+		kms, err = NewGCPKMS(c, GCPKMSConfig{KeyName: cfg.KeyID, Endpoint: cfg.Endpoint})
+	default:
+		return nil, fmt.Errorf("unknown KMS provider: %s", cfg.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewKMSSigner(c, kms)
 }
\ No newline at end of file
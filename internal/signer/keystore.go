@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 // KeystoreSigner implements Signer using a keystore file
@@ -62,6 +63,15 @@ func (s *KeystoreSigner) SignMessage(msg []byte) ([]byte, error) {
 	return ecdsaSigner.SignMessage(msg)
 }
 
+// SignTypedData signs EIP-712 typed data
+func (s *KeystoreSigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	ecdsaSigner := &ECDSASigner{
+		privateKey: s.privateKey,
+		address:    s.address,
+	}
+	return ecdsaSigner.SignTypedData(data)
+}
+
 // PublicKey returns the public key
 func (s *KeystoreSigner) PublicKey() *ecdsa.PublicKey {
 	return &s.privateKey.PublicKey
@@ -0,0 +1,202 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// kmsTimeout bounds each call to the KMS
+const kmsTimeout = 30 * time.Second
+
+var (
+	// oidECPublicKey and oidSecp256k1 identify secp256k1 keys in a
+	// SubjectPublicKeyInfo (RFC 5480, SEC 2)
+	oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1   = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// KMS is the part of a cloud KMS API needed to sign with a secp256k1 key
+// that never leaves the KMS
+type KMS interface {
+	// PublicKey returns the DER-encoded SubjectPublicKeyInfo of the key
+	PublicKey(ctx context.Context) ([]byte, error)
+
+	// SignDigest signs a 32-byte digest as-is (no further hashing) and
+	// returns an ASN.1 DER-encoded ECDSA signature
+	SignDigest(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// KMSSigner implements Signer with a secp256k1 key held in a cloud KMS
+type KMSSigner struct {
+	kms       KMS
+	publicKey *ecdsa.PublicKey
+	address   common.Address
+}
+
+// NewKMSSigner creates a signer for a KMS key, deriving the address from the
+// key's public key
+func NewKMSSigner(ctx context.Context, kms KMS) (*KMSSigner, error) {
+	ctx, cancel := context.WithTimeout(ctx, kmsTimeout)
+	defer cancel()
+
+	der, err := kms.PublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KMS public key: %w", err)
+	}
+
+	publicKey, err := parseSecp256k1PublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KMSSigner{
+		kms:       kms,
+		publicKey: publicKey,
+		address:   crypto.PubkeyToAddress(*publicKey),
+	}, nil
+}
+
+// parseSecp256k1PublicKey parses a DER SubjectPublicKeyInfo holding a
+// secp256k1 key. crypto/x509 does not support the curve.
+func parseSecp256k1PublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	var spki struct {
+		Algorithm struct {
+			Algorithm  asn1.ObjectIdentifier
+			Parameters asn1.ObjectIdentifier
+		}
+		PublicKey asn1.BitString
+	}
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil {
+		return nil, fmt.Errorf("failed to parse KMS public key: %w", err)
+	} else if len(rest) > 0 {
+		return nil, fmt.Errorf("failed to parse KMS public key: trailing data")
+	}
+
+	if !spki.Algorithm.Algorithm.Equal(oidECPublicKey) || !spki.Algorithm.Parameters.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("KMS key is not a secp256k1 key (algorithm %v, curve %v)",
+			spki.Algorithm.Algorithm, spki.Algorithm.Parameters)
+	}
+
+	publicKey, err := crypto.UnmarshalPubkey(spki.PublicKey.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid KMS public key: %w", err)
+	}
+	return publicKey, nil
+}
+
+// Address returns the Ethereum address of the signer
+func (s *KMSSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTransaction signs a transaction
+func (s *KMSSigner) SignTransaction(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.NewLondonSigner(chainID)
+	sig, err := s.signHash(signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	signedTx, err := tx.WithSignature(signer, sig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// SignMessage signs a message using EIP-191
+func (s *KMSSigner) SignMessage(msg []byte) ([]byte, error) {
+	sig, err := s.signHash(accounts.TextHash(msg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	// Transform V from 0/1 to 27/28 according to Ethereum yellow paper
+	sig[64] += 27
+	return sig, nil
+}
+
+// SignTypedData signs EIP-712 typed data
+func (s *KMSSigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+
+	sig, err := s.signHash(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+
+	sig[64] += 27
+	return sig, nil
+}
+
+// PublicKey returns the public key
+func (s *KMSSigner) PublicKey() *ecdsa.PublicKey {
+	return s.publicKey
+}
+
+// signHash signs a 32-byte hash in the KMS and returns the signature in
+// Ethereum's [R || S || V] form with V as 0/1
+func (s *KMSSigner) signHash(hash []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	der, err := s.kms.SignDigest(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("KMS sign failed: %w", err)
+	}
+	return derToEthereumSignature(der, hash, s.publicKey)
+}
+
+// derToEthereumSignature converts an ASN.1 DER ECDSA signature to [R || S || V].
+// S is normalized to the lower half of the curve order (EIP-2), and V is found
+// by recovering the public key with each candidate recovery ID.
+func derToEthereumSignature(der, hash []byte, publicKey *ecdsa.PublicKey) ([]byte, error) {
+	var rs struct {
+		R, S *big.Int
+	}
+	if rest, err := asn1.Unmarshal(der, &rs); err != nil {
+		return nil, fmt.Errorf("failed to parse KMS signature: %w", err)
+	} else if len(rest) > 0 {
+		return nil, fmt.Errorf("failed to parse KMS signature: trailing data")
+	}
+	if rs.R.Sign() <= 0 || rs.S.Sign() <= 0 || rs.R.Cmp(secp256k1N) >= 0 || rs.S.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("invalid KMS signature values")
+	}
+
+	// Ethereum only accepts low-s signatures
+	s := rs.S
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+
+	sig := make([]byte, 65)
+	rs.R.FillBytes(sig[0:32])
+	s.FillBytes(sig[32:64])
+
+	expected := crypto.FromECDSAPub(publicKey)
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		recovered, err := crypto.Ecrecover(hash, sig)
+		if err == nil && bytes.Equal(recovered, expected) {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("KMS signature does not match the key's public key")
+}
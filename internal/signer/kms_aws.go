@@ -0,0 +1,73 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// AWSKMSConfig configures a signer backed by AWS KMS
+type AWSKMSConfig struct {
+	KeyID    string // Key ID, ARN or alias of an ECC_SECG_P256K1 key
+	Region   string // Optional; defaults to the AWS SDK's region resolution
+	Endpoint string // Optional; e.g. a local KMS emulator
+}
+
+// awsKMS implements KMS with AWS KMS. Credentials come from the standard AWS
+// SDK chain (environment, shared config, instance role).
+type awsKMS struct {
+	client *kms.Client
+	keyID  string
+}
+
+// NewAWSKMS returns a KMS for an AWS KMS key
+func NewAWSKMS(ctx context.Context, cfg AWSKMSConfig) (KMS, error) {
+	if cfg.KeyID == "" {
+		return nil, fmt.Errorf("AWS KMS key ID is required")
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if cfg.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	client := kms.NewFromConfig(awsCfg, func(o *kms.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+	})
+
+	return &awsKMS{client: client, keyID: cfg.KeyID}, nil
+}
+
+func (k *awsKMS) PublicKey(ctx context.Context) ([]byte, error) {
+	out, err := k.client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(k.keyID)})
+	if err != nil {
+		return nil, err
+	}
+	if out.KeySpec != kmstypes.KeySpecEccSecgP256k1 {
+		return nil, fmt.Errorf("AWS KMS key %s has key spec %s, expected %s", k.keyID, out.KeySpec, kmstypes.KeySpecEccSecgP256k1)
+	}
+	return out.PublicKey, nil
+}
+
+func (k *awsKMS) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	out, err := k.client.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(k.keyID),
+		Message:          digest,
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: kmstypes.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
@@ -0,0 +1,132 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcpKMSEndpoint = "https://cloudkms.googleapis.com"
+	gcpKMSScope    = "https://www.googleapis.com/auth/cloudkms"
+	gcpKMSAlgo     = "EC_SIGN_SECP256K1_SHA256"
+)
+
+// GCPKMSConfig configures a signer backed by Google Cloud KMS
+type GCPKMSConfig struct {
+	// KeyName is the full key version name:
+	// projects/P/locations/L/keyRings/R/cryptoKeys/K/cryptoKeyVersions/V
+	KeyName  string
+	Endpoint string // Optional; defaults to https://cloudkms.googleapis.com
+
+	// HTTPClient makes authenticated requests. Defaults to a client using
+	// Application Default Credentials.
+	HTTPClient *http.Client
+}
+
+// gcpKMS implements KMS with the Cloud KMS REST API
+type gcpKMS struct {
+	client   *http.Client
+	endpoint string
+	keyName  string
+}
+
+// NewGCPKMS returns a KMS for a Cloud KMS key version
+func NewGCPKMS(ctx context.Context, cfg GCPKMSConfig) (KMS, error) {
+	if !strings.Contains(cfg.KeyName, "/cryptoKeyVersions/") {
+		return nil, fmt.Errorf("GCP KMS key name must be a full key version name (projects/.../cryptoKeyVersions/N)")
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		c, err := google.DefaultClient(ctx, gcpKMSScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Google credentials: %w", err)
+		}
+		client = c
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = gcpKMSEndpoint
+	}
+
+	return &gcpKMS{
+		client:   client,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		keyName:  cfg.KeyName,
+	}, nil
+}
+
+func (k *gcpKMS) PublicKey(ctx context.Context) ([]byte, error) {
+	var out struct {
+		PEM       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := k.do(ctx, http.MethodGet, "/v1/"+k.keyName+"/publicKey", nil, &out); err != nil {
+		return nil, err
+	}
+	if out.Algorithm != gcpKMSAlgo {
+		return nil, fmt.Errorf("GCP KMS key uses algorithm %s, expected %s", out.Algorithm, gcpKMSAlgo)
+	}
+
+	block, _ := pem.Decode([]byte(out.PEM))
+	if block == nil {
+		return nil, fmt.Errorf("GCP KMS returned an invalid PEM public key")
+	}
+	return block.Bytes, nil
+}
+
+func (k *gcpKMS) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	in := map[string]interface{}{
+		"digest": map[string][]byte{"sha256": digest},
+	}
+	var out struct {
+		Signature []byte `json:"signature"`
+	}
+	if err := k.do(ctx, http.MethodPost, "/v1/"+k.keyName+":asymmetricSign", in, &out); err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
+
+func (k *gcpKMS) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, k.endpoint+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GCP KMS request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("GCP KMS returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode GCP KMS response: %w", err)
+	}
+	return nil
+}
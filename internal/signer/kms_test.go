@@ -0,0 +1,285 @@
+package signer_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/signer"
+)
+
+// fakeKMS holds a secp256k1 key and signs like a cloud KMS: DER-encoded
+// signatures without a recovery ID, with S in either half of the curve order
+type fakeKMS struct {
+	key *ecdsa.PrivateKey
+
+	// highS returns the equivalent high-s signature, as KMSs are free to do
+	highS bool
+}
+
+func (f *fakeKMS) PublicKey(ctx context.Context) ([]byte, error) {
+	return marshalSecp256k1SPKI(&f.key.PublicKey)
+}
+
+func (f *fakeKMS) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	sig, err := crypto.Sign(digest, f.key)
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if f.highS {
+		s.Sub(crypto.S256().Params().N, s)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func marshalSecp256k1SPKI(pub *ecdsa.PublicKey) ([]byte, error) {
+	type algorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.ObjectIdentifier
+	}
+	point := crypto.FromECDSAPub(pub)
+	return asn1.Marshal(struct {
+		Algorithm algorithm
+		PublicKey asn1.BitString
+	}{
+		Algorithm: algorithm{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.ObjectIdentifier{1, 3, 132, 0, 10},
+		},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: len(point) * 8},
+	})
+}
+
+// checkKMSSigner signs a transaction and a message and checks both recover to
+// the key's address with low-s signatures
+func checkKMSSigner(t *testing.T, sig signer.Signer, key *ecdsa.PrivateKey) {
+	t.Helper()
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	assert.Equal(t, address, sig.Address())
+
+	halfN := new(big.Int).Rsh(crypto.S256().Params().N, 1)
+
+	// Sign several times so both recovery IDs are exercised
+	for i := 0; i < 8; i++ {
+		tx := newDynamicFeeTx()
+		signed, err := sig.SignTransaction(tx, big.NewInt(17000))
+		require.NoError(t, err)
+
+		sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(17000)), signed)
+		require.NoError(t, err)
+		assert.Equal(t, address, sender)
+
+		_, _, s := signed.RawSignatureValues()
+		assert.True(t, s.Cmp(halfN) <= 0, "signature must be low-s")
+
+		msg := []byte{'m', 's', 'g', byte(i)}
+		signature, err := sig.SignMessage(msg)
+		require.NoError(t, err)
+		require.Len(t, signature, 65)
+		assert.Contains(t, []byte{27, 28}, signature[64])
+
+		signature[64] -= 27
+		pub, err := crypto.SigToPub(accounts.TextHash(msg), signature)
+		require.NoError(t, err)
+		assert.Equal(t, address, crypto.PubkeyToAddress(*pub))
+	}
+}
+
+func TestKMSSigner(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		highS bool
+	}{
+		{"Low-s signatures", false},
+		{"High-s signatures are normalized", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := crypto.GenerateKey()
+			require.NoError(t, err)
+
+			sig, err := signer.NewKMSSigner(context.Background(), &fakeKMS{key: key, highS: tt.highS})
+			require.NoError(t, err)
+			assert.Equal(t, key.PublicKey, *sig.PublicKey())
+
+			checkKMSSigner(t, sig, key)
+		})
+	}
+}
+
+func TestKMSSigner_Errors(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	t.Run("Rejects non-secp256k1 keys", func(t *testing.T) {
+		_, err := signer.NewKMSSigner(context.Background(), &badKMS{publicKey: []byte{0x30, 0x00}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects signatures from another key", func(t *testing.T) {
+		other, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		spki, err := marshalSecp256k1SPKI(&key.PublicKey)
+		require.NoError(t, err)
+
+		sig, err := signer.NewKMSSigner(context.Background(), &badKMS{publicKey: spki, signer: &fakeKMS{key: other}})
+		require.NoError(t, err)
+
+		_, err = sig.SignMessage([]byte("hello"))
+		assert.ErrorContains(t, err, "does not match")
+	})
+}
+
+// badKMS returns a fixed public key and signs with another fake
+type badKMS struct {
+	publicKey []byte
+	signer    *fakeKMS
+}
+
+func (b *badKMS) PublicKey(ctx context.Context) ([]byte, error) {
+	return b.publicKey, nil
+}
+
+func (b *badKMS) SignDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	return b.signer.SignDigest(ctx, digest)
+}
+
+// fakeAWSKMS serves the AWS KMS JSON API for GetPublicKey and Sign
+func fakeAWSKMS(t *testing.T, kms *fakeKMS) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+
+		var req struct {
+			KeyId            string
+			Message          []byte
+			MessageType      string
+			SigningAlgorithm string
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			spki, err := kms.PublicKey(r.Context())
+			require.NoError(t, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"KeyId":     req.KeyId,
+				"KeySpec":   "ECC_SECG_P256K1",
+				"PublicKey": spki,
+			})
+		case "TrentService.Sign":
+			assert.Equal(t, "DIGEST", req.MessageType)
+			assert.Equal(t, "ECDSA_SHA_256", req.SigningAlgorithm)
+			sig, err := kms.SignDigest(r.Context(), req.Message)
+			require.NoError(t, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"KeyId":            req.KeyId,
+				"Signature":        sig,
+				"SigningAlgorithm": req.SigningAlgorithm,
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"__type": "UnsupportedOperationException"})
+		}
+	}))
+}
+
+func TestAWSKMS(t *testing.T) {
+	// Static credentials; keep the SDK away from local AWS config
+	dir := t.TempDir()
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	server := fakeAWSKMS(t, &fakeKMS{key: key, highS: true})
+	defer server.Close()
+
+	// FromContext selects the KMS signer
+	sig, err := signer.FromContext(&config.Context{
+		KMSSigner: &config.KMSSigner{
+			Provider: config.KMSProviderAWS,
+			KeyID:    "alias/release",
+			Region:   "us-east-1",
+			Endpoint: server.URL,
+		},
+	})
+	require.NoError(t, err)
+
+	checkKMSSigner(t, sig, key)
+}
+
+// fakeGCPKMS serves the Cloud KMS REST API for getPublicKey and asymmetricSign
+func fakeGCPKMS(t *testing.T, keyName string, kms *fakeKMS) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/"+keyName+"/publicKey":
+			spki, err := kms.PublicKey(r.Context())
+			require.NoError(t, err)
+			json.NewEncoder(w).Encode(map[string]string{
+				"pem":       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki})),
+				"algorithm": "EC_SIGN_SECP256K1_SHA256",
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/"+keyName+":asymmetricSign":
+			var req struct {
+				Digest struct {
+					SHA256 []byte `json:"sha256"`
+				} `json:"digest"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			sig, err := kms.SignDigest(r.Context(), req.Digest.SHA256)
+			require.NoError(t, err)
+			json.NewEncoder(w).Encode(map[string][]byte{"signature": sig})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestGCPKMS(t *testing.T) {
+	const keyName = "projects/p/locations/global/keyRings/r/cryptoKeys/release/cryptoKeyVersions/1"
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	server := fakeGCPKMS(t, keyName, &fakeKMS{key: key})
+	defer server.Close()
+
+	kms, err := signer.NewGCPKMS(context.Background(), signer.GCPKMSConfig{
+		KeyName:    keyName,
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	})
+	require.NoError(t, err)
+
+	sig, err := signer.NewKMSSigner(context.Background(), kms)
+	require.NoError(t, err)
+
+	checkKMSSigner(t, sig, key)
+
+	t.Run("Rejects key names without a version", func(t *testing.T) {
+		_, err := signer.NewGCPKMS(context.Background(), signer.GCPKMSConfig{
+			KeyName:    strings.TrimSuffix(keyName, "/cryptoKeyVersions/1"),
+			HTTPClient: server.Client(),
+		})
+		assert.Error(t, err)
+	})
+}
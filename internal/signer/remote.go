@@ -0,0 +1,357 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// remoteTimeout bounds each request to the remote signer
+const remoteTimeout = 30 * time.Second
+
+// RemoteSignerConfig configures a remote signer
+type RemoteSignerConfig struct {
+	URL     string
+	Address common.Address
+
+	// Optional TLS settings
+	CACert     string // CA certificate (PEM) to verify the signer with
+	ClientCert string // Client certificate (PEM) for mutual TLS
+	ClientKey  string // Client private key (PEM) for mutual TLS
+}
+
+// RemoteSigner implements Signer by delegating to a remote signer that
+// speaks the Web3Signer eth1 API (eth_signTransaction and eth_sign JSON-RPC).
+// Keys never leave the remote signer; every signature it returns is checked
+// against the configured address.
+type RemoteSigner struct {
+	url       string
+	address   common.Address
+	publicKey *ecdsa.PublicKey
+	client    *http.Client
+	requestID atomic.Uint64
+}
+
+// NewRemoteSigner creates a remote signer and checks that it holds the key
+// for the configured address
+func NewRemoteSigner(cfg RemoteSignerConfig) (*RemoteSigner, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("remote signer URL is required")
+	}
+	if cfg.Address == (common.Address{}) {
+		return nil, fmt.Errorf("remote signer address is required")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.CACert != "" || cfg.ClientCert != "" || cfg.ClientKey != "" {
+		tlsConfig, err := remoteTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	s := &RemoteSigner{
+		url:     strings.TrimSuffix(cfg.URL, "/"),
+		address: cfg.Address,
+		client:  &http.Client{Transport: transport, Timeout: remoteTimeout},
+	}
+
+	if err := s.checkAccount(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func remoteTLSConfig(cfg RemoteSignerConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read remote signer CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, fmt.Errorf("remote signer client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load remote signer client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// checkAccount confirms the signer serves the configured address and fetches
+// its public key when the signer exposes it
+func (s *RemoteSigner) checkAccount() error {
+	var addresses []common.Address
+	if err := s.call("eth_accounts", &addresses); err != nil {
+		return fmt.Errorf("failed to list remote signer accounts: %w", err)
+	}
+
+	found := false
+	for _, addr := range addresses {
+		if addr == s.address {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("remote signer does not hold a key for %s", s.address.Hex())
+	}
+
+	// Web3Signer lists public keys on its REST API; other signers may not
+	keys, err := s.publicKeys()
+	if err != nil {
+		return nil
+	}
+	for _, key := range keys {
+		if crypto.PubkeyToAddress(*key) == s.address {
+			s.publicKey = key
+			break
+		}
+	}
+	return nil
+}
+
+func (s *RemoteSigner) publicKeys() ([]*ecdsa.PublicKey, error) {
+	resp, err := s.client.Get(s.url + "/api/v1/eth1/publicKeys")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var encoded []string
+	if err := json.NewDecoder(resp.Body).Decode(&encoded); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*ecdsa.PublicKey, 0, len(encoded))
+	for _, e := range encoded {
+		raw, err := hexutil.Decode(e)
+		if err != nil {
+			continue
+		}
+		// Web3Signer returns the 64-byte key without the 0x04 prefix
+		if len(raw) == 64 {
+			raw = append([]byte{4}, raw...)
+		}
+		key, err := crypto.UnmarshalPubkey(raw)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Address returns the Ethereum address of the signer
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// remoteTx is the transaction object accepted by eth_signTransaction
+type remoteTx struct {
+	From                 common.Address   `json:"from"`
+	To                   *common.Address  `json:"to,omitempty"`
+	Gas                  hexutil.Uint64   `json:"gas"`
+	GasPrice             *hexutil.Big     `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big     `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big     `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big     `json:"value"`
+	Data                 hexutil.Bytes    `json:"data"`
+	Nonce                hexutil.Uint64   `json:"nonce"`
+	ChainID              *hexutil.Big     `json:"chainId"`
+	AccessList           types.AccessList `json:"accessList,omitempty"`
+}
+
+// SignTransaction signs a transaction with the remote signer
+func (s *RemoteSigner) SignTransaction(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	req := remoteTx{
+		From:       s.address,
+		To:         tx.To(),
+		Gas:        hexutil.Uint64(tx.Gas()),
+		Value:      (*hexutil.Big)(tx.Value()),
+		Data:       tx.Data(),
+		Nonce:      hexutil.Uint64(tx.Nonce()),
+		ChainID:    (*hexutil.Big)(chainID),
+		AccessList: tx.AccessList(),
+	}
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		req.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		req.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		req.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d for remote signing", tx.Type())
+	}
+
+	var raw hexutil.Bytes
+	if err := s.call("eth_signTransaction", &raw, req); err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+
+	// Make sure the signer signed what was asked, with the expected key
+	signer := types.LatestSignerForChainID(chainID)
+	if signer.Hash(signed) != signer.Hash(tx) {
+		return nil, fmt.Errorf("remote signer returned a different transaction than requested")
+	}
+	sender, err := types.Sender(signer, signed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from remote signer: %w", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s, expected %s", sender.Hex(), s.address.Hex())
+	}
+
+	return signed, nil
+}
+
+// SignMessage signs a message using EIP-191 with the remote signer
+func (s *RemoteSigner) SignMessage(msg []byte) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := s.call("eth_sign", &sig, s.address, hexutil.Bytes(msg)); err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+	return s.checkSignature(accounts.TextHash(msg), sig)
+}
+
+// SignTypedData signs EIP-712 typed data with the remote signer
+func (s *RemoteSigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+
+	var sig hexutil.Bytes
+	if err := s.call("eth_signTypedData", &sig, s.address, data); err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+	return s.checkSignature(hash, sig)
+}
+
+// checkSignature normalizes a signature over hash returned by the remote
+// signer and checks that it was made with the configured key
+func (s *RemoteSigner) checkSignature(hash []byte, sig []byte) ([]byte, error) {
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("remote signer returned a %d-byte signature, expected 65", len(sig))
+	}
+
+	// Normalize V to 27/28 as ECDSASigner does
+	if sig[64] < 27 {
+		sig[64] += 27
+	}
+
+	// Recovery expects V as 0/1
+	recoverable := make([]byte, 65)
+	copy(recoverable, sig)
+	recoverable[64] -= 27
+	pub, err := crypto.SigToPub(hash, recoverable)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from remote signer: %w", err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s, expected %s", signer.Hex(), s.address.Hex())
+	}
+	if s.publicKey == nil {
+		s.publicKey = pub
+	}
+
+	return sig, nil
+}
+
+// PublicKey returns the public key, or nil if the remote signer doesn't
+// expose it and nothing has been signed yet
+func (s *RemoteSigner) PublicKey() *ecdsa.PublicKey {
+	return s.publicKey
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// call performs a JSON-RPC call against the remote signer
+func (s *RemoteSigner) call(method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      s.requestID.Add(1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer returned %s", resp.Status)
+	}
+
+	var rpcResp rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("failed to decode remote signer response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("remote signer error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
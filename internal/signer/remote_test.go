@@ -0,0 +1,241 @@
+package signer_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/signer"
+)
+
+// fakeWeb3Signer is a local stand-in for Web3Signer's eth1 API
+type fakeWeb3Signer struct {
+	key *ecdsa.PrivateKey
+
+	// tamper modifies the transaction before signing, to simulate a
+	// misbehaving signer
+	tamper func(tx *types.DynamicFeeTx)
+}
+
+func (f *fakeWeb3Signer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == "/api/v1/eth1/publicKeys" {
+		pub := crypto.FromECDSAPub(&f.key.PublicKey)
+		json.NewEncoder(w).Encode([]string{hexutil.Encode(pub[1:])})
+		return
+	}
+
+	var req struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := f.handle(req.Method, req.Params)
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if err != nil {
+		resp["error"] = map[string]interface{}{"code": -32000, "message": err.Error()}
+	} else {
+		resp["result"] = result
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (f *fakeWeb3Signer) handle(method string, params []json.RawMessage) (interface{}, error) {
+	switch method {
+	case "eth_accounts":
+		return []common.Address{crypto.PubkeyToAddress(f.key.PublicKey)}, nil
+
+	case "eth_sign":
+		var data hexutil.Bytes
+		if err := json.Unmarshal(params[1], &data); err != nil {
+			return nil, err
+		}
+		sig, err := crypto.Sign(accounts.TextHash(data), f.key)
+		if err != nil {
+			return nil, err
+		}
+		sig[64] += 27
+		return hexutil.Bytes(sig), nil
+
+	case "eth_signTypedData":
+		var data apitypes.TypedData
+		if err := json.Unmarshal(params[1], &data); err != nil {
+			return nil, err
+		}
+		hash, _, err := apitypes.TypedDataAndHash(data)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := crypto.Sign(hash, f.key)
+		if err != nil {
+			return nil, err
+		}
+		sig[64] += 27
+		return hexutil.Bytes(sig), nil
+
+	case "eth_signTransaction":
+		var args struct {
+			To                   *common.Address `json:"to"`
+			Gas                  hexutil.Uint64  `json:"gas"`
+			MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+			MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+			Value                *hexutil.Big    `json:"value"`
+			Data                 hexutil.Bytes   `json:"data"`
+			Nonce                hexutil.Uint64  `json:"nonce"`
+			ChainID              *hexutil.Big    `json:"chainId"`
+		}
+		if err := json.Unmarshal(params[0], &args); err != nil {
+			return nil, err
+		}
+		inner := &types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     args.Value.ToInt(),
+			Data:      args.Data,
+		}
+		if f.tamper != nil {
+			f.tamper(inner)
+		}
+		signed, err := types.SignNewTx(f.key, types.LatestSignerForChainID(inner.ChainID), inner)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return hexutil.Bytes(raw), nil
+	}
+
+	return nil, http.ErrNotSupported
+}
+
+func newDynamicFeeTx() *types.Transaction {
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(17000),
+		Nonce:     7,
+		GasTipCap: big.NewInt(1_000_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1000),
+		Data:      []byte{0xca, 0xfe},
+	})
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	fake := &fakeWeb3Signer{key: key}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	sig, err := signer.NewRemoteSigner(signer.RemoteSignerConfig{URL: server.URL, Address: address})
+	require.NoError(t, err)
+	assert.Equal(t, address, sig.Address())
+	require.NotNil(t, sig.PublicKey())
+	assert.Equal(t, address, crypto.PubkeyToAddress(*sig.PublicKey()))
+
+	t.Run("Sign transaction", func(t *testing.T) {
+		tx := newDynamicFeeTx()
+		signed, err := sig.SignTransaction(tx, big.NewInt(17000))
+		require.NoError(t, err)
+
+		sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(17000)), signed)
+		require.NoError(t, err)
+		assert.Equal(t, address, sender)
+		assert.Equal(t, tx.Nonce(), signed.Nonce())
+		assert.Equal(t, tx.Data(), signed.Data())
+	})
+
+	t.Run("Sign message", func(t *testing.T) {
+		msg := []byte("Hello, Flickr!")
+		signature, err := sig.SignMessage(msg)
+		require.NoError(t, err)
+		require.Len(t, signature, 65)
+		assert.Contains(t, []byte{27, 28}, signature[64])
+
+		signature[64] -= 27
+		pub, err := crypto.SigToPub(accounts.TextHash(msg), signature)
+		require.NoError(t, err)
+		assert.Equal(t, address, crypto.PubkeyToAddress(*pub))
+	})
+
+	t.Run("Rejects tampered transaction", func(t *testing.T) {
+		fake.tamper = func(tx *types.DynamicFeeTx) {
+			tx.To = &common.Address{0x01}
+		}
+		defer func() { fake.tamper = nil }()
+
+		_, err := sig.SignTransaction(newDynamicFeeTx(), big.NewInt(17000))
+		assert.ErrorContains(t, err, "different transaction")
+	})
+}
+
+func TestRemoteSigner_WrongKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(&fakeWeb3Signer{key: key})
+	defer server.Close()
+
+	_, err = signer.NewRemoteSigner(signer.RemoteSignerConfig{
+		URL:     server.URL,
+		Address: common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+	})
+	assert.ErrorContains(t, err, "does not hold a key")
+}
+
+func TestRemoteSigner_TLS(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	server := httptest.NewTLSServer(&fakeWeb3Signer{key: key})
+	defer server.Close()
+
+	// Without the CA the server's certificate is rejected
+	_, err = signer.NewRemoteSigner(signer.RemoteSignerConfig{URL: server.URL, Address: address})
+	assert.Error(t, err)
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caPath, caPEM, 0600))
+
+	// FromContext selects the remote signer
+	sig, err := signer.FromContext(&config.Context{
+		RemoteSigner: &config.RemoteSigner{
+			URL:     server.URL,
+			Address: address.Hex(),
+			CACert:  caPath,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, address, sig.Address())
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 // Signer interface for signing transactions and messages
@@ -19,6 +20,9 @@ type Signer interface {
 	// SignMessage signs a message using EIP-191
 	SignMessage(msg []byte) ([]byte, error)
 
+	// SignTypedData signs EIP-712 typed data
+	SignTypedData(data apitypes.TypedData) ([]byte, error)
+
 	// PublicKey returns the public key
 	PublicKey() *ecdsa.PublicKey
 }
\ No newline at end of file
@@ -76,6 +76,8 @@ func TestECDSASigner(t *testing.T) {
 }
 
 func TestFromContext(t *testing.T) {
+	t.Setenv("FLICKR_TEST_PRIVATE_KEY", "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	t.Setenv("FLICKR_TEST_INVALID_KEY", "invalid")
 	tests := []struct {
 		name        string
 		context     *config.Context
@@ -85,7 +87,7 @@ func TestFromContext(t *testing.T) {
 		{
 			name: "With ECDSA private key",
 			context: &config.Context{
-				ECDSAPrivateKey: "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+				ECDSAPrivateKeyRef: "env:FLICKR_TEST_PRIVATE_KEY",
 			},
 			expectError:  false,
 			expectedAddr: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
@@ -98,7 +100,7 @@ func TestFromContext(t *testing.T) {
 		{
 			name: "Invalid private key",
 			context: &config.Context{
-				ECDSAPrivateKey: "invalid",
+				ECDSAPrivateKeyRef: "env:FLICKR_TEST_INVALID_KEY",
 			},
 			expectError: true,
 		},
@@ -124,10 +126,13 @@ func TestSignerMutualExclusivity(t *testing.T) {
 	// Test that setting one signer type should clear the other
 	ctx := &config.Context{}
 
+	t.Setenv("FLICKR_TEST_PRIVATE_KEY", "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	t.Setenv("FLICKR_TEST_KEYSTORE_PASSWORD", "password")
+
 	// Set ECDSA private key
-	ctx.ECDSAPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	ctx.ECDSAPrivateKeyRef = "env:FLICKR_TEST_PRIVATE_KEY"
 	ctx.KeystorePath = ""
-	ctx.KeystorePassword = ""
+	ctx.KeystorePasswordRef = ""
 
 	sig, err := signer.FromContext(ctx)
 	require.NoError(t, err)
@@ -135,11 +140,25 @@ func TestSignerMutualExclusivity(t *testing.T) {
 
 	// Now set keystore (simulating context set command logic)
 	ctx.KeystorePath = "/path/to/keystore"
-	ctx.KeystorePassword = "password"
-	ctx.ECDSAPrivateKey = "" // Should be cleared
+	ctx.KeystorePasswordRef = "env:FLICKR_TEST_KEYSTORE_PASSWORD"
+	ctx.ECDSAPrivateKeyRef = "" // Should be cleared
 
 	// This will fail because the keystore doesn't exist, but it shows the logic
 	_, err = signer.FromContext(ctx)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "keystore")
+}
+
+func TestFromContext_SecretReference(t *testing.T) {
+	t.Setenv("FLICKR_TEST_PRIVATE_KEY", "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+
+	sig, err := signer.FromContext(&config.Context{ECDSAPrivateKeyRef: "env:FLICKR_TEST_PRIVATE_KEY"})
+	require.NoError(t, err)
+	assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", sig.Address().Hex())
+
+	_, err = signer.FromContext(&config.Context{ECDSAPrivateKeyRef: "env:FLICKR_TEST_UNSET"})
+	assert.ErrorContains(t, err, "failed to resolve ECDSA private key")
+
+	_, err = signer.FromContext(&config.Context{KeystorePath: "/path/to/keystore", KeystorePasswordRef: "env:FLICKR_TEST_UNSET"})
+	assert.ErrorContains(t, err, "failed to resolve keystore password")
 }
\ No newline at end of file
@@ -0,0 +1,155 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/secrets"
+)
+
+// Environment variables that supply signer material at runtime, so CI jobs
+// and containers don't need it written to the config file
+const (
+	EnvPrivateKey           = "FLICKR_PRIVATE_KEY"
+	EnvPrivateKeyFile       = "FLICKR_PRIVATE_KEY_FILE"
+	EnvKeystorePassword     = "FLICKR_KEYSTORE_PASSWORD"
+	EnvKeystorePasswordFile = "FLICKR_KEYSTORE_PASSWORD_FILE"
+)
+
+// Names of the secret files looked up in Options.SecretsDir
+const (
+	privateKeySecret       = "flickr_private_key"
+	keystorePasswordSecret = "flickr_keystore_password"
+)
+
+// DefaultSecretsDir is where Docker and Kubernetes mount secret files
+const DefaultSecretsDir = "/run/secrets"
+
+// Options supplies signer material at runtime. It takes precedence over the
+// values stored in the context.
+type Options struct {
+	// KeystorePasswordFile is a file holding the keystore password, e.g.
+	// from --keystore-password-file
+	KeystorePasswordFile string
+
+	// SecretsDir is where mounted secret files are looked up. Defaults to
+	// DefaultSecretsDir.
+	SecretsDir string
+
+	// Report, if set, is told which source each piece of signer material
+	// came from. It is never given the value.
+	Report func(material, source string)
+}
+
+func (o Options) report(material, source string) {
+	if o.Report != nil {
+		o.Report(material, source)
+	}
+}
+
+// source is one place a piece of signer material may come from
+type source struct {
+	name string // Describes the source in diagnostics; never the value
+	ref  string // Secret reference to resolve
+
+	// mounted sources are skipped when the file does not exist
+	mounted bool
+}
+
+// runtimePrivateKeySources returns where an ECDSA private key supplied at
+// runtime is looked up, in order of precedence
+func (o Options) runtimePrivateKeySources() []source {
+	var sources []source
+	if os.Getenv(EnvPrivateKey) != "" {
+		sources = append(sources, source{name: "environment variable " + EnvPrivateKey, ref: "env:" + EnvPrivateKey})
+	}
+	if path := os.Getenv(EnvPrivateKeyFile); path != "" {
+		sources = append(sources, source{name: "file " + path + " (" + EnvPrivateKeyFile + ")", ref: "file:" + path})
+	}
+	return append(sources, o.mountedSource(privateKeySecret))
+}
+
+// privateKeySources returns where the ECDSA private key is looked up, in
+// order of precedence
+func (o Options) privateKeySources(ctx *config.Context) []source {
+	sources := o.runtimePrivateKeySources()
+	if ctx.ECDSAPrivateKeyRef != "" {
+		sources = append(sources, source{name: "context reference " + ctx.ECDSAPrivateKeyRef, ref: ctx.ECDSAPrivateKeyRef})
+	}
+	return sources
+}
+
+// keystorePasswordSources returns where the keystore password is looked up,
+// in order of precedence
+func (o Options) keystorePasswordSources(ctx *config.Context) []source {
+	var sources []source
+	if o.KeystorePasswordFile != "" {
+		sources = append(sources, source{name: "file " + o.KeystorePasswordFile + " (--keystore-password-file)", ref: "file:" + o.KeystorePasswordFile})
+	}
+	if os.Getenv(EnvKeystorePassword) != "" {
+		sources = append(sources, source{name: "environment variable " + EnvKeystorePassword, ref: "env:" + EnvKeystorePassword})
+	}
+	if path := os.Getenv(EnvKeystorePasswordFile); path != "" {
+		sources = append(sources, source{name: "file " + path + " (" + EnvKeystorePasswordFile + ")", ref: "file:" + path})
+	}
+	sources = append(sources, o.mountedSource(keystorePasswordSecret))
+	if ctx.KeystorePasswordRef != "" {
+		sources = append(sources, source{name: "context reference " + ctx.KeystorePasswordRef, ref: ctx.KeystorePasswordRef})
+	}
+	return sources
+}
+
+// mountedSource returns the secret file name in the secrets directory
+func (o Options) mountedSource(name string) source {
+	dir := o.SecretsDir
+	if dir == "" {
+		dir = DefaultSecretsDir
+	}
+	path := filepath.Join(dir, name)
+	return source{name: "secret file " + path, ref: "file:" + path, mounted: true}
+}
+
+// available reports whether the source may supply a value. Only a mounted
+// source whose file does not exist is unavailable.
+func (s source) available() bool {
+	if s.mounted {
+		_, err := os.Stat(s.ref[len("file:"):])
+		return !errors.Is(err, os.ErrNotExist)
+	}
+	return true
+}
+
+// keyHolder describes the keystore, remote, KMS or exec signer configured in
+// ctx, or returns an empty string if there is none
+func keyHolder(ctx *config.Context) string {
+	switch {
+	case ctx.KeystorePath != "":
+		return "keystore " + ctx.KeystorePath
+	case ctx.RemoteSigner != nil:
+		return "remote signer"
+	case ctx.KMSSigner != nil:
+		return "KMS signer"
+	case ctx.ExecSigner != nil:
+		return "exec signer"
+	}
+	return ""
+}
+
+// resolveFirst returns the value of the first available source and its
+// name, or empty strings if none is available
+func resolveFirst(sources []source) (string, string, error) {
+	for _, s := range sources {
+		if !s.available() {
+			continue
+		}
+		value, err := secrets.Resolve(s.ref)
+		if err != nil {
+			return "", "", fmt.Errorf("%s: %w", s.name, err)
+		}
+		return value, s.name, nil
+	}
+	return "", "", nil
+}
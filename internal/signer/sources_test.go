@@ -0,0 +1,214 @@
+package signer_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/config"
+	"github.com/yourorg/flickr/internal/signer"
+)
+
+const (
+	contextKey     = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	contextAddress = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	runtimeKey     = "0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+	runtimeAddress = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+)
+
+func writeSecret(t *testing.T, dir, name, value string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0600))
+	return path
+}
+
+func TestFromContextWithOptions_PrivateKey(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name           string
+		env            map[string]string
+		secretsDir     string
+		expectedAddr   string
+		expectedSource string
+	}{
+		{
+			name:           "Context",
+			expectedAddr:   contextAddress,
+			expectedSource: "context reference env:FLICKR_TEST_CONTEXT_KEY",
+		},
+		{
+			name:           "Environment variable overrides context",
+			env:            map[string]string{signer.EnvPrivateKey: runtimeKey},
+			expectedAddr:   runtimeAddress,
+			expectedSource: "environment variable FLICKR_PRIVATE_KEY",
+		},
+		{
+			name:           "File from environment",
+			env:            map[string]string{signer.EnvPrivateKeyFile: writeSecret(t, dir, "key", runtimeKey)},
+			expectedAddr:   runtimeAddress,
+			expectedSource: "FLICKR_PRIVATE_KEY_FILE",
+		},
+		{
+			name:           "Mounted secret file",
+			secretsDir:     filepath.Dir(writeSecret(t, t.TempDir(), "flickr_private_key", runtimeKey)),
+			expectedAddr:   runtimeAddress,
+			expectedSource: "secret file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(signer.EnvPrivateKey, "")
+			t.Setenv(signer.EnvPrivateKeyFile, "")
+			t.Setenv("FLICKR_TEST_CONTEXT_KEY", contextKey)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var reported []string
+			sig, err := signer.FromContextWithOptions(&config.Context{ECDSAPrivateKeyRef: "env:FLICKR_TEST_CONTEXT_KEY"}, signer.Options{
+				SecretsDir: tt.secretsDir,
+				Report: func(material, source string) {
+					reported = append(reported, material+": "+source)
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAddr, sig.Address().Hex())
+
+			require.Len(t, reported, 1)
+			assert.Contains(t, reported[0], tt.expectedSource)
+			assert.NotContains(t, reported[0], strings.TrimPrefix(runtimeKey, "0x"))
+		})
+	}
+
+	t.Run("Missing file is an error", func(t *testing.T) {
+		t.Setenv(signer.EnvPrivateKey, "")
+		t.Setenv(signer.EnvPrivateKeyFile, filepath.Join(dir, "missing"))
+		_, err := signer.FromContextWithOptions(&config.Context{ECDSAPrivateKeyRef: "file:" + filepath.Join(dir, "missing")}, signer.Options{SecretsDir: dir})
+		assert.ErrorContains(t, err, "FLICKR_PRIVATE_KEY_FILE")
+	})
+}
+
+func TestFromContextWithOptions_ExternalSigner(t *testing.T) {
+	ctx := &config.Context{ExecSigner: &config.ExecSigner{Command: "flickr-test-missing"}}
+
+	t.Run("Environment variable conflicts", func(t *testing.T) {
+		t.Setenv(signer.EnvPrivateKey, runtimeKey)
+		t.Setenv(signer.EnvPrivateKeyFile, "")
+		_, err := signer.FromContextWithOptions(ctx, signer.Options{SecretsDir: t.TempDir()})
+		assert.ErrorContains(t, err, "environment variable FLICKR_PRIVATE_KEY supplies an ECDSA private key, but the context uses its exec signer")
+	})
+
+	t.Run("Mounted secret file conflicts", func(t *testing.T) {
+		t.Setenv(signer.EnvPrivateKey, "")
+		t.Setenv(signer.EnvPrivateKeyFile, "")
+		dir := filepath.Dir(writeSecret(t, t.TempDir(), "flickr_private_key", runtimeKey))
+		_, err := signer.FromContextWithOptions(ctx, signer.Options{SecretsDir: dir})
+		assert.ErrorContains(t, err, "but the context uses its exec signer")
+	})
+
+	t.Run("Keystore conflicts", func(t *testing.T) {
+		t.Setenv(signer.EnvPrivateKey, runtimeKey)
+		t.Setenv(signer.EnvPrivateKeyFile, "")
+		_, err := signer.FromContextWithOptions(&config.Context{KeystorePath: "/path/to/keystore"}, signer.Options{SecretsDir: t.TempDir()})
+		assert.ErrorContains(t, err, "environment variable FLICKR_PRIVATE_KEY supplies an ECDSA private key, but the context uses its keystore /path/to/keystore")
+	})
+
+	t.Run("No runtime key uses the configured signer", func(t *testing.T) {
+		t.Setenv(signer.EnvPrivateKey, "")
+		t.Setenv(signer.EnvPrivateKeyFile, "")
+		_, err := signer.FromContextWithOptions(ctx, signer.Options{SecretsDir: t.TempDir()})
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "supplies an ECDSA private key")
+	})
+}
+
+func TestFromContextWithOptions_KeystorePassword(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(privateKey, "hunter2")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	passwordFile := writeSecret(t, dir, "password", "hunter2")
+	wrongFile := writeSecret(t, dir, "wrong", "wrong")
+
+	tests := []struct {
+		name           string
+		env            map[string]string
+		opts           signer.Options
+		context        config.Context
+		expectedSource string
+		expectError    bool
+	}{
+		{
+			name:           "Context",
+			context:        config.Context{KeystorePasswordRef: "file:" + passwordFile},
+			expectedSource: "context reference file:",
+		},
+		{
+			name:           "Environment variable overrides context",
+			env:            map[string]string{signer.EnvKeystorePassword: "hunter2"},
+			context:        config.Context{KeystorePasswordRef: "file:" + wrongFile},
+			expectedSource: "environment variable FLICKR_KEYSTORE_PASSWORD",
+		},
+		{
+			name:           "File from environment",
+			env:            map[string]string{signer.EnvKeystorePasswordFile: passwordFile},
+			context:        config.Context{KeystorePasswordRef: "file:" + wrongFile},
+			expectedSource: "FLICKR_KEYSTORE_PASSWORD_FILE",
+		},
+		{
+			name:           "Flag overrides environment",
+			env:            map[string]string{signer.EnvKeystorePassword: "wrong"},
+			opts:           signer.Options{KeystorePasswordFile: passwordFile},
+			expectedSource: "--keystore-password-file",
+		},
+		{
+			name:        "Wrong password",
+			opts:        signer.Options{KeystorePasswordFile: wrongFile},
+			context:     config.Context{KeystorePasswordRef: "file:" + passwordFile},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(signer.EnvPrivateKey, "")
+			t.Setenv(signer.EnvPrivateKeyFile, "")
+			t.Setenv(signer.EnvKeystorePassword, "")
+			t.Setenv(signer.EnvKeystorePasswordFile, "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var source string
+			opts := tt.opts
+			opts.SecretsDir = dir
+			opts.Report = func(material, s string) {
+				assert.Equal(t, "keystore password", material)
+				source = s
+			}
+
+			ctx := tt.context
+			ctx.KeystorePath = account.URL.Path
+			sig, err := signer.FromContextWithOptions(&ctx, opts)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, account.Address, sig.Address())
+			assert.Contains(t, source, tt.expectedSource)
+			assert.NotContains(t, source, "hunter2")
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package signer_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourorg/flickr/internal/signer"
+)
+
+// mailTypedData is the example from the EIP-712 specification
+const mailTypedData = `{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "version", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "verifyingContract", "type": "address"}
+    ],
+    "Person": [
+      {"name": "name", "type": "string"},
+      {"name": "wallet", "type": "address"}
+    ],
+    "Mail": [
+      {"name": "from", "type": "Person"},
+      {"name": "to", "type": "Person"},
+      {"name": "contents", "type": "string"}
+    ]
+  },
+  "primaryType": "Mail",
+  "domain": {
+    "name": "Ether Mail",
+    "version": "1",
+    "chainId": 1,
+    "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+  },
+  "message": {
+    "from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+    "to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+    "contents": "Hello, Bob!"
+  }
+}`
+
+// The specification's signature of mailTypedData with keccak256("cow")
+const mailSignature = "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+	"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c"
+
+func loadMailTypedData(t *testing.T) apitypes.TypedData {
+	t.Helper()
+	var data apitypes.TypedData
+	require.NoError(t, json.Unmarshal([]byte(mailTypedData), &data))
+	return data
+}
+
+func TestSignTypedData(t *testing.T) {
+	key := crypto.Keccak256([]byte("cow"))
+	privateKey, err := crypto.ToECDSA(key)
+	require.NoError(t, err)
+	data := loadMailTypedData(t)
+
+	ecdsaSigner := signer.NewECDSASigner(privateKey)
+	assert.Equal(t, "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", ecdsaSigner.Address().Hex())
+
+	// Keystore signer
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(privateKey, "hunter2")
+	require.NoError(t, err)
+	keystoreSigner, err := signer.NewKeystoreSigner(account.URL.Path, "hunter2")
+	require.NoError(t, err)
+
+	// KMS signer
+	kmsSigner, err := signer.NewKMSSigner(context.Background(), &fakeKMS{key: privateKey, highS: true})
+	require.NoError(t, err)
+
+	// Remote signer
+	server := httptest.NewServer(&fakeWeb3Signer{key: privateKey})
+	defer server.Close()
+	remoteSigner, err := signer.NewRemoteSigner(signer.RemoteSignerConfig{URL: server.URL, Address: ecdsaSigner.Address()})
+	require.NoError(t, err)
+
+	// Exec signer
+	execSigner, err := signer.NewExecSigner(execSignerConfig(t, hexutil.Encode(key)[2:], ""))
+	require.NoError(t, err)
+
+	for name, sig := range map[string]signer.Signer{
+		"ECDSA":    ecdsaSigner,
+		"Keystore": keystoreSigner,
+		"KMS":      kmsSigner,
+		"Remote":   remoteSigner,
+		"Exec":     execSigner,
+	} {
+		t.Run(name, func(t *testing.T) {
+			signature, err := sig.SignTypedData(data)
+			require.NoError(t, err)
+			assert.Equal(t, mailSignature, hexutil.Encode(signature))
+		})
+	}
+
+	t.Run("Rejects invalid typed data", func(t *testing.T) {
+		invalid := loadMailTypedData(t)
+		invalid.PrimaryType = "Letter"
+		_, err := ecdsaSigner.SignTypedData(invalid)
+		assert.ErrorContains(t, err, "invalid typed data")
+	})
+}